@@ -2,25 +2,35 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/CaptShanks/terraprism/internal/history"
 	"github.com/CaptShanks/terraprism/internal/parser"
 	"github.com/CaptShanks/terraprism/internal/tui"
 	"github.com/CaptShanks/terraprism/internal/updater"
+	"github.com/CaptShanks/terraprism/internal/watch"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/term"
 )
 
 const version = "0.12.0"
 
 var (
 	printMode  = false
+	queryExpr  = ""
+	outputFmt  = "" // -o: json or ndjson
+	printOpts  tui.PrintOptions
+	colorMode  = tui.ColorAuto // --color for -p output
 	forceLight = false
 	forceDark  = false
 	useTofu    = false
@@ -57,6 +67,12 @@ func main() {
 		forceDark = true
 	}
 
+	if v := os.Getenv("TERRAPRISM_DIFF_ALGORITHM"); !tui.SetDiffAlgorithm(v) {
+		fmt.Fprintf(os.Stderr, "Warning: unknown TERRAPRISM_DIFF_ALGORITHM %q (want myers or patience), using myers\n", v)
+	}
+	loadSuppressRules()
+	loadSavedQueries()
+
 	// Apply color scheme
 	if forceLight {
 		tui.SetLightPalette()
@@ -102,6 +118,12 @@ func main() {
 	case "history":
 		runHistoryMode(args[1:])
 		return
+	case "compare":
+		runCompareMode(args[1:])
+		return
+	case "export":
+		runExportMode(args[1:])
+		return
 	case "version":
 		runVersionMode()
 		return
@@ -112,6 +134,150 @@ func main() {
 	runViewMode(args)
 }
 
+// loadSuppressRules installs noise-suppression rules from
+// TERRAPRISM_SUPPRESS_FILE, or ~/.terraprism/suppress.yaml when it exists.
+func loadSuppressRules() {
+	path := os.Getenv("TERRAPRISM_SUPPRESS_FILE")
+	explicit := path != ""
+	if !explicit {
+		defaultPath, err := tui.DefaultSuppressRulesPath()
+		if err != nil {
+			return
+		}
+		path = defaultPath
+	}
+	rules, err := tui.LoadSuppressRules(path)
+	if err != nil {
+		if explicit || !errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring suppression rules: %v\n", err)
+		}
+		return
+	}
+	tui.SetSuppressRules(rules)
+}
+
+// loadSavedQueries installs named queries from TERRAPRISM_QUERIES_FILE, or
+// ~/.terraprism/queries.yaml when it exists.
+func loadSavedQueries() {
+	path := os.Getenv("TERRAPRISM_QUERIES_FILE")
+	explicit := path != ""
+	if !explicit {
+		defaultPath, err := tui.DefaultSavedQueriesPath()
+		if err != nil {
+			return
+		}
+		path = defaultPath
+	}
+	queries, err := tui.LoadSavedQueries(path)
+	if err != nil {
+		if explicit || !errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring saved queries: %v\n", err)
+		}
+		return
+	}
+	tui.SetSavedQueries(queries)
+}
+
+// printPlan prints the plan in print mode, narrowed by --query when given.
+func printPlan(plan *parser.Plan) {
+	if queryExpr != "" {
+		filtered, err := tui.FilterPlan(plan, queryExpr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --query: %v\n", err)
+			os.Exit(1)
+		}
+		plan = filtered
+	}
+	if printOpts.Width == 0 {
+		if fd := os.Stdout.Fd(); term.IsTerminal(fd) {
+			if width, _, err := term.GetSize(fd); err == nil {
+				printOpts.Width = width
+			}
+		}
+	}
+	tui.UseColorMode(colorMode)
+	tui.PrintPlan(plan, printOpts)
+}
+
+// isPrintOption reports whether flag takes a value that shapes print mode.
+func isPrintOption(flag string) bool {
+	switch flag {
+	case "--filter", "--sort", "--context", "--width", "--color":
+		return true
+	}
+	return false
+}
+
+// setPrintOption parses a print mode option, exiting on invalid values.
+func setPrintOption(flag, value string) {
+	var err error
+	switch flag {
+	case "--filter":
+		printOpts.Filters, err = tui.ParseActionFilter(value)
+	case "--sort":
+		printOpts.Sort, err = tui.ParseSortOrder(value)
+	case "--context":
+		var context int
+		context, err = strconv.Atoi(value)
+		if err == nil && context < 0 {
+			err = fmt.Errorf("must not be negative")
+		}
+		printOpts.Context = &context
+	case "--width":
+		printOpts.Width, err = strconv.Atoi(value)
+		if err == nil && printOpts.Width < 20 {
+			err = fmt.Errorf("must be at least 20")
+		}
+	case "--color":
+		colorMode, err = tui.ParseColorMode(value)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid %s %q: %v\n", flag, value, err)
+		os.Exit(1)
+	}
+}
+
+// flagValue returns the value following the flag at args[i], exiting when it
+// is missing.
+func flagValue(args []string, i int) string {
+	if i+1 >= len(args) {
+		fmt.Fprintf(os.Stderr, "Error: %s needs a value\n", args[i])
+		os.Exit(1)
+	}
+	return args[i+1]
+}
+
+// writePlanJSON writes the plan as JSON or NDJSON (-o), narrowed by --query
+// when given.
+func writePlanJSON(plan *parser.Plan) {
+	if queryExpr != "" {
+		filtered, err := tui.FilterPlan(plan, queryExpr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --query: %v\n", err)
+			os.Exit(1)
+		}
+		plan = filtered
+	}
+	write := parser.WriteJSON
+	if outputFmt == "ndjson" {
+		write = parser.WriteNDJSON
+	}
+	if err := write(os.Stdout, plan); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", outputFmt, err)
+		os.Exit(1)
+	}
+}
+
+// newViewModel creates the view-mode TUI model with --query applied.
+func newViewModel(plan *parser.Plan) tui.Model {
+	m, err := tui.NewModel(plan, version).WithQuery(queryExpr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid --query: %v\n", err)
+		os.Exit(1)
+	}
+	return m
+}
+
 func parseApplyArgs(args []string) []string {
 	var tfArgs []string
 	for i := 0; i < len(args); i++ {
@@ -129,6 +295,27 @@ func parseApplyArgs(args []string) []string {
 	return tfArgs
 }
 
+// splitChdir removes a -chdir=DIR argument from tfArgs. Terraform only
+// accepts -chdir before the subcommand, so withChdir puts it back there.
+func splitChdir(tfArgs []string) (dir string, rest []string) {
+	for _, arg := range tfArgs {
+		if value, ok := strings.CutPrefix(arg, "-chdir="); ok {
+			dir = value
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return dir, rest
+}
+
+// withChdir prefixes a terraform command line with -chdir=dir when set.
+func withChdir(dir string, args ...string) []string {
+	if dir == "" {
+		return args
+	}
+	return append([]string{"-chdir=" + dir}, args...)
+}
+
 func ensureDestroyFlag(tfArgs []string) []string {
 	for _, arg := range tfArgs {
 		if arg == "-destroy" {
@@ -138,11 +325,11 @@ func ensureDestroyFlag(tfArgs []string) []string {
 	return append([]string{"-destroy"}, tfArgs...)
 }
 
-func runApplyExecute(tfCmd, planFile, historyPath string) error {
+func runApplyExecute(tfCmd, chdir, planFile, historyPath string) error {
 	if historyPath != "" {
 		_ = history.AppendToHistoryFile(historyPath, "\n\n--- APPLY OUTPUT ---\n\n")
 	}
-	applyCmd := exec.Command(tfCmd, "apply", planFile)
+	applyCmd := exec.Command(tfCmd, withChdir(chdir, "apply", planFile)...)
 	applyCmd.Stdout = os.Stdout
 	applyCmd.Stderr = os.Stderr
 	applyCmd.Stdin = os.Stdin
@@ -178,8 +365,9 @@ func runApplyMode(args []string, isDestroy bool) {
 	defer os.Remove(planFile)
 
 	fmt.Printf("Terra-Prism: Running %s plan... ", tfCmd)
-	planArgs := append([]string{"plan", "-out=" + planFile, "-no-color"}, tfArgs...)
-	output, err := exec.Command(tfCmd, planArgs...).CombinedOutput()
+	chdir, planFlags := splitChdir(tfArgs)
+	planArgs := append([]string{"plan", "-out=" + planFile, "-no-color"}, planFlags...)
+	output, err := exec.Command(tfCmd, withChdir(chdir, planArgs...)...).CombinedOutput()
 	if err != nil {
 		fmt.Println("FAILED")
 		fmt.Fprintf(os.Stderr, "\n%s plan failed:\n%s\n", tfCmd, string(output))
@@ -209,7 +397,7 @@ func runApplyMode(args []string, isDestroy bool) {
 		os.Exit(0)
 	}
 
-	model := tui.NewModelWithApply(plan, planFile, tfCmd, version)
+	model := tui.NewModelWithApply(plan, planFile, tfCmd, version).WithSourceDir(chdir)
 	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
 	finalModel, err := p.Run()
 	if err != nil {
@@ -219,7 +407,7 @@ func runApplyMode(args []string, isDestroy bool) {
 
 	if m, ok := finalModel.(tui.Model); ok && m.ShouldApply() {
 		fmt.Printf("\nApplying plan with %s...\n\n", tfCmd)
-		applyErr := runApplyExecute(tfCmd, planFile, historyPath)
+		applyErr := runApplyExecute(tfCmd, chdir, planFile, historyPath)
 		if applyErr != nil {
 			fmt.Fprintf(os.Stderr, "\nApply failed: %v\n", applyErr)
 			updateHistoryApplyResult(historyPath, false, applyErr)
@@ -238,12 +426,15 @@ func runApplyMode(args []string, isDestroy bool) {
 // runPlanMode runs terraform/tofu plan and shows in TUI (read-only)
 func runPlanMode(args []string) {
 	var tfArgs []string
+	watchMode := false
 
 	for i := 0; i < len(args); i++ {
 		switch args[i] {
 		case "--help", "-h":
 			printUsage()
 			os.Exit(0)
+		case "--watch", "-w":
+			watchMode = true
 		case "--":
 			tfArgs = append(tfArgs, args[i+1:]...)
 			i = len(args)
@@ -253,11 +444,16 @@ func runPlanMode(args []string) {
 	}
 
 	tfCmd := detectTFCommand()
+	if watchMode {
+		runPlanWatch(tfCmd, tfArgs)
+		return
+	}
 
 	fmt.Printf("Terra-Prism: Running %s plan... ", tfCmd)
 
-	planArgs := append([]string{"plan", "-no-color"}, tfArgs...)
-	cmd := exec.Command(tfCmd, planArgs...)
+	chdir, planFlags := splitChdir(tfArgs)
+	planArgs := append([]string{"plan", "-no-color"}, planFlags...)
+	cmd := exec.Command(tfCmd, withChdir(chdir, planArgs...)...)
 
 	// Capture both stdout and stderr
 	output, err := cmd.CombinedOutput()
@@ -294,7 +490,7 @@ func runPlanMode(args []string) {
 
 	// Go straight to TUI
 	p := tea.NewProgram(
-		tui.NewModel(plan, version),
+		tui.NewModel(plan, version).WithSourceDir(chdir),
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
 	)
@@ -305,6 +501,73 @@ func runPlanMode(args []string) {
 	}
 }
 
+// runPlanWatch runs the plan, opens the TUI and re-runs the plan whenever the
+// configuration, variable files or local modules change, refreshing the TUI
+// in place. The TUI opens even when the first run fails so the error can be
+// fixed while watching.
+func runPlanWatch(tfCmd string, tfArgs []string) {
+	fmt.Printf("Terra-Prism: Running %s plan (watching for changes)... ", tfCmd)
+	plan, err := runWatchedPlan(tfCmd, tfArgs)
+	if err != nil {
+		fmt.Println("FAILED")
+		plan = &parser.Plan{}
+	} else {
+		fmt.Println("OK")
+	}
+
+	chdir, _ := splitChdir(tfArgs)
+	watchDir := chdir
+	if watchDir == "" {
+		watchDir = "."
+	}
+	p := tea.NewProgram(
+		tui.NewModel(plan, version).WithWatch(time.Now(), err).WithSourceDir(chdir),
+		tea.WithAltScreen(),
+		tea.WithMouseCellMotion(),
+	)
+
+	done := make(chan struct{})
+	go func() {
+		for changed := range watch.New(watchDir).Run(done) {
+			reason := changed[0] + " changed"
+			if len(changed) > 1 {
+				reason = fmt.Sprintf("%d files changed", len(changed))
+			}
+			p.Send(tui.PlanRunStartedMsg{Reason: reason})
+			plan, err := runWatchedPlan(tfCmd, tfArgs)
+			p.Send(tui.PlanRefreshedMsg{Plan: plan, Err: err, At: time.Now()})
+		}
+	}()
+
+	_, err = p.Run()
+	close(done)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runWatchedPlan runs one plan for watch mode and saves successful runs to
+// history. Failures return the plan output as the error.
+func runWatchedPlan(tfCmd string, tfArgs []string) (*parser.Plan, error) {
+	chdir, planFlags := splitChdir(tfArgs)
+	planArgs := append([]string{"plan", "-no-color"}, planFlags...)
+	output, err := exec.Command(tfCmd, withChdir(chdir, planArgs...)...).CombinedOutput()
+	if err != nil {
+		if out := strings.TrimSpace(string(output)); out != "" {
+			return nil, errors.New(out)
+		}
+		return nil, fmt.Errorf("%s plan failed: %w", tfCmd, err)
+	}
+
+	// History is best effort here: a warning on stderr would garble the TUI.
+	historyHeader := history.CreateHistoryHeader("plan", tfCmd, tfArgs)
+	_, _ = history.CreateHistoryFile("plan", historyHeader+string(output))
+	_, _ = history.CleanupOldFiles()
+
+	return parser.Parse(string(output))
+}
+
 // runHistoryMode handles history subcommands: list, view
 func runHistoryMode(args []string) {
 	// Check for help first
@@ -390,6 +653,7 @@ func runHistoryList(args []string) {
 		return
 	}
 
+	tui.UseColorMode(tui.ColorAuto)
 	histDir, _ := history.GetHistoryDir()
 	fmt.Printf("History files in %s:\n\n", histDir)
 	// Header: #(3) + 2 + timestamp(16) + 2 + command(7) + 2 + status(12) + 2 + path(40) = 86
@@ -451,25 +715,7 @@ func runHistoryView(args []string) {
 
 		// Check if it's a number (index)
 		if isNumeric(target) {
-			var index int
-			_, _ = fmt.Sscanf(target, "%d", &index)
-			if index < 1 {
-				fmt.Fprintln(os.Stderr, "Index must be 1 or greater")
-				os.Exit(1)
-			}
-
-			entries, err := history.ListEntries("")
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error reading history: %v\n", err)
-				os.Exit(1)
-			}
-
-			if index > len(entries) {
-				fmt.Fprintf(os.Stderr, "Index %d out of range (only %d entries)\n", index, len(entries))
-				os.Exit(1)
-			}
-
-			filePath = entries[index-1].Path
+			filePath = historyEntryPath(target)
 		} else {
 			// It's a filename - find the full path
 			histDir, err := history.GetHistoryDir()
@@ -496,12 +742,12 @@ func runHistoryView(args []string) {
 	}
 
 	if printMode {
-		tui.PrintPlan(plan)
+		printPlan(plan)
 		return
 	}
 
 	p := tea.NewProgram(
-		tui.NewModel(plan, version),
+		newViewModel(plan),
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
 	)
@@ -512,6 +758,193 @@ func runHistoryView(args []string) {
 	}
 }
 
+// historyEntryPath returns the path of the history entry with the given
+// 1-based index (1 = most recent), exiting on an invalid index.
+func historyEntryPath(target string) string {
+	var index int
+	_, _ = fmt.Sscanf(target, "%d", &index)
+	if index < 1 {
+		fmt.Fprintln(os.Stderr, "Index must be 1 or greater")
+		os.Exit(1)
+	}
+
+	entries, err := history.ListEntries("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading history: %v\n", err)
+		os.Exit(1)
+	}
+
+	if index > len(entries) {
+		fmt.Fprintf(os.Stderr, "Index %d out of range (only %d entries)\n", index, len(entries))
+		os.Exit(1)
+	}
+
+	return entries[index-1].Path
+}
+
+// runCompareMode shows what changed between two plan runs
+func runCompareMode(args []string) {
+	var targets []string
+	for _, arg := range args {
+		switch arg {
+		case "--help", "-h":
+			printCompareUsage()
+			os.Exit(0)
+		default:
+			if strings.HasPrefix(arg, "-") {
+				fmt.Fprintf(os.Stderr, "Unknown option: %s\n", arg)
+				fmt.Fprintln(os.Stderr, "Use 'terraprism compare --help' for usage")
+				os.Exit(1)
+			}
+			targets = append(targets, arg)
+		}
+	}
+	if len(targets) != 2 {
+		printCompareUsage()
+		os.Exit(1)
+	}
+
+	previous, previousLabel := loadComparePlan(targets[0])
+	current, _ := loadComparePlan(targets[1])
+
+	p := tea.NewProgram(
+		tui.NewCompareModel(previous, current, previousLabel, version),
+		tea.WithAltScreen(),
+		tea.WithMouseCellMotion(),
+	)
+
+	if _, err := p.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// loadComparePlan reads a plan from a file, or from history when the
+// argument is an index and no such file exists. It returns the plan and a
+// label naming its source.
+func loadComparePlan(target string) (*parser.Plan, string) {
+	filePath := target
+	label := filepath.Base(target)
+	if _, err := os.Stat(target); err != nil && isNumeric(target) {
+		filePath = historyEntryPath(target)
+		label = fmt.Sprintf("history #%s (%s)", target, filepath.Base(filePath))
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading file: %v\n", err)
+		os.Exit(1)
+	}
+	plan, err := parser.Parse(string(content))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing plan %s: %v\n", label, err)
+		os.Exit(1)
+	}
+	return plan, label
+}
+
+// runExportMode writes a Markdown or HTML report of a plan to stdout.
+func runExportMode(args []string) {
+	format := "md"
+	var target, notesFile string
+	opts := tui.ExportOptions{}
+
+	// value returns the argument following a flag.
+	value := func(i int) string {
+		if i+1 >= len(args) {
+			fmt.Fprintf(os.Stderr, "Error: %s needs a value\n", args[i])
+			os.Exit(1)
+		}
+		return args[i+1]
+	}
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--help", "-h":
+			printExportUsage()
+			os.Exit(0)
+		case "--format", "-f":
+			format = value(i)
+			i++
+		case "--query", "-q":
+			queryExpr = value(i)
+			i++
+		case "--notes":
+			notesFile = value(i)
+			i++
+		case "--title":
+			opts.Title = value(i)
+			i++
+		default:
+			if v, ok := strings.CutPrefix(args[i], "--format="); ok {
+				format = v
+			} else if v, ok := strings.CutPrefix(args[i], "--query="); ok {
+				queryExpr = v
+			} else if v, ok := strings.CutPrefix(args[i], "--notes="); ok {
+				notesFile = v
+			} else if v, ok := strings.CutPrefix(args[i], "--title="); ok {
+				opts.Title = v
+			} else if strings.HasPrefix(args[i], "-") && args[i] != "-" {
+				fmt.Fprintf(os.Stderr, "Unknown option: %s\n", args[i])
+				fmt.Fprintln(os.Stderr, "Use 'terraprism export --help' for usage")
+				os.Exit(1)
+			} else {
+				target = args[i]
+			}
+		}
+	}
+	if format == "markdown" {
+		format = "md"
+	}
+	if format != "md" && format != "html" {
+		fmt.Fprintf(os.Stderr, "Error: unknown --format %q (want md or html)\n", format)
+		os.Exit(1)
+	}
+
+	var plan *parser.Plan
+	if target != "" && target != "-" {
+		plan, _ = loadComparePlan(target)
+	} else {
+		stat, _ := os.Stdin.Stat()
+		if (stat.Mode() & os.ModeCharDevice) != 0 {
+			printExportUsage()
+			os.Exit(1)
+		}
+		content, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+			os.Exit(1)
+		}
+		plan, err = parser.Parse(string(content))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing plan: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if notesFile != "" {
+		notes, err := os.ReadFile(notesFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading notes: %v\n", err)
+			os.Exit(1)
+		}
+		opts.Notes = string(notes)
+	}
+	if queryExpr != "" {
+		filtered, err := tui.FilterPlan(plan, queryExpr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --query: %v\n", err)
+			os.Exit(1)
+		}
+		plan = filtered
+	}
+
+	if format == "html" {
+		fmt.Print(tui.ExportHTML(plan, opts))
+	} else {
+		fmt.Print(tui.ExportMarkdown(plan, opts))
+	}
+}
+
 // clearHistory removes all history files
 func clearHistory() {
 	histDir, err := history.GetHistoryDir()
@@ -686,13 +1119,43 @@ func runViewMode(args []string) {
 		switch args[i] {
 		case "-p", "--print":
 			printMode = true
+		case "-q", "--query":
+			if i+1 >= len(args) {
+				fmt.Fprintln(os.Stderr, "Error: --query needs an expression")
+				os.Exit(1)
+			}
+			i++
+			queryExpr = args[i]
+		case "--filter", "--sort", "--context", "--width", "--color":
+			setPrintOption(args[i], flagValue(args, i))
+			i++
+		case "--collapsed":
+			printOpts.Collapsed = true
+		case "-o", "--output":
+			if i+1 >= len(args) {
+				fmt.Fprintln(os.Stderr, "Error: --output needs a format (json or ndjson)")
+				os.Exit(1)
+			}
+			i++
+			outputFmt = args[i]
 		default:
-			if !strings.HasPrefix(args[i], "-") {
+			if v, ok := strings.CutPrefix(args[i], "--query="); ok {
+				queryExpr = v
+			} else if v, ok := strings.CutPrefix(args[i], "--output="); ok {
+				outputFmt = v
+			} else if flag, v, ok := strings.Cut(args[i], "="); ok && isPrintOption(flag) {
+				setPrintOption(flag, v)
+			} else if !strings.HasPrefix(args[i], "-") {
 				inputFile = args[i]
 			}
 		}
 	}
 
+	if outputFmt != "" && outputFmt != "json" && outputFmt != "ndjson" {
+		fmt.Fprintf(os.Stderr, "Error: unknown --output %q (want json or ndjson)\n", outputFmt)
+		os.Exit(1)
+	}
+
 	var input io.Reader
 
 	if inputFile != "" && inputFile != "-" {
@@ -734,18 +1197,24 @@ func runViewMode(args []string) {
 		os.Exit(1)
 	}
 
+	// Scripts get a document even for an empty plan.
+	if outputFmt != "" {
+		writePlanJSON(plan)
+		os.Exit(0)
+	}
+
 	if len(plan.Resources) == 0 {
 		fmt.Println("No resource changes detected in the plan.")
 		os.Exit(0)
 	}
 
 	if printMode {
-		tui.PrintPlan(plan)
+		printPlan(plan)
 		os.Exit(0)
 	}
 
 	p := tea.NewProgram(
-		tui.NewModel(plan, version),
+		newViewModel(plan),
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
 	)
@@ -763,10 +1232,13 @@ USAGE:
     terraform plan -no-color | terraprism        # Pipe plan output
     terraprism <plan-file>                       # Read from file
     terraprism plan [-- tf-args]                 # Run plan and view
+    terraprism plan --watch [-- tf-args]         # Re-run plan on .tf changes
     terraprism apply [-- tf-args]                # Run plan, view, and apply
     terraprism destroy [-- tf-args]              # Run destroy plan and apply
     terraprism init|validate|fmt|...             # Pass through to terraform/tofu
     terraprism history [options]                 # List history files
+    terraprism compare <planA> <planB>           # What changed between plans
+    terraprism export --format md|html [file]    # Markdown/HTML plan report
 
 DESCRIPTION:
     Terra-Prism provides an interactive terminal UI for viewing Terraform and
@@ -779,6 +1251,8 @@ COMMANDS:
     destroy     Run destroy plan, review in TUI, press 'a' to destroy
     state list|show|rm   Interactive state TUI (search, sort, taint, untaint)
     history     View and manage plan/apply history
+    compare     Show what changed between two plans (files or history #)
+    export      Write a Markdown or HTML plan report
     version     Show terraprism and terraform/tofu versions
     upgrade     Upgrade terraprism to the latest release
     init, validate, fmt, output, state mv, import, workspace, graph,
@@ -792,11 +1266,28 @@ GLOBAL OPTIONS:
 ENVIRONMENT:
     TERRAPRISM_TOFU   Set to 1, true, or yes to use OpenTofu
     TERRAPRISM_THEME  Set to "light" or "dark" to force theme
+    TERRAPRISM_DIFF_ALGORITHM  "myers" (default) or "patience" for heredoc diffs
+    TERRAPRISM_SUPPRESS_FILE  Noise-suppression rules (default: ~/.terraprism/suppress.yaml)
+    TERRAPRISM_QUERIES_FILE  Saved queries (default: ~/.terraprism/queries.yaml)
     TERRAPRISM_SKIP_UPDATE_CHECK  Set to 1, true, or yes to skip update checks
     TERRAPRISM_UPDATE_CHECK_INTERVAL  Days between TUI update checks (default: 7)
 
 VIEW OPTIONS:
     -p, --print     Print mode (no TUI)
+    -q, --query Q   Only show resources matching query Q, e.g.
+                    "action:destroy type:aws_iam_*" or @saved-name
+    -o, --output F  Write the parsed plan as json or ndjson (schema v1,
+                    see README "JSON Output")
+
+PRINT OPTIONS (with -p):
+    --filter A,B    Only print these actions (create, update, replace,
+                    destroy, read, delete-create, create-delete, output)
+    --sort S        Order by default, action, address or type
+    --context N     Heredoc/JSON diff context lines (default 3)
+    --collapsed     One summary line per resource, no bodies
+    --width N       Wrap width (default: terminal width, or 120 when piped)
+    --color WHEN    auto (default), always or never; auto drops colors when
+                    piped or when NO_COLOR is set
 
 CONTROLS:
     j/k         Move cursor up/down
@@ -806,7 +1297,10 @@ CONTROLS:
     gg/G        Go to first/last resource
     e/c         Expand/collapse all
     /           Search resources
+    :           Query bar (Q: saved queries)
     n/N         Next/previous match
+    o/O         Preview source block / open it in $EDITOR
+    x/X         Export listed resources as Markdown / HTML
     a           Apply (only in apply mode)
     q/Esc       Quit
 
@@ -821,6 +1315,9 @@ EXAMPLES:
     # Run plan and view
     terraprism plan
 
+    # Re-run the plan whenever .tf/.tfvars files or local modules change
+    terraprism plan --watch -- -var-file=dev.tfvars
+
     # Run plan, review, and apply
     terraprism apply
 
@@ -836,9 +1333,69 @@ EXAMPLES:
     # View history
     terraprism history
 
+    # What changed since the previous plan run
+    terraprism compare 2 1
+
 `, version)
 }
 
+func printCompareUsage() {
+	fmt.Printf(`terraprism compare - Show what changed between two plan runs
+
+USAGE:
+    terraprism compare <planA> <planB>
+
+DESCRIPTION:
+    Matches resources by address and lists those that appeared or
+    disappeared in planB, changed action, or whose attribute changes
+    differ from planA. Expanding a resource shows the attribute changes
+    that differ above its diff.
+
+    Each plan is a file, or a history index (1 = most recent) when no file
+    of that name exists.
+
+CONTROLS IN TUI:
+    w           Toggle between changed resources and the whole plan
+    q/Esc       Quit
+
+EXAMPLES:
+    terraprism compare before.txt after.txt
+    terraprism compare 2 1              # Previous plan run vs latest
+
+`)
+}
+
+func printExportUsage() {
+	fmt.Printf(`terraprism export - Write a plan report as Markdown or HTML
+
+USAGE:
+    terraprism export [options] [plan-file | history-index]
+    terraform plan -no-color | terraprism export [options]
+
+DESCRIPTION:
+    Renders the plan as a report for pull requests and change tickets: a
+    summary table, one section per action, a collapsible <details> block per
+    resource with its diff, and notes on attributes that force replacement.
+    The report is written to stdout. HTML reports embed the current theme's
+    colors and load nothing from the network.
+
+OPTIONS:
+    -f, --format F  md (default) or html
+    -q, --query Q   Only include resources matching query Q (or @saved-name)
+    --notes FILE    Include reviewer notes from FILE
+    --title T       Report heading (default: Terraform plan)
+
+    In the TUI, x and X export the listed resources, with reviewed marks,
+    to terraprism-plan-<time>.md or .html in the current directory.
+
+EXAMPLES:
+    terraprism export plan.txt > plan.md
+    terraprism export --format html --notes review.md 1 > plan.html
+    terraform plan -no-color | terraprism export -q "action:destroy"
+
+`)
+}
+
 func printApplyUsage() {
 	fmt.Printf(`terraprism apply - Run plan, review, and apply
 
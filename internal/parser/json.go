@@ -0,0 +1,244 @@
+package parser
+
+import (
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// JSONSchemaVersion is the version of the documents written by WriteJSON and
+// WriteNDJSON. It changes only when a field is removed or its meaning
+// changes; new fields may be added within a version.
+const JSONSchemaVersion = 1
+
+// JSONPlan is the normalised plan written by terraprism -o json.
+type JSONPlan struct {
+	SchemaVersion int            `json:"schema_version"`
+	Summary       JSONSummary    `json:"summary"`
+	Resources     []JSONResource `json:"resources"`
+	Outputs       []JSONOutput   `json:"outputs"`
+}
+
+// JSONSummary holds the plan's "Plan: ..." counts and the number of parsed
+// resource and output changes. All counts describe the same resources: for a
+// plan narrowed by a query they are recounted from the resources it kept.
+type JSONSummary struct {
+	Text      string `json:"text,omitempty"` // the Plan: line, when present
+	Add       int    `json:"add"`
+	Change    int    `json:"change"`
+	Destroy   int    `json:"destroy"`
+	Resources int    `json:"resources"`
+	Outputs   int    `json:"outputs"`
+}
+
+// JSONResource is a resource change. Address components follow Terraform's
+// resource address syntax.
+type JSONResource struct {
+	Address    string          `json:"address"`
+	Module     string          `json:"module,omitempty"` // e.g. module.network.module.dns
+	Mode       string          `json:"mode"`             // "managed" or "data"
+	Type       string          `json:"type"`
+	Name       string          `json:"name"`
+	Index      string          `json:"index,omitempty"` // instance key as written, e.g. 0 or "a"
+	Action     Action          `json:"action"`
+	Attributes []JSONAttribute `json:"attributes"`
+}
+
+// JSONAttribute is an attribute change. Values are the HCL text Terraform
+// printed, so strings keep their quotes and unknown values read
+// "(known after apply)".
+type JSONAttribute struct {
+	Path      string `json:"path"`
+	Name      string `json:"name"`
+	Action    Action `json:"action"`
+	Before    string `json:"before,omitempty"`
+	After     string `json:"after,omitempty"`
+	Computed  bool   `json:"computed"`
+	Sensitive bool   `json:"sensitive"`
+}
+
+// JSONOutput is a change to a root module output value.
+type JSONOutput struct {
+	Name      string `json:"name"`
+	Action    Action `json:"action"`
+	Before    string `json:"before,omitempty"`
+	After     string `json:"after,omitempty"`
+	Computed  bool   `json:"computed"`
+	Sensitive bool   `json:"sensitive"`
+}
+
+var outputLineRegex = regexp.MustCompile(`^(\s*)([~+\-])\s+([A-Za-z_][A-Za-z0-9_-]*)\s*=\s*(.*)$`)
+
+// ToJSON converts the plan to its JSON document form.
+func (p *Plan) ToJSON() JSONPlan {
+	doc := JSONPlan{
+		SchemaVersion: JSONSchemaVersion,
+		Summary: JSONSummary{
+			Text:    strings.TrimSpace(p.Summary),
+			Add:     p.TotalAdd,
+			Change:  p.TotalChange,
+			Destroy: p.TotalDestroy,
+		},
+		Resources: []JSONResource{},
+		Outputs:   []JSONOutput{},
+	}
+	for _, r := range p.Resources {
+		if r.Action == ActionOutput {
+			doc.Outputs = append(doc.Outputs, jsonOutputs(r)...)
+			continue
+		}
+		doc.Resources = append(doc.Resources, jsonResource(r))
+	}
+	doc.Summary.Resources = len(doc.Resources)
+	doc.Summary.Outputs = len(doc.Outputs)
+	return doc
+}
+
+func jsonResource(r Resource) JSONResource {
+	res := JSONResource{Address: r.Address, Mode: "managed", Action: r.Action, Attributes: []JSONAttribute{}}
+	parts := splitAddressParts(r.Address)
+	var modules []string
+	for len(parts) > 2 && parts[0] == "module" {
+		modules = append(modules, "module."+parts[1])
+		parts = parts[2:]
+	}
+	res.Module = strings.Join(modules, ".")
+	if len(parts) == 3 && parts[0] == "data" {
+		res.Mode = "data"
+		parts = parts[1:]
+	}
+	if len(parts) == 2 {
+		res.Type = parts[0]
+		res.Name, res.Index = splitInstanceKey(parts[1])
+	} else {
+		res.Type, res.Name = r.Type, r.Name
+	}
+	for _, a := range r.Attributes {
+		// Nested block changes are parsed as attributes named "block {".
+		name := strings.TrimSpace(strings.TrimRight(a.Name, "{[ "))
+		path := a.Path
+		if path == "" {
+			path = name
+		}
+		res.Attributes = append(res.Attributes, JSONAttribute{
+			Path:      path,
+			Name:      name,
+			Action:    a.Action,
+			Before:    jsonBefore(a),
+			After:     a.NewValue,
+			Computed:  a.Computed,
+			Sensitive: a.Sensitive,
+		})
+	}
+	return res
+}
+
+// jsonBefore drops the " -> null" Terraform appends to removed values.
+func jsonBefore(a Attribute) string {
+	if a.Action == ActionDestroy {
+		return strings.TrimSuffix(a.OldValue, " -> null")
+	}
+	return a.OldValue
+}
+
+// jsonOutputs parses the "Changes to Outputs" pseudo-resource. Only lines at
+// the outermost indentation name outputs; deeper lines belong to map and list
+// values.
+func jsonOutputs(r Resource) []JSONOutput {
+	indent := -1
+	for _, line := range r.RawLines {
+		if m := outputLineRegex.FindStringSubmatch(line); m != nil && (indent < 0 || len(m[1]) < indent) {
+			indent = len(m[1])
+		}
+	}
+	var outputs []JSONOutput
+	for _, line := range r.RawLines {
+		m := outputLineRegex.FindStringSubmatch(line)
+		if m == nil || len(m[1]) != indent {
+			continue
+		}
+		a := parseNewFormatAttrFromMatch(m[2], m[3], strings.TrimSpace(m[4]))
+		outputs = append(outputs, JSONOutput{
+			Name:      a.Name,
+			Action:    a.Action,
+			Before:    jsonBefore(*a),
+			After:     a.NewValue,
+			Computed:  a.Computed,
+			Sensitive: a.Sensitive,
+		})
+	}
+	return outputs
+}
+
+// splitAddressParts splits a resource address on dots outside instance keys.
+func splitAddressParts(address string) []string {
+	var parts []string
+	depth, start := 0, 0
+	inString := false
+	for i := 0; i < len(address); i++ {
+		switch c := address[i]; {
+		case c == '"' && (i == 0 || address[i-1] != '\\'):
+			inString = !inString
+		case inString:
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case c == '.' && depth == 0:
+			parts = append(parts, address[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, address[start:])
+}
+
+// splitInstanceKey splits name[key] into the name and the key as written.
+func splitInstanceKey(s string) (string, string) {
+	i := strings.IndexByte(s, '[')
+	if i < 0 || !strings.HasSuffix(s, "]") {
+		return s, ""
+	}
+	return s[:i], s[i+1 : len(s)-1]
+}
+
+// WriteJSON writes the plan as one indented JSON document.
+func WriteJSON(w io.Writer, p *Plan) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(p.ToJSON())
+}
+
+// WriteNDJSON writes the plan as newline-delimited JSON: one record per
+// resource, then one per output, then the summary. Every record carries
+// schema_version and a kind of "resource", "output" or "summary" alongside
+// the fields of JSONResource, JSONOutput or JSONSummary.
+func WriteNDJSON(w io.Writer, p *Plan) error {
+	doc := p.ToJSON()
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, r := range doc.Resources {
+		if err := enc.Encode(struct {
+			SchemaVersion int    `json:"schema_version"`
+			Kind          string `json:"kind"`
+			JSONResource
+		}{doc.SchemaVersion, "resource", r}); err != nil {
+			return err
+		}
+	}
+	for _, o := range doc.Outputs {
+		if err := enc.Encode(struct {
+			SchemaVersion int    `json:"schema_version"`
+			Kind          string `json:"kind"`
+			JSONOutput
+		}{doc.SchemaVersion, "output", o}); err != nil {
+			return err
+		}
+	}
+	return enc.Encode(struct {
+		SchemaVersion int    `json:"schema_version"`
+		Kind          string `json:"kind"`
+		JSONSummary
+	}{doc.SchemaVersion, "summary", doc.Summary})
+}
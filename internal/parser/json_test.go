@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const jsonTestPlan = `
+Terraform will perform the following actions:
+
+  # data.aws_ami.ubuntu will be read during apply
+ <= data "aws_ami" "ubuntu" {
+      + id = (known after apply)
+    }
+
+  # module.network.module.dns.aws_route53_record.www["a"] will be created
+  + resource "aws_route53_record" "www" {
+      + name = "www"
+      + root_block_device {
+          + volume_size = 20
+        }
+    }
+
+  # aws_s3_bucket.data will be destroyed
+  - resource "aws_s3_bucket" "data" {
+      - bucket = "my-data-bucket" -> null
+    }
+
+Plan: 1 to add, 0 to change, 1 to destroy.
+
+Changes to Outputs:
+  + endpoint = "www.example.com"
+  ~ zones    = [
+      + "c",
+    ]
+  - legacy   = "x" -> null
+`
+
+func TestToJSON(t *testing.T) {
+	plan, err := Parse(jsonTestPlan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := plan.ToJSON()
+
+	if doc.SchemaVersion != JSONSchemaVersion || doc.Summary.Add != 1 || doc.Summary.Destroy != 1 || doc.Summary.Resources != 3 || doc.Summary.Outputs != 3 {
+		t.Errorf("unexpected header %+v", doc.Summary)
+	}
+
+	byAddr := make(map[string]JSONResource)
+	for _, r := range doc.Resources {
+		byAddr[r.Address] = r
+	}
+	if r := byAddr["data.aws_ami.ubuntu"]; r.Mode != "data" || r.Type != "aws_ami" || r.Name != "ubuntu" || r.Module != "" {
+		t.Errorf("data source = %+v", r)
+	}
+	r := byAddr[`module.network.module.dns.aws_route53_record.www["a"]`]
+	if r.Module != "module.network.module.dns" || r.Type != "aws_route53_record" || r.Name != "www" || r.Index != `"a"` || r.Action != ActionCreate {
+		t.Errorf("module resource = %+v", r)
+	}
+	paths := make(map[string]JSONAttribute)
+	for _, a := range r.Attributes {
+		paths[a.Path] = a
+	}
+	if a := paths["root_block_device"]; a.Name != "root_block_device" {
+		t.Errorf("block attribute = %+v", a)
+	}
+	if a := paths["root_block_device.volume_size"]; a.After != "20" {
+		t.Errorf("nested attribute = %+v", a)
+	}
+	if a := byAddr["aws_s3_bucket.data"].Attributes[0]; a.Before != `"my-data-bucket"` || a.After != "" {
+		t.Errorf("destroyed attribute = %+v", a)
+	}
+
+	var names []string
+	for _, o := range doc.Outputs {
+		names = append(names, o.Name+":"+string(o.Action))
+	}
+	if got := strings.Join(names, ","); got != "endpoint:create,zones:update,legacy:destroy" {
+		t.Errorf("outputs = %s", got)
+	}
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	plan, err := Parse(jsonTestPlan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteNDJSON(&buf, plan); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 7 {
+		t.Fatalf("expected 3 resources, 3 outputs and a summary, got %d lines:\n%s", len(lines), buf.String())
+	}
+	var kinds []string
+	for _, line := range lines {
+		var record struct {
+			SchemaVersion int    `json:"schema_version"`
+			Kind          string `json:"kind"`
+			Address       string `json:"address"`
+		}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid record %s: %v", line, err)
+		}
+		if record.SchemaVersion != JSONSchemaVersion {
+			t.Errorf("record without schema version: %s", line)
+		}
+		kinds = append(kinds, record.Kind)
+	}
+	if got := strings.Join(kinds, ","); got != "resource,resource,resource,output,output,output,summary" {
+		t.Errorf("kinds = %s", got)
+	}
+	if !strings.Contains(lines[1], `"index":"\"a\""`) {
+		t.Errorf("resource record should carry its fields: %s", lines[1])
+	}
+}
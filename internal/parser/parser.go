@@ -24,6 +24,7 @@ const (
 // Attribute represents a single attribute change
 type Attribute struct {
 	Name      string
+	Path      string // dotted path including enclosing blocks, e.g. "root_block_device.volume_size"
 	OldValue  string
 	NewValue  string
 	Action    Action
@@ -137,6 +138,7 @@ func parseNewFormat(plan *Plan, lines []string) {
 	attrRegex2 := regexp.MustCompile(`^\s+([~+\-])\s+(.+)$`)
 
 	var currentResource *Resource
+	var path attrPathTracker
 	inResourceBlock := false
 	braceCount := 0
 
@@ -164,6 +166,7 @@ func parseNewFormat(plan *Plan, lines []string) {
 			}
 			inResourceBlock = true
 			braceCount = 0
+			path = attrPathTracker{}
 			continue
 		}
 
@@ -171,13 +174,21 @@ func parseNewFormat(plan *Plan, lines []string) {
 			currentResource.RawLines = append(currentResource.RawLines, line)
 			braceCount += strings.Count(line, "{") - strings.Count(line, "}")
 
+			if path.inHeredoc(line) || isResourceDeclaration(line) {
+				continue
+			}
+			path.close(line)
+
 			if match := attrRegex.FindStringSubmatch(line); match != nil {
 				attr := parseNewFormatAttrFromMatch(match[1], strings.TrimSpace(match[2]), strings.TrimSpace(match[3]))
+				attr.Path = path.join(attr.Name)
 				currentResource.Attributes = append(currentResource.Attributes, *attr)
 			} else if match := attrRegex2.FindStringSubmatch(line); match != nil {
-				attr := parseNewFormatAttrSimple(match[1], strings.TrimSpace(match[2]))
+				attr := parseNewFormatAttrSimple(match[1], StripPlanComment(strings.TrimSpace(match[2])))
+				attr.Path = path.join("")
 				currentResource.Attributes = append(currentResource.Attributes, *attr)
 			}
+			path.open(line)
 
 			if braceCount <= 0 && strings.TrimSpace(line) == "}" {
 				inResourceBlock = false
@@ -189,6 +200,116 @@ func parseNewFormat(plan *Plan, lines []string) {
 	}
 }
 
+// attrPathTracker follows block nesting inside a resource body so attributes
+// can be given a dotted Path. Anonymous list elements ("{") don't contribute a
+// path segment, and heredoc bodies are skipped entirely.
+type attrPathTracker struct {
+	stack         []string
+	heredocMarker string
+}
+
+var heredocOpenRegex = regexp.MustCompile(`<<-?([A-Za-z_][A-Za-z0-9_]*)\s*$`)
+
+// inHeredoc reports whether line belongs to a heredoc body (including its
+// closing marker) and updates the tracker when a heredoc starts or ends.
+func (t *attrPathTracker) inHeredoc(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if t.heredocMarker != "" {
+		if trimmed == t.heredocMarker || strings.HasPrefix(trimmed, t.heredocMarker+",") ||
+			strings.HasPrefix(trimmed, t.heredocMarker+" ") {
+			t.heredocMarker = ""
+		}
+		return true
+	}
+	if match := heredocOpenRegex.FindStringSubmatch(trimmed); match != nil {
+		t.heredocMarker = match[1]
+	}
+	return false
+}
+
+func (t *attrPathTracker) close(line string) {
+	content := StripPlanComment(stripLineDiffPrefix(strings.TrimSpace(line)))
+	if content == "" || len(t.stack) == 0 {
+		return
+	}
+	switch content[0] {
+	case '}', ']', ')':
+		t.stack = t.stack[:len(t.stack)-1]
+	}
+}
+
+func (t *attrPathTracker) open(line string) {
+	content := StripPlanComment(stripLineDiffPrefix(strings.TrimSpace(line)))
+	if content == "" {
+		return
+	}
+	switch content[len(content)-1] {
+	case '{', '[', '(':
+	default:
+		return
+	}
+	name := ""
+	if idx := strings.Index(content, "="); idx > 0 {
+		name = content[:idx]
+	} else if idx := strings.LastIndex(content, " "); idx > 0 {
+		name = content[:idx]
+	}
+	t.stack = append(t.stack, strings.Trim(strings.TrimSpace(name), `"`))
+}
+
+// join returns the dotted path for name under the current nesting. An empty
+// name yields the enclosing path, which is used for bare list elements.
+func (t *attrPathTracker) join(name string) string {
+	parts := make([]string, 0, len(t.stack)+1)
+	for _, segment := range t.stack {
+		if segment != "" {
+			parts = append(parts, segment)
+		}
+	}
+	if name != "" {
+		parts = append(parts, name)
+	}
+	return strings.Join(parts, ".")
+}
+
+// isResourceDeclaration reports whether line is the `resource "type" "name" {`
+// (or data source) line that opens a resource body.
+func isResourceDeclaration(line string) bool {
+	content := strings.TrimSpace(line)
+	for _, prefix := range []string{"-/+ ", "+/- ", "<= "} {
+		content = strings.TrimPrefix(content, prefix)
+	}
+	content = stripLineDiffPrefix(content)
+	return strings.HasPrefix(content, "resource ") || strings.HasPrefix(content, "data ")
+}
+
+// StripPlanComment drops trailing annotations such as "# forces replacement"
+// from a line of plan content, leaving "#" inside quoted strings alone.
+func StripPlanComment(content string) string {
+	if strings.HasPrefix(content, "#") {
+		return ""
+	}
+	inString := false
+	for i := 0; i < len(content); i++ {
+		switch {
+		case content[i] == '\\' && inString:
+			i++
+		case content[i] == '"':
+			inString = !inString
+		case !inString && strings.HasPrefix(content[i:], " # "):
+			return strings.TrimSpace(content[:i])
+		}
+	}
+	return content
+}
+
+func stripLineDiffPrefix(s string) string {
+	if len(s) >= 2 && s[1] == ' ' && (s[0] == '+' || s[0] == '-' || s[0] == '~') {
+		return strings.TrimSpace(s[2:])
+	}
+	return s
+}
+
 var terraformAddressIdentRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
 
 func isTerraformResourceAddress(address string) bool {
@@ -337,3 +337,110 @@ No changes. Infrastructure is up-to-date.
 		t.Errorf("Expected 0 resources, got %d", len(plan.Resources))
 	}
 }
+
+func TestParseNewFormatAttributePaths(t *testing.T) {
+	input := `
+  # aws_instance.web will be updated in-place
+  ~ resource "aws_instance" "web" {
+        id            = "i-123"
+      ~ instance_type = "t3.micro" -> "t3.large"
+        tags          = {
+            "Name" = "web"
+        }
+      ~ root_block_device {
+          ~ volume_size = 50 -> 100
+        }
+      ~ user_data     = <<-EOT
+          - old: {
+          + new: {
+        EOT
+      ~ ingress       = [
+          + {
+              + cidr_blocks = [
+                  + "0.0.0.0/0",
+                ]
+            },
+        ]
+    }
+`
+
+	plan, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Failed to parse plan: %v", err)
+	}
+	if len(plan.Resources) != 1 {
+		t.Fatalf("Expected 1 resource, got %d", len(plan.Resources))
+	}
+
+	paths := make(map[string]bool)
+	for _, attr := range plan.Resources[0].Attributes {
+		paths[attr.Path] = true
+	}
+	for _, want := range []string{
+		"instance_type",
+		"root_block_device.volume_size",
+		"user_data",
+		"ingress",
+		"ingress.cidr_blocks",
+	} {
+		if !paths[want] {
+			t.Errorf("Expected attribute path %q, got %v", want, paths)
+		}
+	}
+	for _, unwanted := range []string{"old: {", "new: {", "user_data.old: {"} {
+		if paths[unwanted] {
+			t.Errorf("Heredoc body leaked into attribute paths: %v", paths)
+		}
+	}
+}
+
+func TestStripPlanComment(t *testing.T) {
+	tests := map[string]string{
+		`protocol = "tcp" # forces replacement`: `protocol = "tcp"`,
+		`description = "a # b"`:                 `description = "a # b"`,
+		`# (2 unchanged attributes hidden)`:     ``,
+	}
+	for in, want := range tests {
+		if got := StripPlanComment(in); got != want {
+			t.Errorf("StripPlanComment(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseNewFormatPathsAfterForcesReplacementBlock(t *testing.T) {
+	input := `
+  # aws_instance.web must be replaced
+-/+ resource "aws_instance" "web" {
+      ~ network_interface {
+          - ebs_block_device { # forces replacement
+              - volume_size = 10 -> null
+            }
+          ~ device_index = 0 -> 1
+        }
+      ~ instance_type = "t3.micro" -> "t3.large"
+    }
+`
+
+	plan, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Failed to parse plan: %v", err)
+	}
+	if len(plan.Resources) != 1 {
+		t.Fatalf("Expected 1 resource, got %d", len(plan.Resources))
+	}
+
+	paths := make(map[string]string)
+	for _, attr := range plan.Resources[0].Attributes {
+		paths[attr.Name] = attr.Path
+	}
+	for name, want := range map[string]string{
+		"ebs_block_device {": "network_interface",
+		"volume_size":        "network_interface.ebs_block_device.volume_size",
+		"device_index":       "network_interface.device_index",
+		"instance_type":      "instance_type",
+	} {
+		if got, ok := paths[name]; !ok || got != want {
+			t.Errorf("Path of %q = %q, want %q (all: %v)", name, got, want, paths)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// Package source locates the configuration block behind a plan resource
+// address in a Terraform working directory.
+package source
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Location is a resource or data block in a .tf file.
+type Location struct {
+	File  string   // path of the .tf file
+	Line  int      // 1-based line of the block header
+	Lines []string // the block, from its header to the closing brace
+}
+
+// localSourcePrefixes mark module sources that are paths in the working tree.
+var localSourcePrefixes = []string{"./", "../"}
+
+var sourceAttrRegex = regexp.MustCompile(`^\s*source\s*=\s*"([^"]*)"`)
+
+// Locate finds the block that declares address, e.g.
+// module.network.aws_subnet.private["a"], starting in dir and following
+// local module sources. Remote modules cannot be followed.
+func Locate(dir, address string) (Location, error) {
+	modules, kind, typ, name, err := splitAddress(address)
+	if err != nil {
+		return Location{}, err
+	}
+	for i, module := range modules {
+		block, err := findBlock(dir, "module", module)
+		if err != nil {
+			return Location{}, fmt.Errorf("%s: %w", strings.Join(prefixed("module.", modules[:i+1]), "."), err)
+		}
+		src := blockSource(block.Lines)
+		if !isLocalSource(src) {
+			return Location{}, fmt.Errorf("module.%s is not a local module (source %q)", module, src)
+		}
+		dir = filepath.Join(filepath.Dir(block.File), src)
+	}
+	return findBlock(dir, kind, typ, name)
+}
+
+// splitAddress splits a resource address into its module path and the block
+// kind ("resource" or "data"), type and name. Instance keys are dropped.
+func splitAddress(address string) (modules []string, kind, typ, name string, err error) {
+	parts := addressParts(address)
+	for len(parts) >= 2 && parts[0] == "module" {
+		modules = append(modules, stripInstanceKey(parts[1]))
+		parts = parts[2:]
+	}
+	kind = "resource"
+	if len(parts) > 0 && parts[0] == "data" {
+		kind = "data"
+		parts = parts[1:]
+	}
+	if len(parts) != 2 {
+		return nil, "", "", "", fmt.Errorf("cannot parse resource address %q", address)
+	}
+	return modules, kind, parts[0], stripInstanceKey(parts[1]), nil
+}
+
+// addressParts splits an address on dots outside instance keys.
+func addressParts(address string) []string {
+	var parts []string
+	depth, start := 0, 0
+	inString := false
+	for i := 0; i < len(address); i++ {
+		switch c := address[i]; {
+		case c == '"' && (i == 0 || address[i-1] != '\\'):
+			inString = !inString
+		case inString:
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case c == '.' && depth == 0:
+			parts = append(parts, address[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, address[start:])
+}
+
+func stripInstanceKey(s string) string {
+	if i := strings.IndexByte(s, '['); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+func prefixed(prefix string, names []string) []string {
+	out := make([]string, len(names))
+	for i, name := range names {
+		out[i] = prefix + name
+	}
+	return out
+}
+
+// findBlock returns the first block with the given kind and labels in the
+// .tf files of dir, in file name order.
+func findBlock(dir, kind string, labels ...string) (Location, error) {
+	pattern := `^\s*` + kind
+	for _, label := range labels {
+		pattern += `\s+"` + regexp.QuoteMeta(label) + `"`
+	}
+	header := regexp.MustCompile(pattern + `\s*\{`)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return Location{}, err
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		lines := strings.Split(string(data), "\n")
+		for i, line := range lines {
+			if header.MatchString(line) {
+				return Location{File: file, Line: i + 1, Lines: blockLines(lines[i:])}, nil
+			}
+		}
+	}
+	desc := kind + ` "` + strings.Join(labels, `" "`) + `"`
+	if len(files) == 0 {
+		return Location{}, fmt.Errorf("no .tf files in %s", dir)
+	}
+	return Location{}, fmt.Errorf("%s not found in %s", desc, dir)
+}
+
+// blockLines returns the lines from a block header through its closing brace,
+// counting braces outside strings and comments.
+func blockLines(lines []string) []string {
+	depth := 0
+	for i, line := range lines {
+		inString := false
+		for j := 0; j < len(line); j++ {
+			c := line[j]
+			if inString {
+				if c == '\\' {
+					j++
+				} else if c == '"' {
+					inString = false
+				}
+				continue
+			}
+			if c == '#' || (c == '/' && j+1 < len(line) && line[j+1] == '/') {
+				break
+			}
+			switch c {
+			case '"':
+				inString = true
+			case '{':
+				depth++
+			case '}':
+				depth--
+			}
+		}
+		if depth <= 0 {
+			return lines[:i+1]
+		}
+	}
+	return lines
+}
+
+// blockSource returns the source attribute of a module block.
+func blockSource(lines []string) string {
+	for _, line := range lines {
+		if m := sourceAttrRegex.FindStringSubmatch(line); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+func isLocalSource(src string) bool {
+	for _, prefix := range localSourcePrefixes {
+		if strings.HasPrefix(src, prefix) {
+			return true
+		}
+	}
+	return false
+}
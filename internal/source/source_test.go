@@ -0,0 +1,99 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testTree(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "main.tf"), `module "network" {
+  source = "./modules/network"
+}
+
+module "consul" {
+  source = "hashicorp/consul/aws"
+}
+
+resource "aws_instance" "web" {
+  ami  = "ami-1"
+  tags = { Name = "web}" } # braces in strings: {
+}
+`)
+	writeFile(t, filepath.Join(root, "data.tf"), `data "aws_ami" "ubuntu" {
+  most_recent = true
+}
+`)
+	writeFile(t, filepath.Join(root, "modules", "network", "subnets.tf"), `
+module "dns" {
+  source = "../dns"
+}
+
+resource "aws_subnet" "private" {
+  for_each = var.zones
+}
+`)
+	writeFile(t, filepath.Join(root, "modules", "dns", "main.tf"), `resource "aws_route53_record" "www" {
+  name = "www"
+}
+`)
+	return root
+}
+
+func TestLocate(t *testing.T) {
+	root := testTree(t)
+	tests := []struct {
+		address string
+		file    string
+		line    int
+		lines   int
+	}{
+		{"aws_instance.web", "main.tf", 9, 4},
+		{"data.aws_ami.ubuntu", "data.tf", 1, 3},
+		{`module.network.aws_subnet.private["eu-west-1a"]`, "modules/network/subnets.tf", 6, 3},
+		{`module.network.module.dns.aws_route53_record.www`, "modules/dns/main.tf", 1, 3},
+	}
+	for _, tt := range tests {
+		loc, err := Locate(root, tt.address)
+		if err != nil {
+			t.Errorf("%s: %v", tt.address, err)
+			continue
+		}
+		rel, _ := filepath.Rel(root, loc.File)
+		if filepath.ToSlash(rel) != tt.file || loc.Line != tt.line || len(loc.Lines) != tt.lines {
+			t.Errorf("%s = %s:%d (%d lines), want %s:%d (%d lines)", tt.address, rel, loc.Line, len(loc.Lines), tt.file, tt.line, tt.lines)
+		}
+	}
+}
+
+func TestLocateErrors(t *testing.T) {
+	root := testTree(t)
+	tests := []struct {
+		address string
+		want    string
+	}{
+		{"aws_instance.api", `resource "aws_instance" "api" not found`},
+		{"module.consul.aws_instance.server", "module.consul is not a local module"},
+		{"module.missing.aws_instance.server", `module.missing: module "missing" not found`},
+		{"aws_instance", "cannot parse resource address"},
+	}
+	for _, tt := range tests {
+		_, err := Locate(root, tt.address)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error %v, want %q", tt.address, err, tt.want)
+		}
+	}
+}
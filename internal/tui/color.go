@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// ColorMode selects when command-line output (print mode, history listings)
+// uses ANSI colors. The TUI always renders in color: its cursor and
+// highlights are drawn with background colors.
+type ColorMode string
+
+const (
+	ColorAuto   ColorMode = "auto"
+	ColorAlways ColorMode = "always"
+	ColorNever  ColorMode = "never"
+)
+
+// ParseColorMode parses a --color value: auto, always or never.
+func ParseColorMode(s string) (ColorMode, error) {
+	switch mode := ColorMode(strings.ToLower(strings.TrimSpace(s))); mode {
+	case ColorAuto, ColorAlways, ColorNever:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown color mode %q (want auto, always or never)", s)
+}
+
+// UseColorMode sets the color profile for output written to stdout and
+// restyles with it. Auto uses colors only when stdout is a terminal, honouring
+// NO_COLOR and CLICOLOR_FORCE.
+func UseColorMode(mode ColorMode) {
+	lipgloss.SetColorProfile(colorProfile(mode, termenv.NewOutput(os.Stdout)))
+	initStyles()
+}
+
+// colorProfile resolves mode to the profile used for out.
+func colorProfile(mode ColorMode, out *termenv.Output) termenv.Profile {
+	switch mode {
+	case ColorAlways:
+		return termenv.TrueColor
+	case ColorNever:
+		return termenv.Ascii
+	}
+	return out.EnvColorProfile()
+}
+
+// plainOutput reports whether styles render without colors, in which case
+// output that relies on color alone is marked with symbols instead.
+func plainOutput() bool {
+	return lipgloss.ColorProfile() == termenv.Ascii
+}
@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+func TestParseColorMode(t *testing.T) {
+	for in, want := range map[string]ColorMode{"auto": ColorAuto, "Always": ColorAlways, " never ": ColorNever} {
+		if got, err := ParseColorMode(in); err != nil || got != want {
+			t.Errorf("ParseColorMode(%q) = %q, %v", in, got, err)
+		}
+	}
+	if _, err := ParseColorMode("sometimes"); err == nil {
+		t.Error("expected an error for an unknown color mode")
+	}
+}
+
+func TestColorProfile(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("CLICOLOR_FORCE", "")
+	piped := termenv.NewOutput(&bytes.Buffer{})
+
+	if got := colorProfile(ColorAuto, piped); got != termenv.Ascii {
+		t.Errorf("auto on a pipe = %v, want Ascii", got)
+	}
+	if got := colorProfile(ColorAlways, piped); got != termenv.TrueColor {
+		t.Errorf("always = %v, want TrueColor", got)
+	}
+	t.Setenv("CLICOLOR_FORCE", "1")
+	if got := colorProfile(ColorAuto, piped); got == termenv.Ascii {
+		t.Error("CLICOLOR_FORCE should enable colors on a pipe")
+	}
+	t.Setenv("NO_COLOR", "1")
+	if got := colorProfile(ColorAuto, piped); got != termenv.Ascii {
+		t.Errorf("auto with NO_COLOR = %v, want Ascii", got)
+	}
+	if got := colorProfile(ColorNever, piped); got != termenv.Ascii {
+		t.Errorf("never = %v, want Ascii", got)
+	}
+}
+
+func TestRenderPlanPlain(t *testing.T) {
+	lipgloss.SetColorProfile(termenv.Ascii)
+	initStyles()
+	defer func() {
+		lipgloss.SetColorProfile(termenv.TrueColor)
+		initStyles()
+	}()
+
+	plan, err := parser.Parse(`
+  # aws_instance.web will be updated in-place
+  ~ resource "aws_instance" "web" {
+      ~ instance_type = "t3.micro" -> "t3.large"
+    }
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := RenderPlan(plan, PrintOptions{})
+	if strings.Contains(out, "\x1b") {
+		t.Errorf("plain output should have no escape codes: %q", out)
+	}
+	if !strings.HasPrefix(out, printTitle+"\n") {
+		t.Errorf("plain output should start with the bare title:\n%s", out)
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if strings.TrimRight(line, " ") != line {
+			t.Errorf("trailing padding in %q", line)
+		}
+	}
+	if !strings.Contains(out, `"t3.[-micro-]" → "t3.{+large+}"`) {
+		t.Errorf("changed words should be marked with symbols:\n%s", out)
+	}
+}
@@ -0,0 +1,252 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+// deltaKind classifies a resource when comparing two plan runs.
+type deltaKind int
+
+const (
+	deltaUnchanged     deltaKind = iota
+	deltaAppeared                // only in the current plan
+	deltaDisappeared             // only in the previous plan
+	deltaActionChanged           // planned action differs
+	deltaDiffChanged             // same action, different attribute changes
+)
+
+// resourceDelta describes how a resource's planned change differs from the
+// previous plan.
+type resourceDelta struct {
+	Kind           deltaKind
+	PreviousAction parser.Action
+	Attrs          []attrDelta
+}
+
+// attrDelta is an attribute whose planned change differs between the plans.
+// Previous or Current is empty when only one plan changes the attribute.
+type attrDelta struct {
+	Path     string
+	Previous string
+	Current  string
+}
+
+// comparePlans matches resources by address and returns a plan holding the
+// current plan's resources with those that disappeared since the previous
+// plan re-inserted after their former neighbour, plus the delta of each.
+func comparePlans(previous, current *parser.Plan) (*parser.Plan, map[string]resourceDelta) {
+	prevByAddr := make(map[string]parser.Resource, len(previous.Resources))
+	for _, r := range previous.Resources {
+		prevByAddr[r.Address] = r
+	}
+	inCurrent := make(map[string]bool, len(current.Resources))
+	for _, r := range current.Resources {
+		inCurrent[r.Address] = true
+	}
+
+	deltas := make(map[string]resourceDelta, len(current.Resources))
+	for _, r := range current.Resources {
+		prev, ok := prevByAddr[r.Address]
+		switch {
+		case !ok:
+			deltas[r.Address] = resourceDelta{Kind: deltaAppeared}
+		case prev.Action != r.Action:
+			deltas[r.Address] = resourceDelta{Kind: deltaActionChanged, PreviousAction: prev.Action, Attrs: diffResourceAttrs(prev, r)}
+		default:
+			attrs := diffResourceAttrs(prev, r)
+			if len(attrs) > 0 || resourceBody(prev) != resourceBody(r) {
+				deltas[r.Address] = resourceDelta{Kind: deltaDiffChanged, PreviousAction: prev.Action, Attrs: attrs}
+			} else {
+				deltas[r.Address] = resourceDelta{Kind: deltaUnchanged, PreviousAction: prev.Action}
+			}
+		}
+	}
+
+	// Disappeared resources follow the nearest earlier resource both plans share.
+	gone := make(map[string][]parser.Resource)
+	anchor := ""
+	for _, r := range previous.Resources {
+		if inCurrent[r.Address] {
+			anchor = r.Address
+			continue
+		}
+		gone[anchor] = append(gone[anchor], r)
+		deltas[r.Address] = resourceDelta{Kind: deltaDisappeared, PreviousAction: r.Action}
+	}
+
+	merged := *current
+	merged.Resources = append([]parser.Resource(nil), gone[""]...)
+	for _, r := range current.Resources {
+		merged.Resources = append(merged.Resources, r)
+		merged.Resources = append(merged.Resources, gone[r.Address]...)
+	}
+	return &merged, deltas
+}
+
+// diffResourceAttrs lists the attribute paths whose planned change differs.
+func diffResourceAttrs(previous, current parser.Resource) []attrDelta {
+	prevChanges, prevOrder := attrChangeSummaries(previous)
+	curChanges, curOrder := attrChangeSummaries(current)
+
+	var deltas []attrDelta
+	for _, path := range curOrder {
+		if prevChanges[path] != curChanges[path] {
+			deltas = append(deltas, attrDelta{Path: path, Previous: prevChanges[path], Current: curChanges[path]})
+		}
+	}
+	for _, path := range prevOrder {
+		if _, ok := curChanges[path]; !ok {
+			deltas = append(deltas, attrDelta{Path: path, Previous: prevChanges[path]})
+		}
+	}
+	return deltas
+}
+
+// attrChangeSummaries renders each attribute path's change as "old → new",
+// joining the elements of list attributes, and returns the paths in order.
+func attrChangeSummaries(r parser.Resource) (map[string]string, []string) {
+	summaries := make(map[string]string)
+	var order []string
+	for _, attr := range r.Attributes {
+		path, oldVal, newVal, ok := pivotAttrValues(attr)
+		if !ok {
+			continue
+		}
+		change := oldVal + " → " + newVal
+		if prev, seen := summaries[path]; seen {
+			summaries[path] = prev + ", " + change
+			continue
+		}
+		summaries[path] = change
+		order = append(order, path)
+	}
+	return summaries, order
+}
+
+// resourceBody returns the resource's diff lines without the header comment,
+// for detecting changes the parsed attributes miss (e.g. heredoc bodies).
+func resourceBody(r parser.Resource) string {
+	if len(r.RawLines) <= 1 {
+		return ""
+	}
+	lines := make([]string, 0, len(r.RawLines)-1)
+	for _, line := range r.RawLines[1:] {
+		lines = append(lines, strings.TrimRight(line, " \t"))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// NewCompareModel creates a view-only TUI model showing what changed in the
+// current plan since the previous one. Only changed resources are listed
+// until the delta filter is turned off.
+func NewCompareModel(previous, current *parser.Plan, previousLabel, version string) Model {
+	merged, deltas := comparePlans(previous, current)
+	m := NewModel(merged, version)
+	m.delta = deltas
+	m.deltaLabel = previousLabel
+	m.deltaOnly = true
+	return m
+}
+
+func handleKeyToggleDelta(m Model) (Model, tea.Cmd, bool) {
+	if m.delta == nil {
+		return m, nil, true
+	}
+	m.deltaOnly = !m.deltaOnly
+	m.clampCursorAndRefreshSearch()
+	m.updateViewportContent()
+	return m, nil, true
+}
+
+// deltaCounts returns how many resources fall into each delta kind.
+func (m Model) deltaCounts() map[deltaKind]int {
+	counts := make(map[deltaKind]int)
+	for _, r := range m.plan.Resources {
+		counts[m.delta[r.Address].Kind]++
+	}
+	return counts
+}
+
+// viewDeltaStatus renders the plan comparison status line.
+func (m Model) viewDeltaStatus() string {
+	if m.delta == nil {
+		return ""
+	}
+	counts := m.deltaCounts()
+	toggle := "w: show unchanged"
+	if !m.deltaOnly {
+		toggle = "w: only changes"
+	}
+	return searchStyle.Render(fmt.Sprintf("Since %s: %d new, %d gone, %d action changed, %d diff changed, %d unchanged • %s",
+		m.deltaLabel, counts[deltaAppeared], counts[deltaDisappeared], counts[deltaActionChanged], counts[deltaDiffChanged], counts[deltaUnchanged], toggle)) + "\n\n"
+}
+
+// deltaBadge returns the short label shown after a compared resource's address.
+func (m Model) deltaBadge(r parser.Resource) (string, lipgloss.Color) {
+	if m.delta == nil {
+		return "", ""
+	}
+	d := m.delta[r.Address]
+	switch d.Kind {
+	case deltaAppeared:
+		return "[new]", createColor
+	case deltaDisappeared:
+		return "[gone]", destroyColor
+	case deltaActionChanged:
+		return "[was " + filterActionLabel(d.PreviousAction) + "]", updateColor
+	case deltaDiffChanged:
+		return "[diff changed]", updateColor
+	}
+	return "", ""
+}
+
+// renderPlanDelta renders what changed in a resource's planned diff since the
+// previous plan, shown above its expanded body.
+func (m Model) renderPlanDelta(r parser.Resource, maxWidth int) string {
+	if m.delta == nil {
+		return ""
+	}
+	d := m.delta[r.Address]
+	var notes []string
+	switch d.Kind {
+	case deltaUnchanged:
+		return ""
+	case deltaAppeared:
+		notes = append(notes, mutedColor.Render("not in the previous plan"))
+	case deltaDisappeared:
+		notes = append(notes, mutedColor.Render("no longer in the plan; showing the previous plan's diff"))
+	case deltaActionChanged:
+		notes = append(notes, mutedColor.Render("previously "+getActionDescription(d.PreviousAction)))
+	}
+	for _, a := range d.Attrs {
+		switch {
+		case a.Previous == "":
+			notes = append(notes, createSymbol+" "+attrNameStyle.Render(a.Path)+" "+a.Current+mutedColor.Render(" (new change)"))
+		case a.Current == "":
+			notes = append(notes, destroySymbol+" "+attrNameStyle.Render(a.Path)+" "+a.Previous+mutedColor.Render(" (no longer changes)"))
+		default:
+			notes = append(notes, updateSymbol+" "+attrNameStyle.Render(a.Path)+" "+a.Current+mutedColor.Render(" (was "+a.Previous+")"))
+		}
+	}
+	if d.Kind == deltaDiffChanged && len(d.Attrs) == 0 {
+		notes = append(notes, mutedColor.Render("diff body differs from the previous plan"))
+	}
+
+	var b strings.Builder
+	b.WriteString("    " + mutedColor.Render("┄┄┄ since "+m.deltaLabel+" ┄┄┄") + "\n")
+	for _, note := range notes {
+		line := "      " + note
+		if maxWidth > 0 && lipgloss.Width(line) > maxWidth {
+			line = truncate.StringWithTail(line, uint(maxWidth), "…")
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,141 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+const comparePreviousPlan = `
+  # aws_instance.web will be updated in-place
+  ~ resource "aws_instance" "web" {
+      ~ instance_type = "t3.micro" -> "t3.large"
+        id            = "i-123"
+    }
+
+  # aws_s3_bucket.old will be destroyed
+  - resource "aws_s3_bucket" "old" {
+      - bucket = "old"
+    }
+
+  # aws_iam_role.app will be updated in-place
+  ~ resource "aws_iam_role" "app" {
+      ~ name = "a" -> "b"
+    }
+
+  # aws_sqs_queue.q will be updated in-place
+  ~ resource "aws_sqs_queue" "q" {
+      ~ delay_seconds = 0 -> 5
+    }
+`
+
+const compareCurrentPlan = `
+  # aws_instance.web will be updated in-place
+  ~ resource "aws_instance" "web" {
+      ~ instance_type = "t3.micro" -> "t3.xlarge"
+      ~ ami           = "ami-1" -> "ami-2"
+        id            = "i-123"
+    }
+
+  # aws_iam_role.app must be replaced
+-/+ resource "aws_iam_role" "app" {
+      ~ name = "a" -> "b" # forces replacement
+    }
+
+  # aws_sqs_queue.q will be updated in-place
+  ~ resource "aws_sqs_queue" "q" {
+      ~ delay_seconds = 0 -> 5
+    }
+
+  # aws_sns_topic.new will be created
+  + resource "aws_sns_topic" "new" {
+      + name = "new"
+    }
+`
+
+func compareTestPlans(t *testing.T) (*parser.Plan, *parser.Plan) {
+	t.Helper()
+	previous, err := parser.Parse(comparePreviousPlan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := parser.Parse(compareCurrentPlan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return previous, current
+}
+
+func TestComparePlans(t *testing.T) {
+	merged, deltas := comparePlans(compareTestPlans(t))
+
+	var order []string
+	for _, r := range merged.Resources {
+		order = append(order, r.Address)
+	}
+	want := "aws_instance.web,aws_s3_bucket.old,aws_iam_role.app,aws_sqs_queue.q,aws_sns_topic.new"
+	if got := strings.Join(order, ","); got != want {
+		t.Errorf("merged order = %s, want %s", got, want)
+	}
+
+	kinds := map[string]deltaKind{
+		"aws_instance.web":  deltaDiffChanged,
+		"aws_s3_bucket.old": deltaDisappeared,
+		"aws_iam_role.app":  deltaActionChanged,
+		"aws_sqs_queue.q":   deltaUnchanged,
+		"aws_sns_topic.new": deltaAppeared,
+	}
+	for address, kind := range kinds {
+		if deltas[address].Kind != kind {
+			t.Errorf("%s delta = %v, want %v", address, deltas[address].Kind, kind)
+		}
+	}
+	if d := deltas["aws_iam_role.app"]; d.PreviousAction != parser.ActionUpdate {
+		t.Errorf("previous action = %q", d.PreviousAction)
+	}
+
+	attrs := deltas["aws_instance.web"].Attrs
+	if len(attrs) != 2 {
+		t.Fatalf("expected instance_type and ami deltas, got %+v", attrs)
+	}
+	if attrs[0].Path != "instance_type" || attrs[0].Previous != `"t3.micro" → "t3.large"` || attrs[0].Current != `"t3.micro" → "t3.xlarge"` {
+		t.Errorf("unexpected instance_type delta %+v", attrs[0])
+	}
+	if attrs[1].Path != "ami" || attrs[1].Previous != "" {
+		t.Errorf("unexpected ami delta %+v", attrs[1])
+	}
+}
+
+func TestCompareModelFiltersAndRendersDelta(t *testing.T) {
+	previous, current := compareTestPlans(t)
+	m := NewCompareModel(previous, current, "plan-a.txt", "test")
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 140, Height: 40})
+	m = updated.(Model)
+
+	view := stripRenderANSI(m.View())
+	for _, want := range []string{
+		"Since plan-a.txt: 1 new, 1 gone, 1 action changed, 1 diff changed, 1 unchanged",
+		"aws_s3_bucket.old [gone]", "aws_iam_role.app [was update]", "aws_instance.web [diff changed]", "aws_sns_topic.new [new]",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "aws_sqs_queue.q") {
+		t.Error("unchanged resources should be hidden by the delta filter")
+	}
+
+	m = pressKeys(m, "l")
+	view = stripRenderANSI(m.View())
+	if !strings.Contains(view, "since plan-a.txt") || !strings.Contains(view, `instance_type "t3.micro" → "t3.xlarge" (was "t3.micro" → "t3.large")`) {
+		t.Errorf("expanded resource should show its delta:\n%s", view)
+	}
+
+	m = pressKeys(m, "w")
+	if view := stripRenderANSI(m.View()); !strings.Contains(view, "aws_sqs_queue.q") {
+		t.Error("w should show unchanged resources")
+	}
+}
@@ -0,0 +1,300 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func handleKeyContentSearch(m Model) (Model, tea.Cmd, bool) {
+	m.contentSearch = true
+	m.searching = true
+	m.searchInput.Placeholder = "Search resource bodies..."
+	m.searchInput.Focus()
+	return m, textinput.Blink, true
+}
+
+// contentSearchActive reports whether a committed or in-progress content
+// search is filtering the list.
+func (m Model) contentSearchActive() bool {
+	return m.contentSearch && strings.TrimSpace(m.searchQuery) != ""
+}
+
+// performContentSearch keeps the resources whose rendered text contains the
+// query: the same text the list highlights, so decoded userdata, JSON diffs
+// and tag tables are searched as shown. Once the query is committed, matching
+// resources are expanded and the folds hiding an occurrence are opened.
+func (m *Model) performContentSearch() {
+	query := strings.TrimSpace(m.searchQuery)
+	m.contentMatchCursor = 0
+	if query == "" {
+		return
+	}
+
+	for displayIdx, resourceIdx := range m.sortedResources() {
+		r := m.plan.Resources[resourceIdx]
+		headerHits := countContentMatches(m.renderResourceLine(r, true, false), query)
+		bodyHits := 0
+		if len(r.RawLines) > 1 {
+			bodyHits = countContentMatches(m.renderOpenBody(resourceIdx).text, query)
+		}
+		if headerHits == 0 && bodyHits == 0 {
+			continue
+		}
+		m.searchMatches = append(m.searchMatches, displayIdx)
+		if bodyHits > 0 && !m.searching {
+			m.expanded[resourceIdx] = true
+			m.openFoldsWithMatches(resourceIdx, query)
+		}
+	}
+
+	if len(m.searchMatches) > 0 {
+		m.cursor = 0
+		m.currentMatch = 0
+		m.blockCursor = -1
+	}
+}
+
+// countContentMatches counts the occurrences of query that
+// highlightContentMatches would highlight in the rendered text s.
+func countContentMatches(s, query string) int {
+	_, lines := highlightContentMatches(s, query, -1)
+	return len(lines)
+}
+
+// renderOpenBody returns the body of a resource rendered with every fold
+// open, cached like renderBody.
+func (m *Model) renderOpenBody(resourceIdx int) *renderedBody {
+	key := bodyRenderKey{
+		width:          m.viewport.Width,
+		screenWidth:    m.width,
+		blockCursor:    -1,
+		diffContext:    m.diffContextSize(),
+		sideBySide:     m.sideBySide[resourceIdx],
+		showSuppressed: m.showSuppressed,
+		styleVersion:   styleVersion,
+	}
+	if body, ok := m.cache().openBodies[resourceIdx]; ok && body.key == key {
+		return body
+	}
+	r := m.plan.Resources[resourceIdx]
+	lines, _ := m.expandedLines(r)
+	folds := make(map[string]bool)
+	for _, block := range findFoldBlocks(r, lines) {
+		folds[block.Key] = false
+	}
+	c := *m
+	c.foldedBlocks = folds
+	c.renders = newRenderCache()
+	body := c.renderBody(resourceIdx, false)
+	body.key = key
+	m.cache().openBodies[resourceIdx] = body
+	return body
+}
+
+// openFoldsWithMatches expands every fold whose body holds one of the
+// resource's occurrences, located by line in the open rendering.
+func (m *Model) openFoldsWithMatches(resourceIdx int, query string) {
+	body := m.renderOpenBody(resourceIdx)
+	_, matchLines := highlightContentMatches(body.text, query, -1)
+	for _, fold := range body.folds {
+		for _, line := range matchLines {
+			if line > fold.start && line < fold.end {
+				m.setFold(resourceIdx, fold.block, false)
+				break
+			}
+		}
+	}
+}
+
+// stepContentMatch moves to the next (delta 1) or previous (delta -1)
+// highlighted occurrence, selecting its resource and scrolling it into view.
+func (m *Model) stepContentMatch(delta int) {
+	n := len(m.contentMatchLines)
+	if n == 0 {
+		return
+	}
+	m.contentMatchCursor = ((m.contentMatchCursor+delta)%n + n) % n
+	if m.split {
+		m.updateViewportContent()
+		if m.contentMatchCursor < len(m.contentMatchLines) {
+			line := m.contentMatchLines[m.contentMatchCursor]
+			if top := m.detailViewport.YOffset; line < top || line >= top+m.detailViewport.Height {
+				m.detailViewport.SetYOffset(max(0, line-m.detailViewport.Height/2))
+			}
+		}
+		return
+	}
+	line := m.contentMatchLines[m.contentMatchCursor]
+	for displayIdx := len(m.resourceLineStarts) - 1; displayIdx >= 0; displayIdx-- {
+		if m.resourceLineStarts[displayIdx] <= line {
+			m.cursor = displayIdx
+			m.currentMatch = displayIdx
+			break
+		}
+	}
+	m.blockCursor = -1
+	m.updateViewportContent()
+	m.scrollLineIntoView(m.contentMatchLines[m.contentMatchCursor])
+}
+
+// scrollLineIntoView centres line in the viewport when it is off screen.
+func (m *Model) scrollLineIntoView(line int) {
+	if !m.ready {
+		return
+	}
+	top := m.viewport.YOffset
+	if line >= top && line < top+m.viewport.Height {
+		return
+	}
+	m.viewport.SetYOffset(max(0, line-m.viewport.Height/2))
+	m.syncRenderWindow()
+}
+
+// viewContentSearchBar renders the search bar for content search.
+func (m Model) viewContentSearchBar() string {
+	if m.searching {
+		return searchStyle.Render("Content search: ") + m.searchInput.View() + "\n\n"
+	}
+	current := 0
+	if len(m.contentMatchLines) > 0 {
+		current = m.contentMatchCursor + 1
+	}
+	return searchStyle.Render(fmt.Sprintf("Content search: %q (%d/%d occurrences in %d resources)",
+		m.searchQuery, current, len(m.contentMatchLines), len(m.searchMatches))) + "\n\n"
+}
+
+// highlightContentMatches highlights every case-insensitive occurrence of
+// query in the styled text s, which may already contain ANSI escape codes.
+// The occurrence numbered current gets currentMatchStyle. It returns the
+// highlighted text and the line number of each occurrence.
+func highlightContentMatches(s, query string, current int) (string, []int) {
+	query = asciiLower(query)
+	if query == "" {
+		return s, nil
+	}
+	matchOpen := styleOpenSequence(matchStyle)
+	currentOpen := styleOpenSequence(currentMatchStyle)
+
+	lines := strings.Split(s, "\n")
+	var matchLines []int
+	for i, line := range lines {
+		visible := visibleText(line)
+		lower := asciiLower(visible)
+		var starts []int
+		for from := 0; ; {
+			idx := strings.Index(lower[from:], query)
+			if idx < 0 {
+				break
+			}
+			starts = append(starts, from+idx)
+			from += idx + len(query)
+		}
+		if len(starts) == 0 {
+			continue
+		}
+		opens := make([]string, len(starts))
+		for j := range starts {
+			opens[j] = matchOpen
+			if len(matchLines) == current {
+				opens[j] = currentOpen
+			}
+			matchLines = append(matchLines, i)
+		}
+		if matchOpen != "" {
+			lines[i] = insertHighlights(line, starts, len(query), opens)
+		}
+	}
+	return strings.Join(lines, "\n"), matchLines
+}
+
+// visibleText strips ANSI escape sequences from line.
+func visibleText(line string) string {
+	var b strings.Builder
+	for i := 0; i < len(line); {
+		if n := escapeSequenceLen(line[i:]); n > 0 {
+			i += n
+			continue
+		}
+		b.WriteByte(line[i])
+		i++
+	}
+	return b.String()
+}
+
+// insertHighlights wraps the visible ranges [starts[j], starts[j]+length)
+// of line in opens[j] and restores the styles that were active before.
+func insertHighlights(line string, starts []int, length int, opens []string) string {
+	var b strings.Builder
+	var active []string
+	match, end := -1, -1
+	next := 0
+	for vis, pos := 0, 0; pos < len(line); {
+		if n := escapeSequenceLen(line[pos:]); n > 0 {
+			seq := line[pos : pos+n]
+			b.WriteString(seq)
+			if seq == "\x1b[0m" || seq == "\x1b[m" {
+				active = active[:0]
+			} else if strings.HasSuffix(seq, "m") {
+				active = append(active, seq)
+			}
+			if match >= 0 {
+				b.WriteString(opens[match])
+			}
+			pos += n
+			continue
+		}
+		if next < len(starts) && vis == starts[next] {
+			match, end = next, starts[next]+length
+			b.WriteString(opens[match])
+			next++
+		}
+		b.WriteByte(line[pos])
+		pos++
+		vis++
+		if match >= 0 && vis == end {
+			b.WriteString("\x1b[0m")
+			b.WriteString(strings.Join(active, ""))
+			match = -1
+		}
+	}
+	return b.String()
+}
+
+// escapeSequenceLen returns the length of the ANSI escape sequence at the
+// start of s, or 0.
+func escapeSequenceLen(s string) int {
+	if len(s) < 2 || s[0] != '\x1b' {
+		return 0
+	}
+	if s[1] != '[' {
+		return 2
+	}
+	for i := 2; i < len(s); i++ {
+		if s[i] >= 0x40 && s[i] <= 0x7e {
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// styleOpenSequence returns the escape codes style emits before its text,
+// or "" when the terminal has no color support.
+func styleOpenSequence(style lipgloss.Style) string {
+	open, _, _ := strings.Cut(style.Render("x"), "x")
+	return open
+}
+
+// asciiLower lowercases ASCII letters only, so byte offsets are preserved.
+func asciiLower(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if c >= 'A' && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,191 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+func contentSearchPlan() *parser.Plan {
+	return &parser.Plan{Resources: []parser.Resource{
+		{Address: "aws_instance.web", Type: "aws_instance", Action: parser.ActionUpdate, RawLines: []string{
+			`  ~ resource "aws_instance" "web" {`,
+			`      ~ ami           = "ami-0abc" -> "ami-0def"`,
+			`      ~ root_block_device {`,
+			`          ~ snapshot_id = "snap-1" -> "snap-2"`,
+			`            tags        = { "Source" = "ami-0abc" }`,
+			`        }`,
+			`    }`,
+		}},
+		{Address: "aws_s3_bucket.logs", Type: "aws_s3_bucket", Action: parser.ActionCreate, RawLines: []string{
+			`  + resource "aws_s3_bucket" "logs" {`,
+			`      + bucket = "my-logs"`,
+			`    }`,
+		}},
+	}}
+}
+
+func TestContentSearchExpandsMatchingResourcesAndFolds(t *testing.T) {
+	m := NewModel(contentSearchPlan(), "test")
+	r := m.plan.Resources[0]
+	blocks := findFoldBlocks(r, r.RawLines[1:])
+	m.foldedBlocks[blocks[0].Key] = true
+
+	m, _, _ = handleKeyContentSearch(m)
+	m.searchQuery = "AMI-0ABC"
+	m.performSearch()
+	if got := m.displayedResourceIndices(); len(got) != 1 || got[0] != 0 {
+		t.Fatalf("expected only aws_instance.web to match, got %v", got)
+	}
+	if m.expanded[0] {
+		t.Error("resources should not expand while the query is being typed")
+	}
+
+	m.searching = false
+	m.performSearch()
+	if !m.expanded[0] {
+		t.Error("committed search should expand the matching resource")
+	}
+	if m.isFoldCollapsed(blocks[0]) {
+		t.Error("fold containing a match should be opened")
+	}
+	if m.expanded[1] {
+		t.Error("non-matching resource should stay collapsed")
+	}
+}
+
+func TestContentSearchStepsThroughOccurrences(t *testing.T) {
+	m := NewModel(contentSearchPlan(), "test")
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+
+	m, _, _ = handleKeyContentSearch(m)
+	m.searching = false
+	m.searchQuery = "ami-0abc"
+	m.performSearch()
+	m.updateViewportContent()
+
+	// Two occurrences in the body; the resource line itself does not match.
+	if len(m.contentMatchLines) != 2 {
+		t.Fatalf("expected 2 occurrences, got %v", m.contentMatchLines)
+	}
+	first := m.contentMatchLines[0]
+	m.nextMatch()
+	if m.contentMatchCursor != 1 || m.contentMatchLines[1] <= first {
+		t.Errorf("n should move to the second occurrence, cursor=%d lines=%v", m.contentMatchCursor, m.contentMatchLines)
+	}
+	m.nextMatch()
+	if m.contentMatchCursor != 0 {
+		t.Errorf("n should wrap around, cursor=%d", m.contentMatchCursor)
+	}
+	m.prevMatch()
+	if m.contentMatchCursor != 1 {
+		t.Errorf("N should wrap backwards, cursor=%d", m.contentMatchCursor)
+	}
+	if bar := stripRenderANSI(m.viewSearchBar()); !strings.Contains(bar, `Content search: "ami-0abc" (2/2 occurrences in 1 resources)`) {
+		t.Errorf("unexpected search bar %q", bar)
+	}
+
+	m.clearSearch()
+	if m.contentSearch || m.contentMatchLines != nil {
+		t.Error("clearing the search should leave content search mode")
+	}
+}
+
+func TestContentSearchMatchesRenderedText(t *testing.T) {
+	m := NewModel(contentSearchPlan(), "test")
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+	m, _, _ = handleKeyContentSearch(m)
+	m.searching = false
+
+	// The raw plan's "->" is drawn as an arrow, so it is not a visible hit.
+	m.searchQuery = "->"
+	m.performSearch()
+	if len(m.searchMatches) != 0 {
+		t.Errorf("raw-only text should not match, got %v", m.searchMatches)
+	}
+
+	r := m.plan.Resources[0]
+	block := findFoldBlocks(r, r.RawLines[1:])[0]
+	m.foldedBlocks[block.Key] = true
+	m.searchQuery = "→"
+	m.performSearch()
+	m.updateViewportContent()
+	if len(m.searchMatches) != 1 || len(m.contentMatchLines) != 2 {
+		t.Fatalf("expected 2 arrows in 1 resource, got %v in %v", m.contentMatchLines, m.searchMatches)
+	}
+	if m.isFoldCollapsed(block) {
+		t.Error("the fold holding an occurrence should be opened")
+	}
+	if bar := stripRenderANSI(m.viewSearchBar()); !strings.Contains(bar, "(1/2 occurrences in 1 resources)") {
+		t.Errorf("unexpected search bar %q", bar)
+	}
+}
+
+func TestInsertHighlightsRestoresStyles(t *testing.T) {
+	line := "\x1b[32mid = ami-123 ok\x1b[0m"
+	got := insertHighlights(line, []int{5}, 7, []string{"<H>"})
+	want := "\x1b[32mid = <H>ami-123\x1b[0m\x1b[32m ok\x1b[0m"
+	if got != want {
+		t.Errorf("insertHighlights = %q, want %q", got, want)
+	}
+
+	split := "ami-\x1b[1m123\x1b[0m"
+	got = insertHighlights(split, []int{0}, 7, []string{"<H>"})
+	want = "<H>ami-\x1b[1m<H>123\x1b[0m\x1b[1m\x1b[0m"
+	if got != want {
+		t.Errorf("match across escape codes = %q, want %q", got, want)
+	}
+}
+
+func TestHighlightContentMatchesReportsLines(t *testing.T) {
+	_, lines := highlightContentMatches("a CIDR\nnone\n\x1b[31mcidr\x1b[0m and cidr", "cidr", 0)
+	if len(lines) != 3 || lines[0] != 0 || lines[1] != 2 || lines[2] != 2 {
+		t.Errorf("match lines = %v", lines)
+	}
+}
+
+func TestContentSearchOpensOnlyFoldsWithMatches(t *testing.T) {
+	m := NewModel(&parser.Plan{Resources: []parser.Resource{
+		{Address: "aws_instance.web", Type: "aws_instance", Action: parser.ActionUpdate, RawLines: []string{
+			`  ~ resource "aws_instance" "web" {`,
+			`      ~ user_data_description = "a long value that wraps over several lines in a narrow viewport" -> "b"`,
+			`      ~ root_block_device {`,
+			`          ~ volume_size = 10 -> 20`,
+			`        }`,
+			`      ~ network_interface {`,
+			`          ~ device_index = 0 -> 1`,
+			`          ~ private_ip {`,
+			`              ~ address = "10.0.0.1" -> "10.0.0.9"`,
+			`            }`,
+			`        }`,
+			`    }`,
+		}},
+	}}, "test")
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 40, Height: 40})
+	m = updated.(Model)
+	r := m.plan.Resources[0]
+	blocks := findFoldBlocks(r, r.RawLines[1:])
+	if len(blocks) != 3 {
+		t.Fatalf("expected 3 folds, got %d", len(blocks))
+	}
+	for _, block := range blocks {
+		m.foldedBlocks[block.Key] = true
+	}
+
+	m, _, _ = handleKeyContentSearch(m)
+	m.searching = false
+	m.searchQuery = "10.0.0.9"
+	m.performSearch()
+	for _, block := range blocks {
+		header := r.RawLines[1+block.Start]
+		wantOpen := !strings.Contains(header, "root_block_device")
+		if open := !m.isFoldCollapsed(block); open != wantOpen {
+			t.Errorf("fold %q open = %v, want %v", strings.TrimSpace(header), open, wantOpen)
+		}
+	}
+}
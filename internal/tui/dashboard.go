@@ -0,0 +1,308 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+// dashboardEntry is one selectable row of the plan dashboard. Enter shows
+// the main list filtered by Action (status filter) or Query.
+type dashboardEntry struct {
+	Section string
+	Label   string
+	Count   int    // resources the entry's filter shows
+	Detail  string // optional note after the count, e.g. "42 values"
+	Action  parser.Action
+	Query   string
+}
+
+const maxDashboardTopTypes = 5
+
+// buildDashboard aggregates the plan into dashboard sections: actions,
+// providers, modules, resource types, top changed types and value counts.
+func buildDashboard(plan *parser.Plan) []dashboardEntry {
+	if plan == nil {
+		return nil
+	}
+
+	actionCounts := make(map[parser.Action]int)
+	providers := make(map[string]int)
+	modules := make(map[string]int)
+	types := make(map[string]int)
+	typeAttrs := make(map[string]int)
+	rootResources := 0
+	var computed, computedResources, sensitive, sensitiveResources, forced, forcedResources int
+
+	for _, r := range plan.Resources {
+		actionCounts[r.Action]++
+		if r.Action == parser.ActionOutput || r.Type == "" {
+			continue
+		}
+		providers[resourceProvider(r)]++
+		types[r.Type]++
+		typeAttrs[r.Type] += len(r.Attributes)
+
+		seen := make(map[string]bool)
+		for _, module := range addressModules(r.Address) {
+			if !seen[module] {
+				seen[module] = true
+				modules[module]++
+			}
+		}
+		if len(seen) == 0 {
+			rootResources++
+		}
+
+		if n := countComputedValues(r); n > 0 {
+			computed += n
+			computedResources++
+		}
+		if n := countSensitiveValues(r); n > 0 {
+			sensitive += n
+			sensitiveResources++
+		}
+		if n := countForcedReplacements(r); n > 0 {
+			forced += n
+			forcedResources++
+		}
+	}
+
+	var entries []dashboardEntry
+	for _, action := range filterableActions {
+		if n := actionCounts[action]; n > 0 {
+			entries = append(entries, dashboardEntry{Section: "Actions", Label: filterActionLabel(action), Count: n, Action: action})
+		}
+	}
+	for _, kv := range sortedCounts(providers) {
+		entries = append(entries, dashboardEntry{Section: "Providers", Label: kv.key, Count: kv.count, Query: "provider:" + kv.key})
+	}
+	if len(modules) > 0 {
+		if rootResources > 0 {
+			entries = append(entries, dashboardEntry{Section: "Modules", Label: "(root)", Count: rootResources, Query: "!module:*"})
+		}
+		for _, kv := range sortedCounts(modules) {
+			entries = append(entries, dashboardEntry{Section: "Modules", Label: "module." + kv.key, Count: kv.count, Query: "module:" + kv.key})
+		}
+	}
+	for _, kv := range sortedCounts(types) {
+		entries = append(entries, dashboardEntry{Section: "Resource types", Label: kv.key, Count: kv.count, Query: "type:" + kv.key})
+	}
+
+	topTypes := sortedCounts(typeAttrs)
+	if len(topTypes) > maxDashboardTopTypes {
+		topTypes = topTypes[:maxDashboardTopTypes]
+	}
+	for _, kv := range topTypes {
+		if kv.count == 0 {
+			break
+		}
+		entries = append(entries, dashboardEntry{Section: "Top changed types", Label: kv.key, Count: types[kv.key],
+			Detail: fmt.Sprintf("%d changed attribute(s)", kv.count), Query: "type:" + kv.key})
+	}
+
+	entries = append(entries,
+		dashboardEntry{Section: "Values", Label: "(known after apply)", Count: computedResources, Detail: fmt.Sprintf("%d value(s)", computed), Query: "unknown"},
+		dashboardEntry{Section: "Values", Label: "sensitive", Count: sensitiveResources, Detail: fmt.Sprintf("%d change(s)", sensitive), Query: "sensitive"},
+		dashboardEntry{Section: "Values", Label: "forces replacement", Count: forcedResources, Detail: fmt.Sprintf("%d attribute(s)", forced), Query: "forces-replacement"},
+	)
+	return entries
+}
+
+type keyCount struct {
+	key   string
+	count int
+}
+
+// sortedCounts orders a count map by count, then key.
+func sortedCounts(counts map[string]int) []keyCount {
+	sorted := make([]keyCount, 0, len(counts))
+	for key, count := range counts {
+		sorted = append(sorted, keyCount{key, count})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].count != sorted[j].count {
+			return sorted[i].count > sorted[j].count
+		}
+		return sorted[i].key < sorted[j].key
+	})
+	return sorted
+}
+
+// resourceProvider derives the provider from the resource type prefix,
+// e.g. "aws" for aws_instance.
+func resourceProvider(r parser.Resource) string {
+	provider, _, _ := strings.Cut(r.Type, "_")
+	return provider
+}
+
+func countComputedValues(r parser.Resource) int {
+	n := 0
+	for _, attr := range r.Attributes {
+		if attr.Computed {
+			n++
+		}
+	}
+	return n
+}
+
+func countSensitiveValues(r parser.Resource) int {
+	n := 0
+	for _, attr := range r.Attributes {
+		if attr.Sensitive {
+			n++
+		}
+	}
+	return n
+}
+
+// countForcedReplacements counts the lines Terraform marks with
+// "# forces replacement", including nested blocks.
+func countForcedReplacements(r parser.Resource) int {
+	n := 0
+	for _, line := range r.RawLines {
+		if strings.Contains(line, "# forces replacement") {
+			n++
+		}
+	}
+	return n
+}
+
+func handleKeyDashboard(m Model) (Model, tea.Cmd, bool) {
+	m.dashboardEntries = buildDashboard(m.plan)
+	m.dashboard = true
+	m.dashboardCursor = 0
+	return m, nil, true
+}
+
+// handleDashboardKey handles key presses in the dashboard view
+func (m Model) handleDashboardKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q", "D":
+		m.dashboard = false
+		m.updateViewportContent()
+	case "ctrl+c":
+		return m, tea.Quit
+	case "enter", " ", "l", "right":
+		if m.dashboardCursor >= 0 && m.dashboardCursor < len(m.dashboardEntries) {
+			m.applyDashboardEntry(m.dashboardEntries[m.dashboardCursor])
+		}
+	case "up", "k":
+		if m.dashboardCursor > 0 {
+			m.dashboardCursor--
+		}
+	case "down", "j":
+		if m.dashboardCursor < len(m.dashboardEntries)-1 {
+			m.dashboardCursor++
+		}
+	case "d", "ctrl+d", "pgdown":
+		m.dashboardCursor = min(len(m.dashboardEntries)-1, m.dashboardCursor+m.dashboardPageSize()/2)
+	case "u", "ctrl+u", "pgup":
+		m.dashboardCursor = max(0, m.dashboardCursor-m.dashboardPageSize()/2)
+	case "g", "home":
+		m.dashboardCursor = 0
+	case "G", "end":
+		m.dashboardCursor = max(0, len(m.dashboardEntries)-1)
+	}
+	return m, nil
+}
+
+// applyDashboardEntry replaces the current filters with the entry's filter
+// and returns to the main list.
+func (m *Model) applyDashboardEntry(entry dashboardEntry) {
+	m.statusFilters = nil
+	m.attrFilter = ""
+	m.attrFilterResources = nil
+	m.query = nil
+	m.searchQuery = ""
+	m.searchMatches = []int{}
+	m.contentSearch = false
+	m.searchInput.SetValue("")
+	if entry.Action != "" {
+		m.statusFilters = map[parser.Action]bool{entry.Action: true}
+	}
+	if entry.Query != "" {
+		// Dashboard queries are built from valid fields, so this cannot fail.
+		m.query, _ = parsePlanQuery(entry.Query)
+	}
+	m.dashboard = false
+	m.cursor = 0
+	m.clampCursorAndRefreshSearch()
+	m.updateViewportContent()
+	m.viewport.GotoTop()
+}
+
+// dashboardPageSize returns how many rows (entries and section titles) fit.
+func (m Model) dashboardPageSize() int {
+	return max(5, m.height-8)
+}
+
+// viewDashboard renders the plan summary dashboard (returns full view, caller returns early).
+func (m Model) viewDashboard() string {
+	var b strings.Builder
+	b.WriteString(m.viewHeader())
+	b.WriteString(searchStyle.Render("Plan dashboard — Enter: show resources, Esc: close"))
+	b.WriteString("\n\n")
+
+	labelWidth := 0
+	for _, entry := range m.dashboardEntries {
+		labelWidth = max(labelWidth, lipgloss.Width(entry.Label))
+	}
+	labelWidth = min(labelWidth, 48)
+
+	// Section titles take a row each, so page over rendered rows.
+	var rows []string
+	cursorRow := 0
+	section := ""
+	for i, entry := range m.dashboardEntries {
+		if entry.Section != section {
+			if section != "" {
+				rows = append(rows, "")
+			}
+			section = entry.Section
+			rows = append(rows, headerStyle.UnsetMarginBottom().Render(section))
+		}
+		label := entry.Label
+		if lipgloss.Width(label) > labelWidth {
+			label = truncate.StringWithTail(label, uint(labelWidth), "…")
+		}
+		row := fmt.Sprintf("  %s  %4d resource(s)", padCell(label, labelWidth), entry.Count)
+		if entry.Detail != "" {
+			row += "  " + entry.Detail
+		}
+		rowStyle := lipgloss.NewStyle().Foreground(textColor)
+		if entry.Action != "" {
+			rowStyle = rowStyle.Foreground(GetActionColor(string(entry.Action)))
+		}
+		if i == m.dashboardCursor {
+			rowStyle = rowStyle.Background(selectedBg).Bold(true)
+			cursorRow = len(rows)
+		}
+		rows = append(rows, rowStyle.Render(row))
+	}
+
+	pageSize := m.dashboardPageSize()
+	start := 0
+	if cursorRow >= pageSize {
+		start = cursorRow - pageSize + 1
+	}
+	end := min(len(rows), start+pageSize)
+	for _, row := range rows[start:end] {
+		b.WriteString(row)
+		b.WriteString("\n")
+	}
+	if end < len(rows) {
+		b.WriteString(mutedColor.Render(fmt.Sprintf("  ... %d more", len(rows)-end)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("j/k: navigate • d/u: page • g/G: top/bottom • Enter: show resources • Esc/D: close"))
+	return appStyle.Render(b.String())
+}
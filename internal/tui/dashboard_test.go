@@ -0,0 +1,159 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+const dashboardTestPlan = `
+  # aws_instance.web must be replaced
+-/+ resource "aws_instance" "web" {
+      ~ ami           = "ami-1" -> "ami-2" # forces replacement
+      ~ id            = "i-123" -> (known after apply)
+      ~ private_ip    = "10.0.0.1" -> (known after apply)
+    }
+
+  # module.network.aws_subnet.a will be created
+  + resource "aws_subnet" "a" {
+      + cidr_block = "10.0.1.0/24"
+      + id         = (known after apply)
+    }
+
+  # module.network.module.dns.google_dns_record_set.www will be updated in-place
+  ~ resource "google_dns_record_set" "www" {
+      ~ ttl      = 300 -> 60
+      ~ rrdatas  = (sensitive value)
+    }
+
+Plan: 2 to add, 1 to change, 1 to destroy.
+`
+
+func dashboardEntryFor(t *testing.T, entries []dashboardEntry, section, label string) dashboardEntry {
+	t.Helper()
+	for _, entry := range entries {
+		if entry.Section == section && entry.Label == label {
+			return entry
+		}
+	}
+	t.Fatalf("no %s entry %q in %+v", section, label, entries)
+	return dashboardEntry{}
+}
+
+func TestBuildDashboard(t *testing.T) {
+	plan, err := parser.Parse(dashboardTestPlan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := buildDashboard(plan)
+
+	tests := []struct {
+		section, label string
+		count          int
+		detail         string
+	}{
+		{"Actions", "create", 1, ""},
+		{"Providers", "aws", 2, ""},
+		{"Providers", "google", 1, ""},
+		{"Modules", "(root)", 1, ""},
+		{"Modules", "module.network", 2, ""},
+		{"Modules", "module.dns", 1, ""},
+		{"Resource types", "aws_subnet", 1, ""},
+		{"Top changed types", "aws_instance", 1, "3 changed attribute(s)"},
+		{"Values", "(known after apply)", 2, "3 value(s)"},
+		{"Values", "sensitive", 1, "1 change(s)"},
+		{"Values", "forces replacement", 1, "1 attribute(s)"},
+	}
+	for _, tt := range tests {
+		entry := dashboardEntryFor(t, entries, tt.section, tt.label)
+		if entry.Count != tt.count || entry.Detail != tt.detail {
+			t.Errorf("%s %q = %d %q, want %d %q", tt.section, tt.label, entry.Count, entry.Detail, tt.count, tt.detail)
+		}
+	}
+
+	// Every entry's filter shows exactly the resources it counts.
+	for _, entry := range entries {
+		m := NewModel(plan, "test")
+		m.applyDashboardEntry(entry)
+		if got := len(m.filteredResources()); got != entry.Count {
+			t.Errorf("%s %q shows %d resources, counted %d", entry.Section, entry.Label, got, entry.Count)
+		}
+	}
+}
+
+func TestDashboardJumpsToFilteredList(t *testing.T) {
+	plan, err := parser.Parse(dashboardTestPlan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewModel(plan, "test")
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+	m, _ = m.WithQuery("action:create")
+
+	m = pressKeys(m, "D")
+	if view := stripRenderANSI(m.View()); !strings.Contains(view, "Plan dashboard") || !strings.Contains(view, "module.network") {
+		t.Fatalf("unexpected dashboard view:\n%s", view)
+	}
+	for m.dashboardEntries[m.dashboardCursor].Label != "google" {
+		m = pressKeys(m, "j")
+	}
+	m = pressKeys(m, "enter")
+	if m.dashboard || m.query == nil || m.query.Expr != "provider:google" {
+		t.Fatalf("enter should close the dashboard and replace the query, got %+v", m.query)
+	}
+	view := stripRenderANSI(m.View())
+	if !strings.Contains(view, "google_dns_record_set.www") || strings.Contains(view, "aws_subnet.a") {
+		t.Errorf("list should only show the google resource:\n%s", view)
+	}
+}
+
+func TestDashboardTruncatesWideLabels(t *testing.T) {
+	plan, err := parser.Parse(dashboardTestPlan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewModel(plan, "test")
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+	m = pressKeys(m, "D")
+	m.dashboardEntries = []dashboardEntry{{Section: "Modules", Label: "module." + strings.Repeat("é", 60), Count: 1}}
+
+	view := m.viewDashboard()
+	if !utf8.ValidString(view) {
+		t.Fatalf("truncation split a multi-byte rune:\n%q", view)
+	}
+	if !strings.Contains(stripRenderANSI(view), "module."+strings.Repeat("é", 40)+"…") {
+		t.Errorf("long label should be cut to 48 columns with an ellipsis:\n%s", stripRenderANSI(view))
+	}
+}
+
+func TestDashboardAlignsWideLabels(t *testing.T) {
+	plan, err := parser.Parse(dashboardTestPlan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewModel(plan, "test")
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+	m = pressKeys(m, "D")
+	m.dashboardEntries = []dashboardEntry{
+		{Section: "Modules", Label: "module.日本語", Count: 1},
+		{Section: "Modules", Label: "module.abcdef", Count: 2},
+	}
+
+	columns := map[int]bool{}
+	for _, line := range strings.Split(stripRenderANSI(m.viewDashboard()), "\n") {
+		if idx := strings.Index(line, " resource(s)"); idx >= 0 {
+			columns[lipgloss.Width(line[:idx])] = true
+		}
+	}
+	if len(columns) != 1 {
+		t.Errorf("resource counts should line up by display width, got columns %v", columns)
+	}
+}
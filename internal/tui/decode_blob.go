@@ -0,0 +1,385 @@
+package tui
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DecodedBlob is a readable summary of an encoded attribute value.
+type DecodedBlob struct {
+	Kind  string   // "certificate", "private key", "jwt", "json", ...
+	Lines []string // summary lines; never contains private key material
+}
+
+// now is overridden in tests so expiry hints are stable.
+var now = time.Now
+
+// TryDecodeBlob detects PEM certificates and keys, JWTs and base64-encoded
+// JSON documents. Like TryDecodeUserdata it never fails loudly: unknown
+// values return false so the caller shows them unchanged.
+func TryDecodeBlob(s string) (blob DecodedBlob, ok bool) {
+	defer func() {
+		if recover() != nil {
+			blob = DecodedBlob{}
+			ok = false
+		}
+	}()
+
+	s = strings.TrimSpace(s)
+	if s == "" || s == "null" || strings.HasPrefix(s, "(") {
+		return DecodedBlob{}, false
+	}
+	if strings.Contains(s, "-----BEGIN ") {
+		return decodePEMBlob(s)
+	}
+	if blob, ok := decodeJWT(s); ok {
+		return blob, true
+	}
+	return decodeBase64JSON(s)
+}
+
+func decodePEMBlob(s string) (DecodedBlob, bool) {
+	var blocks []*pem.Block
+	rest := []byte(s)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		blocks = append(blocks, block)
+	}
+	if len(blocks) == 0 {
+		return DecodedBlob{}, false
+	}
+
+	blob := DecodedBlob{}
+	for i, block := range blocks {
+		if len(blocks) > 1 {
+			blob.Lines = append(blob.Lines, fmt.Sprintf("[%d/%d] %s", i+1, len(blocks), strings.ToLower(block.Type)))
+		}
+		switch {
+		case strings.Contains(block.Type, "PRIVATE KEY"):
+			setBlobKind(&blob, "private key")
+			blob.Lines = append(blob.Lines, strings.ToLower(block.Type)+" (content hidden)")
+		case block.Type == "CERTIFICATE":
+			setBlobKind(&blob, "certificate")
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				blob.Lines = append(blob.Lines, "certificate (unparseable)")
+				continue
+			}
+			blob.Lines = append(blob.Lines, certificateSummary(cert)...)
+		case block.Type == "CERTIFICATE REQUEST":
+			setBlobKind(&blob, "certificate request")
+			if csr, err := x509.ParseCertificateRequest(block.Bytes); err == nil {
+				blob.Lines = append(blob.Lines, "subject: "+csr.Subject.String())
+				if sans := certificateSANs(csr.DNSNames, csr.IPAddresses, csr.EmailAddresses, csr.URIs); sans != "" {
+					blob.Lines = append(blob.Lines, "SANs: "+sans)
+				}
+			}
+		default:
+			setBlobKind(&blob, strings.ToLower(block.Type))
+			blob.Lines = append(blob.Lines, fmt.Sprintf("%s (%d bytes)", strings.ToLower(block.Type), len(block.Bytes)))
+		}
+	}
+	return blob, true
+}
+
+// setBlobKind records the most sensitive kind seen in a PEM bundle, so a
+// bundle holding a private key is always labelled as one.
+func setBlobKind(blob *DecodedBlob, kind string) {
+	if blob.Kind == "private key" {
+		return
+	}
+	if blob.Kind == "" || kind == "private key" {
+		blob.Kind = kind
+		return
+	}
+	if blob.Kind != kind {
+		blob.Kind = "pem bundle"
+	}
+}
+
+func certificateSummary(cert *x509.Certificate) []string {
+	lines := []string{
+		"subject: " + cert.Subject.String(),
+		"issuer: " + cert.Issuer.String(),
+	}
+	if sans := certificateSANs(cert.DNSNames, cert.IPAddresses, cert.EmailAddresses, cert.URIs); sans != "" {
+		lines = append(lines, "SANs: "+sans)
+	}
+	lines = append(lines,
+		"not before: "+cert.NotBefore.UTC().Format(time.RFC3339),
+		"expires: "+cert.NotAfter.UTC().Format(time.RFC3339)+" ("+expiryHint(cert.NotAfter)+")",
+		fmt.Sprintf("serial: %X", cert.SerialNumber),
+	)
+	return lines
+}
+
+func certificateSANs[IP fmt.Stringer, U fmt.Stringer](dns []string, ips []IP, emails []string, uris []U) string {
+	sans := append([]string{}, dns...)
+	for _, ip := range ips {
+		sans = append(sans, ip.String())
+	}
+	sans = append(sans, emails...)
+	for _, u := range uris {
+		sans = append(sans, u.String())
+	}
+	return strings.Join(sans, ", ")
+}
+
+func expiryHint(t time.Time) string {
+	d := t.Sub(now())
+	if d < 0 {
+		return "EXPIRED"
+	}
+	days := int(d.Hours() / 24)
+	if days == 1 {
+		return "in 1 day"
+	}
+	return fmt.Sprintf("in %d days", days)
+}
+
+// decodeJWT decodes the header and claims of a compact JWT. The signature is
+// never shown.
+func decodeJWT(s string) (DecodedBlob, bool) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 || !strings.HasPrefix(parts[0], "eyJ") {
+		return DecodedBlob{}, false
+	}
+	header, ok := decodeBase64URLJSON(parts[0])
+	if !ok {
+		return DecodedBlob{}, false
+	}
+	if _, hasAlg := header.(map[string]any)["alg"]; !hasAlg {
+		return DecodedBlob{}, false
+	}
+	claims, ok := decodeBase64URLJSON(parts[1])
+	if !ok {
+		return DecodedBlob{}, false
+	}
+
+	blob := DecodedBlob{Kind: "jwt"}
+	blob.Lines = append(blob.Lines, "header: "+canonicalJSON(header, ""))
+	blob.Lines = append(blob.Lines, "claims:")
+	for _, l := range strings.Split(canonicalJSON(claims, "  "), "\n") {
+		blob.Lines = append(blob.Lines, "  "+l)
+	}
+	if obj, ok := claims.(map[string]any); ok {
+		if exp, ok := obj["exp"].(json.Number); ok {
+			if secs, err := exp.Int64(); err == nil {
+				t := time.Unix(secs, 0)
+				blob.Lines = append(blob.Lines, "expires: "+t.UTC().Format(time.RFC3339)+" ("+expiryHint(t)+")")
+			}
+		}
+	}
+	return blob, true
+}
+
+func decodeBase64URLJSON(s string) (any, bool) {
+	for _, enc := range []*base64.Encoding{base64.RawURLEncoding, base64.URLEncoding} {
+		if b, err := enc.DecodeString(s); err == nil {
+			dec := json.NewDecoder(strings.NewReader(string(b)))
+			dec.UseNumber()
+			var v map[string]any
+			if dec.Decode(&v) == nil {
+				return v, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// decodeBase64JSON pretty-prints base64 values that hold a JSON document.
+func decodeBase64JSON(s string) (DecodedBlob, bool) {
+	if len(s) < 16 || strings.ContainsAny(s, " \t") {
+		return DecodedBlob{}, false
+	}
+	decoded, ok := tryBase64Variants(stripBase64Whitespace(s))
+	if !ok {
+		return DecodedBlob{}, false
+	}
+	doc, ok := parseJSONDocument(string(decoded))
+	if !ok {
+		return DecodedBlob{}, false
+	}
+	return DecodedBlob{Kind: "json", Lines: strings.Split(canonicalJSON(doc, "  "), "\n")}, true
+}
+
+// blobDiff diffs two decoded blobs. Values whose summaries match but whose
+// encoded content differs (a rotated private key) still show as changed.
+func blobDiff(oldBlob, newBlob DecodedBlob, contentChanged bool) []DiffLine {
+	diff := ComputeDiff(oldBlob.Lines, newBlob.Lines)
+	if contentChanged && !diffHasChanges(diff) {
+		diff = append(diff, DiffLine{Op: DiffInsert, Text: "(encoded content changed, not shown)"})
+	}
+	return diff
+}
+
+func diffHasChanges(diff []DiffLine) bool {
+	for _, d := range diff {
+		if d.Op == DiffInsert || d.Op == DiffDelete {
+			return true
+		}
+	}
+	return false
+}
+
+// blobDiffLabel names a blob diff after the kind of both sides, e.g.
+// "certificate diff".
+func blobDiffLabel(oldBlob, newBlob DecodedBlob) string {
+	if oldBlob.Kind == newBlob.Kind || oldBlob.Kind == "" {
+		return newBlob.Kind + " diff"
+	}
+	if newBlob.Kind == "" {
+		return oldBlob.Kind + " diff"
+	}
+	return "decoded diff"
+}
+
+// markBlobHeredocPair attaches a decoded diff to a heredoc pair fold when both
+// heredoc bodies hold PEM data, so a rotated certificate shows its subject and
+// expiry changes instead of two walls of base64.
+func markBlobHeredocPair(block *foldBlock, lines []string) {
+	oldContent := strings.Join(extractHeredocContent(lines[block.Start+1:block.OldEnd-1]), "\n")
+	newContent := strings.Join(extractHeredocContent(lines[block.AddStart+1:block.End-1]), "\n")
+	if !strings.Contains(oldContent, "-----BEGIN ") || !strings.Contains(newContent, "-----BEGIN ") {
+		return
+	}
+	oldBlob, oldOk := TryDecodeBlob(dedentLines(strings.Split(oldContent, "\n")))
+	newBlob, newOk := TryDecodeBlob(dedentLines(strings.Split(newContent, "\n")))
+	if !oldOk || !newOk {
+		return
+	}
+	block.PairDiff = blobDiff(oldBlob, newBlob, oldContent != newContent)
+	block.PairDiffLabel = blobDiffLabel(oldBlob, newBlob)
+}
+
+// renderHeredocBlob renders a heredoc body holding PEM data as its decoded
+// summary. It returns false for any other heredoc so the body is shown as is.
+func renderHeredocBlob(contentLines []string, indent string, lineAction parser.Action, maxWidth int) (string, bool) {
+	content := dedentLines(extractHeredocContent(contentLines))
+	if !strings.Contains(content, "-----BEGIN ") {
+		return "", false
+	}
+	blob, ok := TryDecodeBlob(content)
+	if !ok {
+		return "", false
+	}
+	var b strings.Builder
+	writeBlobPreview(&b, blob, indent, userdataLineStyle(lineAction), maxWidth)
+	return b.String(), true
+}
+
+func writeBlobPreview(b *strings.Builder, blob DecodedBlob, indent string, style lipgloss.Style, maxWidth int) {
+	b.WriteString(indent)
+	b.WriteString(mutedColor.Render("┄┄┄ decoded " + blob.Kind + " ┄┄┄"))
+	b.WriteString("\n")
+	for _, l := range blob.Lines {
+		for _, wl := range strings.Split(wrapText(l, maxWidth-len(indent)-2), "\n") {
+			b.WriteString(indent)
+			b.WriteString(style.Render("  " + wl))
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString(indent)
+	b.WriteString(mutedColor.Render("┄┄┄ end " + blob.Kind + " ┄┄┄"))
+	b.WriteString("\n")
+}
+
+// parseBlobValue reads one side of an attribute value: a quoted string that
+// decodes as a blob, or a bare placeholder such as (known after apply).
+func parseBlobValue(s string) (blob DecodedBlob, raw, rest string, ok bool) {
+	s = strings.TrimLeft(s, " ")
+	if strings.HasPrefix(s, "\"") {
+		value, rest, ok := scanQuotedString(s)
+		if !ok {
+			return DecodedBlob{}, "", "", false
+		}
+		blob, decoded := TryDecodeBlob(value)
+		switch {
+		case !decoded && strings.Contains(value, "PRIVATE KEY"):
+			blob = DecodedBlob{Lines: []string{"private key (content hidden)"}}
+		case !decoded:
+			blob = DecodedBlob{Lines: []string{value}}
+		}
+		return blob, value, rest, true
+	}
+	if strings.HasPrefix(s, "(") || strings.HasPrefix(s, "null") {
+		placeholder, rest, _ := strings.Cut(s, " -> ")
+		if rest != "" {
+			rest = " -> " + rest
+		}
+		return DecodedBlob{Lines: []string{placeholder}}, placeholder, rest, true
+	}
+	return DecodedBlob{}, "", "", false
+}
+
+// tryRenderBlob detects attribute values holding certificates, keys, JWTs or
+// base64 JSON and renders a decoded preview in place of the encoded value.
+// Changed values render as a diff of the decoded summaries. Private key
+// material is never rendered.
+func (m Model) tryRenderBlob(line string, action parser.Action, maxWidth int) (string, bool) {
+	trimmed := strings.TrimLeft(line, " \t")
+	indent := line[:len(line)-len(trimmed)]
+	rawPrefix, content, lineAction := parseUserdataLinePrefix(trimmed, action)
+
+	key, value, found := strings.Cut(content, " = ")
+	if !found || !strings.HasPrefix(value, "\"") {
+		return "", false
+	}
+	key = strings.TrimSpace(key)
+	decodedIndent := indent + strings.Repeat(" ", len(rawPrefix))
+	symbol := " "
+	switch lineAction {
+	case parser.ActionCreate:
+		symbol = createSymbol
+	case parser.ActionDestroy:
+		symbol = destroySymbol
+	case parser.ActionUpdate:
+		symbol = updateSymbol
+	}
+
+	oldBlob, oldRaw, rest, ok := parseBlobValue(value)
+	if !ok {
+		return "", false
+	}
+	if !strings.HasPrefix(rest, " -> ") {
+		if oldBlob.Kind == "" {
+			return "", false
+		}
+		var b strings.Builder
+		b.WriteString(indent + symbol + " " + attrNameStyle.Render(key) + " = " + mutedColor.Render("<"+oldBlob.Kind+">"))
+		b.WriteString("\n")
+		writeBlobPreview(&b, oldBlob, decodedIndent, userdataLineStyle(lineAction), maxWidth)
+		return strings.TrimSuffix(b.String(), "\n"), true
+	}
+
+	newBlob, newRaw, _, ok := parseBlobValue(strings.TrimPrefix(rest, " -> "))
+	if !ok || (oldBlob.Kind == "" && newBlob.Kind == "") {
+		return "", false
+	}
+	label := blobDiffLabel(oldBlob, newBlob)
+
+	var b strings.Builder
+	b.WriteString(indent + symbol + " " + attrNameStyle.Render(key) + " = " + mutedColor.Render("<"+strings.TrimSuffix(label, " diff")+" changed>"))
+	b.WriteString("\n")
+	b.WriteString(decodedIndent)
+	b.WriteString(mutedColor.Render("┄┄┄ " + label + " ┄┄┄"))
+	b.WriteString("\n")
+	if contextDiff := ContextDiff(blobDiff(oldBlob, newBlob, oldRaw != newRaw), m.diffContextSize()); contextDiff != nil {
+		renderDiffLines(&b, contextDiff, decodedIndent, maxWidth)
+	}
+	b.WriteString(decodedIndent)
+	b.WriteString(mutedColor.Render("┄┄┄ end " + label + " ┄┄┄"))
+	return b.String(), true
+}
@@ -0,0 +1,206 @@
+package tui
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+var blobTestNow = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func useBlobTestClock(t *testing.T) {
+	t.Helper()
+	now = func() time.Time { return blobTestNow }
+	t.Cleanup(func() { now = time.Now })
+}
+
+// testCertificatePEM returns a self-signed certificate for cn that expires
+// validDays after blobTestNow.
+func testCertificatePEM(t *testing.T, cn string, validDays int) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     []string{cn, "www." + cn},
+		NotBefore:    blobTestNow.AddDate(0, 0, -1),
+		NotAfter:     blobTestNow.AddDate(0, 0, validDays),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func testPrivateKeyPEM(t *testing.T) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+func TestTryDecodeBlobCertificate(t *testing.T) {
+	useBlobTestClock(t)
+	blob, ok := TryDecodeBlob(testCertificatePEM(t, "example.com", 90))
+	if !ok || blob.Kind != "certificate" {
+		t.Fatalf("expected certificate, got %#v (ok=%v)", blob, ok)
+	}
+	got := strings.Join(blob.Lines, "\n")
+	for _, want := range []string{
+		"subject: CN=example.com",
+		"issuer: CN=example.com",
+		"SANs: example.com, www.example.com",
+		"expires: 2025-04-01T00:00:00Z (in 90 days)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("summary missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestTryDecodeBlobNeverShowsPrivateKey(t *testing.T) {
+	keyPEM := testPrivateKeyPEM(t)
+	blob, ok := TryDecodeBlob(keyPEM)
+	if !ok || blob.Kind != "private key" {
+		t.Fatalf("expected private key, got %#v (ok=%v)", blob, ok)
+	}
+	body := strings.Split(keyPEM, "\n")[1]
+	for _, l := range blob.Lines {
+		if strings.Contains(l, body[:16]) {
+			t.Fatalf("private key material leaked: %q", l)
+		}
+	}
+
+	bundle, ok := TryDecodeBlob(testCertificatePEM(t, "example.com", 30) + keyPEM)
+	if !ok || bundle.Kind != "private key" {
+		t.Fatalf("expected bundle with a key to be labelled as a private key, got %#v", bundle)
+	}
+}
+
+func TestTryDecodeBlobJWT(t *testing.T) {
+	useBlobTestClock(t)
+	enc := base64.RawURLEncoding
+	exp := blobTestNow.AddDate(0, 0, 2).Unix()
+	token := enc.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." +
+		enc.EncodeToString([]byte(`{"sub":"deploy","exp":`+strconv.FormatInt(exp, 10)+`}`)) + ".c2lnbmF0dXJl"
+
+	blob, ok := TryDecodeBlob(token)
+	if !ok || blob.Kind != "jwt" {
+		t.Fatalf("expected jwt, got %#v (ok=%v)", blob, ok)
+	}
+	got := strings.Join(blob.Lines, "\n")
+	for _, want := range []string{`header: {"alg":"HS256","typ":"JWT"}`, `"sub": "deploy"`, "(in 2 days)"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("jwt summary missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "c2lnbmF0dXJl") {
+		t.Errorf("jwt signature should not be shown:\n%s", got)
+	}
+}
+
+func TestTryDecodeBlobBase64JSON(t *testing.T) {
+	blob, ok := TryDecodeBlob(base64.StdEncoding.EncodeToString([]byte(`{"b":1,"a":[true]}`)))
+	if !ok || blob.Kind != "json" {
+		t.Fatalf("expected json, got %#v (ok=%v)", blob, ok)
+	}
+	if blob.Lines[1] != `  "a": [` {
+		t.Errorf("expected pretty, key-sorted JSON, got %q", blob.Lines)
+	}
+
+	for _, s := range []string{"hello world", "aGVsbG8gd29ybGQgaGVsbG8=", "arn:aws:iam::123456789012:root"} {
+		if _, ok := TryDecodeBlob(s); ok {
+			t.Errorf("TryDecodeBlob(%q) should not decode", s)
+		}
+	}
+}
+
+func TestRenderBlobShowsCertificateChangeAsSummaryDiff(t *testing.T) {
+	useBlobTestClock(t)
+	oldCert := strconv.Quote(testCertificatePEM(t, "old.example.com", 10))
+	newCert := strconv.Quote(testCertificatePEM(t, "new.example.com", 365))
+	r := parser.Resource{Action: parser.ActionUpdate}
+	lines := []string{`      ~ certificate_body = ` + oldCert + ` -> ` + newCert}
+
+	got := renderExpandedForTest(r, lines)
+	if !strings.Contains(got, "~ certificate_body = <certificate changed>") {
+		t.Fatalf("missing certificate change header:\n%s", got)
+	}
+	for _, want := range []string{
+		"- subject: CN=old.example.com",
+		"+ subject: CN=new.example.com",
+		"- expires: 2025-01-11T00:00:00Z (in 10 days)",
+		"+ expires: 2026-01-01T00:00:00Z (in 365 days)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("certificate diff missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "BEGIN CERTIFICATE") {
+		t.Errorf("raw PEM should be replaced by the decoded diff:\n%s", got)
+	}
+}
+
+func TestRenderBlobLabelsPrivateKeyAttribute(t *testing.T) {
+	keyPEM := testPrivateKeyPEM(t)
+	r := parser.Resource{Action: parser.ActionCreate}
+	got := renderExpandedForTest(r, []string{`      + private_key_pem = ` + strconv.Quote(keyPEM)})
+
+	if !strings.Contains(got, "+ private_key_pem = <private key>") || !strings.Contains(got, "private key (content hidden)") {
+		t.Fatalf("expected private key label:\n%s", got)
+	}
+	if strings.Contains(got, strings.Split(keyPEM, "\n")[1][:16]) {
+		t.Fatalf("private key material rendered:\n%s", got)
+	}
+}
+
+func TestMarkBlobHeredocPairDiffsCertificates(t *testing.T) {
+	useBlobTestClock(t)
+	heredoc := func(prefix, certPEM string) []string {
+		out := []string{`          ` + prefix + ` <<-EOT`}
+		for _, l := range strings.Split(strings.TrimSpace(certPEM), "\n") {
+			out = append(out, `                `+l)
+		}
+		return append(out, `            EOT,`)
+	}
+	lines := []string{`      ~ certificate_chain = [`}
+	lines = append(lines, heredoc("-", testCertificatePEM(t, "example.com", 10))...)
+	lines = append(lines, heredoc("+", testCertificatePEM(t, "example.com", 400))...)
+	lines = append(lines, `        ]`)
+
+	var pair *foldBlock
+	blocks := findFoldBlocks(parser.Resource{Action: parser.ActionUpdate}, lines)
+	for i := range blocks {
+		if blocks[i].HeredocPair {
+			pair = &blocks[i]
+		}
+	}
+	if pair == nil || pair.PairDiffLabel != "certificate diff" {
+		t.Fatalf("expected certificate heredoc pair fold, got %#v", blocks)
+	}
+	removed, added := changedYAMLLines(pair.PairDiff)
+	if len(removed) != 1 || len(added) != 1 || !strings.HasPrefix(added[0], "expires: 2026-02-05") {
+		t.Fatalf("expected only the expiry to change, got -%q +%q", removed, added)
+	}
+}
@@ -5,7 +5,12 @@ import (
 	"compress/gzip"
 	"encoding/base64"
 	"encoding/hex"
+	"fmt"
 	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
 	"strings"
 	"unicode/utf8"
 )
@@ -122,3 +127,107 @@ func validateDecoded(b []byte) (string, bool) {
 
 	return string(b), true
 }
+
+// UserdataPart is one part of a cloud-init multipart MIME archive.
+type UserdataPart struct {
+	ContentType string
+	Filename    string
+	Content     string
+}
+
+// Label describes the part for display, e.g. "shell script (setup.sh)".
+func (p UserdataPart) Label() string {
+	label := userdataPartKinds[p.ContentType]
+	if label == "" {
+		label = p.ContentType
+	}
+	if p.Filename != "" {
+		label += " (" + p.Filename + ")"
+	}
+	return label
+}
+
+// userdataPartKinds maps cloud-init part content types to readable names.
+var userdataPartKinds = map[string]string{
+	"text/x-shellscript":          "shell script",
+	"text/x-shellscript-per-boot": "shell script (per boot)",
+	"text/cloud-config":           "cloud-config",
+	"text/cloud-config-archive":   "cloud-config archive",
+	"text/cloud-boothook":         "boothook",
+	"text/x-include-url":          "include",
+	"text/x-include-once-url":     "include once",
+	"text/jinja2":                 "jinja2 template",
+	"text/part-handler":           "part handler",
+	"text/plain":                  "text",
+}
+
+// SplitUserdataMIME splits decoded userdata that is a multipart MIME archive
+// (as built by cloud-init's write-mime-multipart or Terraform's
+// cloudinit_config) into its parts. Each part's transfer encoding is decoded
+// and gzip-compressed parts are decompressed. Returns false when the input is
+// not a multipart archive.
+func SplitUserdataMIME(decoded string) (parts []UserdataPart, ok bool) {
+	defer func() {
+		if recover() != nil {
+			parts = nil
+			ok = false
+		}
+	}()
+
+	if !strings.Contains(decoded, "multipart/") {
+		return nil, false
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(decoded))
+	if err != nil {
+		return nil, false
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return nil, false
+	}
+
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, false
+		}
+		body, err := io.ReadAll(part)
+		if err != nil {
+			return nil, false
+		}
+		parts = append(parts, decodeUserdataPart(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part.FileName(), body))
+	}
+	return parts, len(parts) > 0
+}
+
+func decodeUserdataPart(contentType, transferEncoding, filename string, body []byte) UserdataPart {
+	p := UserdataPart{ContentType: "text/plain", Filename: filename}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		p.ContentType = mediaType
+	}
+
+	switch strings.ToLower(strings.TrimSpace(transferEncoding)) {
+	case "base64":
+		if decoded, ok := tryBase64Variants(stripBase64Whitespace(string(body))); ok {
+			body = decoded
+		}
+	case "quoted-printable":
+		if decoded, err := io.ReadAll(quotedprintable.NewReader(bytes.NewReader(body))); err == nil {
+			body = decoded
+		}
+	}
+	if decompressed, ok := tryGzipDecompress(body); ok {
+		body = decompressed
+	}
+
+	if text, ok := validateDecoded(body); ok {
+		p.Content = text
+	} else {
+		p.Content = fmt.Sprintf("(binary content, %d bytes)", len(body))
+	}
+	return p
+}
@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"compress/gzip"
 	"encoding/base64"
+	"strings"
 	"testing"
+
+	"github.com/charmbracelet/bubbles/viewport"
 )
 
 func TestTryDecodeUserdata_StdBase64(t *testing.T) {
@@ -155,3 +158,60 @@ func TestTryDecodeUserdata_GzipMagicButInvalidGzip(t *testing.T) {
 		t.Error("expected decode failure for invalid gzip (contains null bytes or invalid)")
 	}
 }
+
+func buildCloudInitMIME(t *testing.T, script, cloudConfig string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	buf.WriteString("Content-Type: multipart/mixed; boundary=\"MIMEBOUNDARY\"\nMIME-Version: 1.0\n\n")
+	buf.WriteString("--MIMEBOUNDARY\nContent-Transfer-Encoding: 7bit\nContent-Type: text/cloud-config\nMime-Version: 1.0\n\n")
+	buf.WriteString(cloudConfig)
+	buf.WriteString("\n--MIMEBOUNDARY\nContent-Transfer-Encoding: base64\nContent-Type: text/x-shellscript\nContent-Disposition: attachment; filename=\"setup.sh\"\nMime-Version: 1.0\n\n")
+	buf.WriteString(base64.StdEncoding.EncodeToString([]byte(script)))
+	buf.WriteString("\n--MIMEBOUNDARY--\n")
+
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	_, _ = w.Write(buf.Bytes())
+	_ = w.Close()
+	return base64.StdEncoding.EncodeToString(gz.Bytes())
+}
+
+func TestSplitUserdataMIME(t *testing.T) {
+	encoded := buildCloudInitMIME(t, "#!/bin/bash\necho hello\n", "#cloud-config\npackages:\n  - jq")
+	decoded, ok := TryDecodeUserdata(encoded)
+	if !ok {
+		t.Fatal("expected gzip+base64 MIME archive to decode")
+	}
+	parts, ok := SplitUserdataMIME(decoded)
+	if !ok || len(parts) != 2 {
+		t.Fatalf("expected 2 MIME parts, got %d (ok=%v)", len(parts), ok)
+	}
+	if parts[0].Label() != "cloud-config" || !strings.Contains(parts[0].Content, "packages:") {
+		t.Errorf("unexpected first part: %+v", parts[0])
+	}
+	if parts[1].Label() != "shell script (setup.sh)" || parts[1].Content != "#!/bin/bash\necho hello\n" {
+		t.Errorf("unexpected second part: %+v", parts[1])
+	}
+}
+
+func TestSplitUserdataMIME_PlainScript(t *testing.T) {
+	if _, ok := SplitUserdataMIME("#!/bin/bash\necho hello\n"); ok {
+		t.Fatal("expected a plain script not to be treated as MIME")
+	}
+}
+
+func TestRenderUserdataDiffComparesMIMEParts(t *testing.T) {
+	oldB64 := buildCloudInitMIME(t, "#!/bin/bash\necho hello\n", "#cloud-config\npackages:\n  - jq")
+	newB64 := buildCloudInitMIME(t, "#!/bin/bash\necho goodbye\n", "#cloud-config\npackages:\n  - jq")
+	m := Model{viewport: viewport.New(120, 40), diffContext: 0}
+
+	got := stripRenderANSI(m.renderUserdataDiff(oldB64, newB64, "user_data", "    ", "header", 120))
+	for _, want := range []string{"part: cloud-config (unchanged)", "part: shell script (setup.sh)", "- echo hello", "+ echo goodbye"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("rendered MIME diff missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "MIMEBOUNDARY") {
+		t.Fatalf("rendered MIME diff still shows raw MIME structure:\n%s", got)
+	}
+}
@@ -1,5 +1,10 @@
 package tui
 
+import (
+	"sort"
+	"strings"
+)
+
 // DiffOp represents the type of a diff operation
 type DiffOp int
 
@@ -16,107 +21,291 @@ type DiffLine struct {
 	Text string
 }
 
-const maxLCSLines = 800
+// DiffAlgorithm selects how ComputeDiff aligns old and new lines.
+type DiffAlgorithm int
 
-// ComputeDiff computes a line-level diff between old and new using LCS.
-// For inputs exceeding maxLCSLines total, it trims the common prefix/suffix
-// and only diffs the changed core to avoid O(m*n) blowup.
-func ComputeDiff(oldLines, newLines []string) []DiffLine {
-	m, n := len(oldLines), len(newLines)
+const (
+	// DiffMyers is the linear-space Myers O(ND) diff (default).
+	DiffMyers DiffAlgorithm = iota
+	// DiffPatience anchors the diff on lines that are unique in both inputs
+	// before falling back to Myers, which keeps YAML/JSON blocks aligned on
+	// their distinctive keys instead of on repeated braces and blank lines.
+	DiffPatience
+)
+
+var diffAlgorithm = DiffMyers
 
-	if m+n > maxLCSLines {
-		return computeDiffLargeInput(oldLines, newLines)
+// SetDiffAlgorithm selects the algorithm used by ComputeDiff by name
+// ("myers" or "patience"). It returns false for unknown names.
+func SetDiffAlgorithm(name string) bool {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "myers", "default":
+		diffAlgorithm = DiffMyers
+	case "patience":
+		diffAlgorithm = DiffPatience
+	default:
+		return false
 	}
+	return true
+}
 
-	return lcs(oldLines, newLines)
+// ComputeDiff computes a line-level diff between old and new using the
+// configured algorithm. Memory use is linear in the input size, so large
+// heredocs and userdata scripts still get a precise diff.
+func ComputeDiff(oldLines, newLines []string) []DiffLine {
+	return computeDiffWith(diffAlgorithm, oldLines, newLines)
 }
 
-func lcs(oldLines, newLines []string) []DiffLine {
-	m, n := len(oldLines), len(newLines)
+func computeDiffWith(algo DiffAlgorithm, oldLines, newLines []string) []DiffLine {
+	d := newDiffer(algo, oldLines, newLines)
+	d.diffRange(0, len(oldLines), 0, len(newLines))
+	return d.result
+}
 
-	table := make([][]int, m+1)
-	for i := range table {
-		table[i] = make([]int, n+1)
-	}
-	for i := 1; i <= m; i++ {
-		for j := 1; j <= n; j++ {
-			if oldLines[i-1] == newLines[j-1] {
-				table[i][j] = table[i-1][j-1] + 1
-			} else if table[i-1][j] >= table[i][j-1] {
-				table[i][j] = table[i-1][j]
-			} else {
-				table[i][j] = table[i][j-1]
+// differ holds the interned inputs of a single diff. Lines are mapped to
+// integer ids so comparisons in the hot loops are cheap.
+type differ struct {
+	algo     DiffAlgorithm
+	oldLines []string
+	newLines []string
+	a, b     []int
+	result   []DiffLine
+}
+
+func newDiffer(algo DiffAlgorithm, oldLines, newLines []string) *differ {
+	ids := make(map[string]int, len(oldLines))
+	intern := func(lines []string) []int {
+		out := make([]int, len(lines))
+		for i, l := range lines {
+			id, ok := ids[l]
+			if !ok {
+				id = len(ids)
+				ids[l] = id
 			}
+			out[i] = id
 		}
+		return out
 	}
-
-	var result []DiffLine
-	i, j := m, n
-	for i > 0 || j > 0 {
-		if i > 0 && j > 0 && oldLines[i-1] == newLines[j-1] {
-			result = append(result, DiffLine{Op: DiffEqual, Text: oldLines[i-1]})
-			i--
-			j--
-		} else if j > 0 && (i == 0 || table[i][j-1] >= table[i-1][j]) {
-			result = append(result, DiffLine{Op: DiffInsert, Text: newLines[j-1]})
-			j--
-		} else {
-			result = append(result, DiffLine{Op: DiffDelete, Text: oldLines[i-1]})
-			i--
-		}
+	return &differ{
+		algo:     algo,
+		oldLines: oldLines,
+		newLines: newLines,
+		a:        intern(oldLines),
+		b:        intern(newLines),
+		result:   make([]DiffLine, 0, len(oldLines)+len(newLines)),
 	}
+}
 
-	for left, right := 0, len(result)-1; left < right; left, right = left+1, right-1 {
-		result[left], result[right] = result[right], result[left]
+func (d *differ) equal(i int) {
+	d.result = append(d.result, DiffLine{Op: DiffEqual, Text: d.oldLines[i]})
+}
+
+func (d *differ) deleteRange(lo, hi int) {
+	for i := lo; i < hi; i++ {
+		d.result = append(d.result, DiffLine{Op: DiffDelete, Text: d.oldLines[i]})
 	}
+}
 
-	return result
+func (d *differ) insertRange(lo, hi int) {
+	for j := lo; j < hi; j++ {
+		d.result = append(d.result, DiffLine{Op: DiffInsert, Text: d.newLines[j]})
+	}
 }
 
-// computeDiffLargeInput strips common prefix/suffix lines and only diffs the
-// changed middle portion, keeping memory and CPU reasonable for large files.
-func computeDiffLargeInput(oldLines, newLines []string) []DiffLine {
-	m, n := len(oldLines), len(newLines)
+// diffRange diffs a[aLo:aHi] against b[bLo:bHi], appending to d.result.
+func (d *differ) diffRange(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		d.equal(aLo)
+		aLo++
+		bLo++
+	}
+	suffix := 0
+	for aLo < aHi-suffix && bLo < bHi-suffix && d.a[aHi-1-suffix] == d.b[bHi-1-suffix] {
+		suffix++
+	}
+	aHi -= suffix
+	bHi -= suffix
 
-	prefixLen := 0
-	limit := m
-	if n < limit {
-		limit = n
+	switch {
+	case aLo == aHi:
+		d.insertRange(bLo, bHi)
+	case bLo == bHi:
+		d.deleteRange(aLo, aHi)
+	case d.algo == DiffPatience:
+		d.patience(aLo, aHi, bLo, bHi)
+	default:
+		d.bisect(aLo, aHi, bLo, bHi)
 	}
-	for prefixLen < limit && oldLines[prefixLen] == newLines[prefixLen] {
-		prefixLen++
+
+	for k := 0; k < suffix; k++ {
+		d.equal(aHi + k)
 	}
+}
 
-	suffixLen := 0
-	for suffixLen < limit-prefixLen &&
-		oldLines[m-1-suffixLen] == newLines[n-1-suffixLen] {
-		suffixLen++
+// bisect finds the middle snake of the Myers edit graph for the given ranges
+// and diffs both halves recursively. Only two diagonal vectors are kept, so
+// memory stays linear however many lines differ.
+func (d *differ) bisect(aLo, aHi, bLo, bHi int) {
+	a, b := d.a[aLo:aHi], d.b[bLo:bHi]
+	n, m := len(a), len(b)
+	maxD := (n + m + 1) / 2
+	offset := maxD
+	size := 2*maxD + 2
+	v1 := make([]int, size)
+	v2 := make([]int, size)
+	for i := range v1 {
+		v1[i] = -1
+		v2[i] = -1
 	}
+	v1[offset+1] = 0
+	v2[offset+1] = 0
+	delta := n - m
+	front := delta%2 != 0
+	k1start, k1end, k2start, k2end := 0, 0, 0, 0
 
-	var result []DiffLine
-	for i := 0; i < prefixLen; i++ {
-		result = append(result, DiffLine{Op: DiffEqual, Text: oldLines[i]})
+	for step := 0; step < maxD; step++ {
+		for k1 := -step + k1start; k1 <= step-k1end; k1 += 2 {
+			k1off := offset + k1
+			var x1 int
+			if k1 == -step || (k1 != step && v1[k1off-1] < v1[k1off+1]) {
+				x1 = v1[k1off+1]
+			} else {
+				x1 = v1[k1off-1] + 1
+			}
+			y1 := x1 - k1
+			for x1 < n && y1 < m && a[x1] == b[y1] {
+				x1++
+				y1++
+			}
+			v1[k1off] = x1
+			switch {
+			case x1 > n:
+				k1end += 2
+			case y1 > m:
+				k1start += 2
+			case front:
+				k2off := offset + delta - k1
+				if k2off >= 0 && k2off < size && v2[k2off] != -1 && x1 >= n-v2[k2off] {
+					d.diffRange(aLo, aLo+x1, bLo, bLo+y1)
+					d.diffRange(aLo+x1, aHi, bLo+y1, bHi)
+					return
+				}
+			}
+		}
+
+		for k2 := -step + k2start; k2 <= step-k2end; k2 += 2 {
+			k2off := offset + k2
+			var x2 int
+			if k2 == -step || (k2 != step && v2[k2off-1] < v2[k2off+1]) {
+				x2 = v2[k2off+1]
+			} else {
+				x2 = v2[k2off-1] + 1
+			}
+			y2 := x2 - k2
+			for x2 < n && y2 < m && a[n-x2-1] == b[m-y2-1] {
+				x2++
+				y2++
+			}
+			v2[k2off] = x2
+			switch {
+			case x2 > n:
+				k2end += 2
+			case y2 > m:
+				k2start += 2
+			case !front:
+				k1off := offset + delta - k2
+				if k1off >= 0 && k1off < size && v1[k1off] != -1 {
+					x1 := v1[k1off]
+					y1 := offset + x1 - k1off
+					if x1 >= n-x2 {
+						d.diffRange(aLo, aLo+x1, bLo, bLo+y1)
+						d.diffRange(aLo+x1, aHi, bLo+y1, bHi)
+						return
+					}
+				}
+			}
+		}
 	}
 
-	oldCore := oldLines[prefixLen : m-suffixLen]
-	newCore := newLines[prefixLen : n-suffixLen]
+	// No common subsequence at all.
+	d.deleteRange(aLo, aHi)
+	d.insertRange(bLo, bHi)
+}
+
+// patience anchors the diff on lines that occur exactly once in both ranges,
+// keeping the longest run of anchors that appear in the same order, and diffs
+// the gaps between anchors. Ranges without unique lines fall back to Myers.
+func (d *differ) patience(aLo, aHi, bLo, bHi int) {
+	anchors := d.uniqueAnchors(aLo, aHi, bLo, bHi)
+	if len(anchors) == 0 {
+		d.bisect(aLo, aHi, bLo, bHi)
+		return
+	}
+	i, j := aLo, bLo
+	for _, anchor := range anchors {
+		d.diffRange(i, anchor[0], j, anchor[1])
+		d.equal(anchor[0])
+		i, j = anchor[0]+1, anchor[1]+1
+	}
+	d.diffRange(i, aHi, j, bHi)
+}
 
-	if len(oldCore)+len(newCore) <= maxLCSLines {
-		result = append(result, lcs(oldCore, newCore)...)
-	} else {
-		for _, l := range oldCore {
-			result = append(result, DiffLine{Op: DiffDelete, Text: l})
+// uniqueAnchors returns (old, new) index pairs of lines that are unique in
+// both ranges, reduced to their longest increasing subsequence.
+func (d *differ) uniqueAnchors(aLo, aHi, bLo, bHi int) [][2]int {
+	type occurrence struct {
+		countA, countB int
+		posA, posB     int
+	}
+	occ := make(map[int]*occurrence)
+	for i := aLo; i < aHi; i++ {
+		o := occ[d.a[i]]
+		if o == nil {
+			o = &occurrence{}
+			occ[d.a[i]] = o
 		}
-		for _, l := range newCore {
-			result = append(result, DiffLine{Op: DiffInsert, Text: l})
+		o.countA++
+		o.posA = i
+	}
+	for j := bLo; j < bHi; j++ {
+		if o := occ[d.b[j]]; o != nil {
+			o.countB++
+			o.posB = j
 		}
 	}
 
-	for i := 0; i < suffixLen; i++ {
-		result = append(result, DiffLine{Op: DiffEqual, Text: oldLines[m-suffixLen+i]})
+	var pairs [][2]int
+	for _, o := range occ {
+		if o.countA == 1 && o.countB == 1 {
+			pairs = append(pairs, [2]int{o.posA, o.posB})
+		}
+	}
+	if len(pairs) == 0 {
+		return nil
 	}
+	sort.Slice(pairs, func(x, y int) bool { return pairs[x][0] < pairs[y][0] })
 
-	return result
+	// Patience sort over new-side positions to find the LIS.
+	var tails []int // index into pairs of the smallest tail for each length
+	prev := make([]int, len(pairs))
+	for idx, p := range pairs {
+		pos := sort.Search(len(tails), func(t int) bool { return pairs[tails[t]][1] > p[1] })
+		if pos > 0 {
+			prev[idx] = tails[pos-1]
+		} else {
+			prev[idx] = -1
+		}
+		if pos == len(tails) {
+			tails = append(tails, idx)
+		} else {
+			tails[pos] = idx
+		}
+	}
+	lis := make([][2]int, len(tails))
+	for k, idx := len(tails)-1, tails[len(tails)-1]; k >= 0; k, idx = k-1, prev[idx] {
+		lis[k] = pairs[idx]
+	}
+	return lis
 }
 
 // ContextDiff collapses runs of DiffEqual lines, keeping only contextSize
@@ -0,0 +1,150 @@
+package tui
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// applyDiff rebuilds the old and new inputs from a diff.
+func applyDiff(diff []DiffLine) (oldLines, newLines []string) {
+	for _, d := range diff {
+		switch d.Op {
+		case DiffEqual:
+			oldLines = append(oldLines, d.Text)
+			newLines = append(newLines, d.Text)
+		case DiffDelete:
+			oldLines = append(oldLines, d.Text)
+		case DiffInsert:
+			newLines = append(newLines, d.Text)
+		}
+	}
+	return oldLines, newLines
+}
+
+func countEdits(diff []DiffLine) int {
+	edits := 0
+	for _, d := range diff {
+		if d.Op != DiffEqual {
+			edits++
+		}
+	}
+	return edits
+}
+
+// lcsLength is the reference O(m*n) LCS used to check minimality.
+func lcsLength(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			switch {
+			case a[i-1] == b[j-1]:
+				cur[j] = prev[j-1] + 1
+			case prev[j] >= cur[j-1]:
+				cur[j] = prev[j]
+			default:
+				cur[j] = cur[j-1]
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func randomLines(r *rand.Rand, n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = string(rune('a' + r.Intn(4)))
+	}
+	return lines
+}
+
+func TestComputeDiffMyersIsMinimal(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for iter := 0; iter < 300; iter++ {
+		a := randomLines(r, r.Intn(30))
+		b := randomLines(r, r.Intn(30))
+		diff := computeDiffWith(DiffMyers, a, b)
+
+		gotOld, gotNew := applyDiff(diff)
+		if strings.Join(gotOld, ",") != strings.Join(a, ",") || strings.Join(gotNew, ",") != strings.Join(b, ",") {
+			t.Fatalf("diff does not reproduce inputs: %v vs %v", a, b)
+		}
+		if want := len(a) + len(b) - 2*lcsLength(a, b); countEdits(diff) != want {
+			t.Fatalf("edits = %d, want minimal %d for %v vs %v", countEdits(diff), want, a, b)
+		}
+	}
+}
+
+func TestComputeDiffPatienceReproducesInputs(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for iter := 0; iter < 300; iter++ {
+		a := randomLines(r, r.Intn(30))
+		b := randomLines(r, r.Intn(30))
+		gotOld, gotNew := applyDiff(computeDiffWith(DiffPatience, a, b))
+		if strings.Join(gotOld, ",") != strings.Join(a, ",") || strings.Join(gotNew, ",") != strings.Join(b, ",") {
+			t.Fatalf("patience diff does not reproduce inputs: %v vs %v", a, b)
+		}
+	}
+}
+
+func TestComputeDiffLargeInputStaysPrecise(t *testing.T) {
+	var oldLines, newLines []string
+	for i := 0; i < 5000; i++ {
+		line := fmt.Sprintf("  key_%d: value_%d", i, i)
+		oldLines = append(oldLines, line)
+		switch {
+		case i%1000 == 500:
+			newLines = append(newLines, line+"-changed")
+		case i == 2600:
+			// dropped
+		default:
+			newLines = append(newLines, line)
+		}
+	}
+
+	for _, algo := range []DiffAlgorithm{DiffMyers, DiffPatience} {
+		diff := computeDiffWith(algo, oldLines, newLines)
+		// 5 changed lines (delete + insert each) plus one dropped line.
+		if got := countEdits(diff); got != 11 {
+			t.Errorf("algo %d: edits = %d, want 11", algo, got)
+		}
+	}
+}
+
+func TestComputeDiffPatienceAlignsOnUniqueLines(t *testing.T) {
+	oldLines := []string{"a: {", "  x: 1", "}", "b: {", "  y: 2", "}"}
+	newLines := []string{"b: {", "  y: 2", "}", "a: {", "  x: 1", "}"}
+	diff := computeDiffWith(DiffPatience, oldLines, newLines)
+	gotOld, gotNew := applyDiff(diff)
+	if strings.Join(gotOld, "\n") != strings.Join(oldLines, "\n") || strings.Join(gotNew, "\n") != strings.Join(newLines, "\n") {
+		t.Fatal("patience diff does not reproduce inputs")
+	}
+	// One block must survive intact as context; the other is moved.
+	equal := make(map[string]bool)
+	for _, d := range diff {
+		if d.Op == DiffEqual {
+			equal[d.Text] = true
+		}
+	}
+	keptA := equal["a: {"] && equal["  x: 1"]
+	keptB := equal["b: {"] && equal["  y: 2"]
+	if keptA == keptB {
+		t.Errorf("expected exactly one block to be kept intact, got %+v", diff)
+	}
+}
+
+func TestSetDiffAlgorithm(t *testing.T) {
+	defer SetDiffAlgorithm("myers")
+	if !SetDiffAlgorithm("patience") || diffAlgorithm != DiffPatience {
+		t.Error("expected patience to be selected")
+	}
+	if SetDiffAlgorithm("bogus") {
+		t.Error("expected unknown algorithm to be rejected")
+	}
+	if diffAlgorithm != DiffPatience {
+		t.Error("unknown algorithm must not change the selection")
+	}
+}
@@ -0,0 +1,373 @@
+package tui
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+// ExportOptions controls the content of an exported report.
+type ExportOptions struct {
+	Title    string          // report heading, "Terraform plan" when empty
+	Notes    string          // reviewer notes, included verbatim
+	Reviewed map[string]bool // addresses marked reviewed
+}
+
+// exportActionOrder is the order of the per-action report sections.
+var exportActionOrder = []parser.Action{
+	parser.ActionCreate,
+	parser.ActionUpdate,
+	parser.ActionReplace,
+	parser.ActionDeleteCreate,
+	parser.ActionCreateDelete,
+	parser.ActionDestroy,
+	parser.ActionRead,
+	parser.ActionOutput,
+}
+
+// exportSection is the resources of one action, in plan order.
+type exportSection struct {
+	Action    parser.Action
+	Resources []parser.Resource
+}
+
+// exportSections groups resources by action in exportActionOrder, followed
+// by any other actions in order of appearance.
+func exportSections(plan *parser.Plan) []exportSection {
+	byAction := make(map[parser.Action][]parser.Resource)
+	var order []parser.Action
+	for _, r := range plan.Resources {
+		if _, ok := byAction[r.Action]; !ok {
+			order = append(order, r.Action)
+		}
+		byAction[r.Action] = append(byAction[r.Action], r)
+	}
+	var sections []exportSection
+	known := make(map[parser.Action]bool)
+	for _, action := range exportActionOrder {
+		known[action] = true
+		if len(byAction[action]) > 0 {
+			sections = append(sections, exportSection{Action: action, Resources: byAction[action]})
+		}
+	}
+	for _, action := range order {
+		if !known[action] {
+			sections = append(sections, exportSection{Action: action, Resources: byAction[action]})
+		}
+	}
+	return sections
+}
+
+// exportSectionTitle returns the heading of an action's section.
+func exportSectionTitle(action parser.Action) string {
+	label := filterActionLabel(action)
+	if label == "" {
+		return "Other"
+	}
+	return strings.ToUpper(label[:1]) + label[1:]
+}
+
+// forcedReplacementAttrs returns the attributes and blocks Terraform marks
+// with "# forces replacement".
+func forcedReplacementAttrs(r parser.Resource) []string {
+	var names []string
+	for _, line := range r.RawLines {
+		if !strings.Contains(line, "# forces replacement") {
+			continue
+		}
+		content := strings.TrimSpace(line)
+		for _, symbol := range []string{"-/+ ", "+/- ", "+ ", "- ", "~ "} {
+			content = strings.TrimPrefix(content, symbol)
+		}
+		if i := strings.IndexAny(content, "={"); i > 0 {
+			content = content[:i]
+		}
+		if name := strings.Trim(strings.TrimSpace(content), `"`); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func exportTitle(opts ExportOptions) string {
+	if opts.Title == "" {
+		return "Terraform plan"
+	}
+	return opts.Title
+}
+
+// exportSummary returns the plan's summary line, or a resource count when
+// the plan has none.
+func exportSummary(plan *parser.Plan) string {
+	if plan.Summary != "" {
+		return fmt.Sprintf("Plan: %d to add, %d to change, %d to destroy.", plan.TotalAdd, plan.TotalChange, plan.TotalDestroy)
+	}
+	return fmt.Sprintf("%d resource(s) with changes.", len(plan.Resources))
+}
+
+// reviewedCount counts the plan's resources marked reviewed.
+func reviewedCount(plan *parser.Plan, reviewed map[string]bool) int {
+	n := 0
+	for _, r := range plan.Resources {
+		if reviewed[r.Address] {
+			n++
+		}
+	}
+	return n
+}
+
+// ExportMarkdown renders the plan as a Markdown report for pull requests and
+// change tickets: a summary table, then one section per action with a
+// collapsible <details> block per resource.
+func ExportMarkdown(plan *parser.Plan, opts ExportOptions) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", exportTitle(opts))
+	fmt.Fprintf(&b, "%s\n\n", exportSummary(plan))
+
+	sections := exportSections(plan)
+	b.WriteString("| Action | Resources |\n|--------|----------:|\n")
+	for _, section := range sections {
+		fmt.Fprintf(&b, "| %s | %d |\n", exportSectionTitle(section.Action), len(section.Resources))
+	}
+	b.WriteString("\n")
+	if n := reviewedCount(plan, opts.Reviewed); n > 0 {
+		fmt.Fprintf(&b, "Reviewed: %d of %d resource(s).\n\n", n, len(plan.Resources))
+	}
+
+	if notes := strings.TrimSpace(opts.Notes); notes != "" {
+		fmt.Fprintf(&b, "## Reviewer notes\n\n%s\n\n", notes)
+	}
+
+	for _, section := range sections {
+		fmt.Fprintf(&b, "## %s (%d)\n\n", exportSectionTitle(section.Action), len(section.Resources))
+		for _, r := range section.Resources {
+			writeMarkdownResource(&b, r, opts.Reviewed[r.Address])
+		}
+	}
+	return b.String()
+}
+
+func writeMarkdownResource(b *strings.Builder, r parser.Resource, reviewed bool) {
+	summary := "<code>" + html.EscapeString(r.Address) + "</code> " + getActionDescription(r.Action)
+	if reviewed {
+		summary += " ✓ reviewed"
+	}
+	fmt.Fprintf(b, "<details>\n<summary>%s</summary>\n\n", summary)
+	if forced := forcedReplacementAttrs(r); len(forced) > 0 {
+		fmt.Fprintf(b, "> **Forces replacement:** `%s`\n\n", strings.Join(forced, "`, `"))
+	}
+	if len(r.RawLines) > 1 {
+		lines := make([]string, 0, len(r.RawLines)-1)
+		for _, line := range r.RawLines[1:] {
+			lines = append(lines, markdownDiffLine(line))
+		}
+		fence := codeFence(lines)
+		fmt.Fprintf(b, "%sdiff\n%s\n%s\n\n", fence, strings.Join(lines, "\n"), fence)
+	}
+	b.WriteString("</details>\n\n")
+}
+
+// markdownDiffLine moves a line's +/- change symbol to the first column so
+// Markdown diff highlighting picks it up, keeping the content aligned.
+func markdownDiffLine(line string) string {
+	trimmed := strings.TrimLeft(line, " \t")
+	indent := line[:len(line)-len(trimmed)]
+	if strings.HasPrefix(trimmed, "+ ") || strings.HasPrefix(trimmed, "- ") {
+		return trimmed[:1] + indent + " " + trimmed[2:]
+	}
+	return line
+}
+
+// codeFence returns a backtick fence longer than any backtick run in lines.
+func codeFence(lines []string) string {
+	longest := 0
+	for _, line := range lines {
+		run := 0
+		for _, c := range line {
+			if c == '`' {
+				run++
+				longest = max(longest, run)
+			} else {
+				run = 0
+			}
+		}
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+// ExportHTML renders the same report as ExportMarkdown as a self-contained
+// HTML page styled with the current theme's colors. It loads no external
+// resources, so it can be attached to tickets and opened offline.
+func ExportHTML(plan *parser.Plan, opts ExportOptions) string {
+	p := activePalette
+	title := html.EscapeString(exportTitle(opts))
+	sections := exportSections(plan)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+body { background: %s; color: %s; font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 1100px; padding: 0 1em; }
+h1, h2 { color: %s; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid %s; padding: 0.3em 0.8em; text-align: left; }
+td.count { text-align: right; }
+details { background: %s; border-radius: 6px; margin: 0.5em 0; padding: 0.4em 0.8em; }
+summary { cursor: pointer; }
+code, pre { font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; }
+pre { background: %s; overflow-x: auto; padding: 0.8em; }
+.notes { background: %s; border-left: 4px solid %s; padding: 0.6em 1em; white-space: pre-wrap; }
+.muted { color: %s; }
+.forced { color: %s; }
+.reviewed { color: %s; }
+.create { color: %s; }
+.destroy { color: %s; }
+.update { color: %s; }
+.replace { color: %s; }
+.read { color: %s; }
+</style>
+</head>
+<body>
+`, title, p["base"], p["text"], p["blue"], p["surface1"], p["surface0"], p["mantle"],
+		p["surface0"], p["blue"], p["overlay"], p["red"], p["green"],
+		p["green"], p["red"], p["yellow"], p["mauve"], p["sapphire"])
+
+	fmt.Fprintf(&b, "<h1>%s</h1>\n<p>%s</p>\n", title, html.EscapeString(exportSummary(plan)))
+	b.WriteString("<table>\n<tr><th>Action</th><th>Resources</th></tr>\n")
+	for _, section := range sections {
+		fmt.Fprintf(&b, "<tr><td class=\"%s\">%s</td><td class=\"count\">%d</td></tr>\n",
+			htmlActionClass(section.Action), exportSectionTitle(section.Action), len(section.Resources))
+	}
+	b.WriteString("</table>\n")
+	if n := reviewedCount(plan, opts.Reviewed); n > 0 {
+		fmt.Fprintf(&b, "<p class=\"reviewed\">Reviewed: %d of %d resource(s).</p>\n", n, len(plan.Resources))
+	}
+
+	if notes := strings.TrimSpace(opts.Notes); notes != "" {
+		fmt.Fprintf(&b, "<h2>Reviewer notes</h2>\n<div class=\"notes\">%s</div>\n", html.EscapeString(notes))
+	}
+
+	for _, section := range sections {
+		fmt.Fprintf(&b, "<h2 class=\"%s\">%s (%d)</h2>\n", htmlActionClass(section.Action), exportSectionTitle(section.Action), len(section.Resources))
+		for _, r := range section.Resources {
+			writeHTMLResource(&b, r, opts.Reviewed[r.Address])
+		}
+	}
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
+
+func writeHTMLResource(b *strings.Builder, r parser.Resource, reviewed bool) {
+	fmt.Fprintf(b, "<details>\n<summary><code class=\"%s\">%s</code> <span class=\"muted\">%s</span>",
+		htmlActionClass(r.Action), html.EscapeString(r.Address), getActionDescription(r.Action))
+	if reviewed {
+		b.WriteString(` <span class="reviewed">✓ reviewed</span>`)
+	}
+	b.WriteString("</summary>\n")
+	if forced := forcedReplacementAttrs(r); len(forced) > 0 {
+		fmt.Fprintf(b, "<p class=\"forced\"><strong>Forces replacement:</strong> <code>%s</code></p>\n",
+			html.EscapeString(strings.Join(forced, ", ")))
+	}
+	if len(r.RawLines) > 1 {
+		b.WriteString("<pre>")
+		for _, line := range r.RawLines[1:] {
+			escaped := html.EscapeString(line)
+			if class := htmlLineClass(line); class != "" {
+				escaped = `<span class="` + class + `">` + escaped + `</span>`
+			}
+			b.WriteString(escaped + "\n")
+		}
+		b.WriteString("</pre>\n")
+	}
+	b.WriteString("</details>\n")
+}
+
+// htmlActionClass returns the CSS class coloring an action.
+func htmlActionClass(action parser.Action) string {
+	switch action {
+	case parser.ActionCreate:
+		return "create"
+	case parser.ActionDestroy:
+		return "destroy"
+	case parser.ActionUpdate, parser.ActionOutput:
+		return "update"
+	case parser.ActionRead:
+		return "read"
+	}
+	return "replace"
+}
+
+// htmlLineClass returns the CSS class for a diff line's change symbol.
+func htmlLineClass(line string) string {
+	trimmed := strings.TrimLeft(line, " \t")
+	switch {
+	case strings.HasPrefix(trimmed, "-/+ "), strings.HasPrefix(trimmed, "+/- "):
+		return "replace"
+	case strings.HasPrefix(trimmed, "+ "):
+		return "create"
+	case strings.HasPrefix(trimmed, "- "):
+		return "destroy"
+	case strings.HasPrefix(trimmed, "~ "):
+		return "update"
+	}
+	return ""
+}
+
+// exportDisplayed writes the resources currently listed, with their reviewed
+// marks, to a report in the working directory. The totals are recounted so
+// the summary describes the same resources.
+func (m Model) exportDisplayed(format string) (string, error) {
+	displayed := m.displayedResourceIndices()
+	plan := *m.plan
+	plan.Resources = make([]parser.Resource, 0, len(displayed))
+	for _, idx := range displayed {
+		plan.Resources = append(plan.Resources, m.plan.Resources[idx])
+	}
+	recountPlanTotals(&plan, m.plan.OutputCount)
+
+	opts := ExportOptions{Reviewed: m.reviewed}
+	content := ExportMarkdown(&plan, opts)
+	if format == "html" {
+		content = ExportHTML(&plan, opts)
+	}
+	path := fmt.Sprintf("terraprism-plan-%s.%s", time.Now().Format("20060102-150405"), format)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func handleKeyExportMarkdown(m Model) (Model, tea.Cmd, bool) {
+	return m.handleExport("md"), nil, true
+}
+
+func handleKeyExportHTML(m Model) (Model, tea.Cmd, bool) {
+	return m.handleExport("html"), nil, true
+}
+
+func (m Model) handleExport(format string) Model {
+	path, err := m.exportDisplayed(format)
+	if err != nil {
+		m.exportStatus = "Export failed: " + err.Error()
+	} else {
+		m.exportStatus = fmt.Sprintf("Exported %d resource(s) to %s", len(m.displayedResourceIndices()), path)
+	}
+	return m
+}
+
+// viewExportStatus renders the result of the last export until the next key.
+func (m Model) viewExportStatus() string {
+	if m.exportStatus == "" {
+		return ""
+	}
+	return searchStyle.Render(m.exportStatus) + "\n\n"
+}
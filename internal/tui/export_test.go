@@ -0,0 +1,140 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+func TestExportMarkdown(t *testing.T) {
+	plan, err := parser.Parse(dashboardTestPlan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := ExportMarkdown(plan, ExportOptions{
+		Notes:    "Approved by the network team.",
+		Reviewed: map[string]bool{"module.network.aws_subnet.a": true},
+	})
+
+	for _, want := range []string{
+		"# Terraform plan\n\nPlan: 2 to add, 1 to change, 1 to destroy.",
+		"| Create | 1 |\n| Update | 1 |\n| Replace | 1 |",
+		"Reviewed: 1 of 3 resource(s).",
+		"## Reviewer notes\n\nApproved by the network team.",
+		"## Create (1)\n\n<details>\n<summary><code>module.network.aws_subnet.a</code> will be created ✓ reviewed</summary>",
+		"> **Forces replacement:** `ami`",
+		"+       cidr_block = \"10.0.1.0/24\"",
+		"      ~ ttl      = 300 -> 60",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "## Update") > strings.Index(out, "## Replace") {
+		t.Error("sections should follow the action order")
+	}
+}
+
+func TestExportMarkdownFilteredTotals(t *testing.T) {
+	plan, err := parser.Parse(dashboardTestPlan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for expr, want := range map[string]string{
+		"action:update":  "Plan: 0 to add, 1 to change, 0 to destroy.",
+		"action:replace": "Plan: 1 to add, 0 to change, 1 to destroy.",
+	} {
+		filtered, err := FilterPlan(plan, expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out := ExportMarkdown(filtered, ExportOptions{}); !strings.Contains(out, want) {
+			t.Errorf("export -q %s should summarise the listed resources with %q:\n%s", expr, want, out)
+		}
+	}
+}
+
+func TestMarkdownDiffLineAndFence(t *testing.T) {
+	tests := map[string]string{
+		`      + name = "a"`: `+       name = "a"`,
+		`      - name = "a"`: `-       name = "a"`,
+		`      ~ name = "a"`: `      ~ name = "a"`,
+		`        id   = "i"`: `        id   = "i"`,
+	}
+	for in, want := range tests {
+		if got := markdownDiffLine(in); got != want {
+			t.Errorf("markdownDiffLine(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if got := codeFence([]string{"a ``` b", "````"}); got != "`````" {
+		t.Errorf("codeFence = %q", got)
+	}
+}
+
+func TestExportHTML(t *testing.T) {
+	plan, err := parser.Parse(dashboardTestPlan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetLightPalette()
+	defer SetDarkPalette()
+	out := ExportHTML(plan, ExportOptions{Title: "Change <42>", Notes: "<b>ok</b>"})
+	for _, want := range []string{
+		"<title>Change &lt;42&gt;</title>",
+		"background: " + lightPalette["base"],
+		`<div class="notes">&lt;b&gt;ok&lt;/b&gt;</div>`,
+		`<summary><code class="replace">aws_instance.web</code>`,
+		`<span class="update">      ~ ttl      = 300 -&gt; 60</span>`,
+		"<strong>Forces replacement:</strong> <code>ami</code>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("html missing %q", want)
+		}
+	}
+	for _, external := range []string{"http://", "https://", "<script", "<link"} {
+		if strings.Contains(out, external) {
+			t.Errorf("html should be self-contained, found %q", external)
+		}
+	}
+}
+
+func TestExportKeyWritesDisplayedResources(t *testing.T) {
+	t.Chdir(t.TempDir())
+	plan, err := parser.Parse(dashboardTestPlan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewModel(plan, "test")
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 200, Height: 40})
+	m = updated.(Model)
+	m, _ = m.WithQuery("provider:aws")
+
+	m = pressKeys(m, "x")
+	if view := stripRenderANSI(m.View()); !strings.Contains(view, "Exported 2 resource(s) to terraprism-plan-") {
+		t.Fatalf("export status missing:\n%s", view)
+	}
+	files, _ := filepath.Glob("terraprism-plan-*.md")
+	if len(files) != 1 {
+		t.Fatalf("exported files = %v", files)
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "google_dns_record_set") || !strings.Contains(string(data), "aws_subnet.a") {
+		t.Errorf("export should only contain the displayed resources:\n%s", data)
+	}
+	if !strings.Contains(string(data), "Plan: 2 to add, 0 to change, 1 to destroy.") {
+		t.Errorf("export should summarise the displayed resources:\n%s", data)
+	}
+
+	m = pressKeys(m, "j")
+	if m.exportStatus != "" {
+		t.Error("the export status should clear on the next key")
+	}
+}
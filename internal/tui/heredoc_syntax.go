@@ -0,0 +1,384 @@
+package tui
+
+import (
+	"regexp"
+	"strings"
+)
+
+// heredocLang is the content language of a heredoc body, used to pick a
+// syntax highlighter.
+type heredocLang int
+
+const (
+	langPlain heredocLang = iota
+	langShell
+	langJSON
+	langYAML
+	langHCL
+	langSQL
+)
+
+// heredocMarkerLangs maps conventional heredoc markers to languages. Generic
+// markers such as EOT and EOF fall through to the attribute name and content.
+var heredocMarkerLangs = map[string]heredocLang{
+	"JSON":   langJSON,
+	"POLICY": langJSON,
+	"YAML":   langYAML,
+	"YML":    langYAML,
+	"SH":     langShell,
+	"BASH":   langShell,
+	"SHELL":  langShell,
+	"SCRIPT": langShell,
+	"SQL":    langSQL,
+	"HCL":    langHCL,
+	"TF":     langHCL,
+}
+
+var (
+	sqlStatementPattern = regexp.MustCompile(`(?i)^(select|insert|update|delete|create|alter|drop|grant|revoke|with)\s`)
+	hclAssignPattern    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*\s*=\s*\S`)
+	hclBlockPattern     = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\s+"[^"]*")*\s*\{$`)
+	yamlKeyPattern      = regexp.MustCompile(`^(\s*(?:-\s+)?)([^\s#'"{\[][^:#]*?|"[^"]*"|'[^']*')(:)(\s|$)`)
+)
+
+// detectHeredocLang picks the heredoc language from its end marker, strong
+// content signatures (shebang, #cloud-config, a JSON document), the attribute
+// name, and finally weaker content hints.
+func detectHeredocLang(header string, body []string) heredocLang {
+	if lang, ok := heredocMarkerLangs[strings.ToUpper(parseHeredocMarkerFromLine(header))]; ok {
+		return lang
+	}
+
+	content := heredocSniffLines(body)
+	first := ""
+	for _, l := range content {
+		if strings.TrimSpace(l) != "" {
+			first = strings.TrimSpace(l)
+			break
+		}
+	}
+	switch {
+	case strings.HasPrefix(first, "#!"):
+		return langShell
+	case strings.HasPrefix(first, "#cloud-config"):
+		return langYAML
+	case strings.HasPrefix(first, "{") || strings.HasPrefix(first, "["):
+		if _, ok := parseJSONDocument(strings.Join(content, "\n")); ok {
+			return langJSON
+		}
+	}
+
+	key := heredocAttrKey(header)
+	switch {
+	case key == "":
+	case strings.HasSuffix(key, "script") || strings.Contains(key, "command") || key == "inline" || key == "user_data":
+		return langShell
+	case strings.HasSuffix(key, "policy") || strings.HasSuffix(key, "_json") || key == "container_definitions" || key == "definition":
+		return langJSON
+	case strings.Contains(key, "yaml") || key == "values" || key == "manifest":
+		return langYAML
+	case strings.Contains(key, "sql") || key == "query":
+		return langSQL
+	}
+
+	switch {
+	case first == "":
+		return langPlain
+	case sqlStatementPattern.MatchString(first):
+		return langSQL
+	case hclBlockPattern.MatchString(first) || hclAssignPattern.MatchString(first):
+		return langHCL
+	case yamlKeyPattern.MatchString(first) || first == "---" || strings.HasPrefix(first, "- "):
+		return langYAML
+	case strings.HasPrefix(first, "#"):
+		return langShell
+	}
+	return langPlain
+}
+
+// heredocSniffLines strips Terraform's diff prefixes and common indentation
+// from a heredoc body so its content can be inspected.
+func heredocSniffLines(body []string) []string {
+	out := make([]string, 0, len(body))
+	baseIndent := heredocContentBaseIndent(body)
+	for _, line := range body {
+		trimmed := strings.TrimLeft(line, " \t")
+		indent := len(line) - len(trimmed)
+		if hasDiffPrefix(trimmed) && baseIndent >= 2 && indent == baseIndent-2 {
+			line = strings.Repeat(" ", baseIndent) + trimmed[2:]
+		}
+		out = append(out, line)
+	}
+	return strings.Split(dedentLines(out), "\n")
+}
+
+// heredocAttrKey returns the attribute a heredoc is assigned to, or "" for a
+// heredoc list element.
+func heredocAttrKey(header string) string {
+	content := stripDiffPrefix(strings.TrimLeft(header, " \t"))
+	key, _, found := strings.Cut(content, " = ")
+	if !found {
+		return ""
+	}
+	return strings.ToLower(strings.Trim(strings.TrimSpace(key), `"`))
+}
+
+// highlightHeredocBody colors a heredoc body for the language detected from
+// its header line and content.
+func highlightHeredocBody(header string, body []string) []string {
+	lang := detectHeredocLang(header, body)
+	baseIndent := heredocContentBaseIndent(body)
+	out := make([]string, len(body))
+	for i, line := range body {
+		out[i] = colorizeHeredocContentLine(line, baseIndent, lang)
+	}
+	return out
+}
+
+// syntaxRules describes the lexical features of a heredoc language for the
+// line highlighter.
+type syntaxRules struct {
+	comments  []string // line comment openers
+	quotes    string   // string delimiters
+	keywords  map[string]bool
+	constants map[string]bool
+	variables bool // $VAR and ${...} expansion (shell)
+	foldCase  bool // keywords are case-insensitive (SQL)
+	keyColon  bool // a string followed by ':' is a key (JSON)
+	keyAssign bool // a word followed by '=' is a key (HCL)
+}
+
+func keywordSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
+
+var jsonConstants = keywordSet("true", "false", "null")
+
+var syntaxRulesByLang = map[heredocLang]syntaxRules{
+	langShell: {
+		comments:  []string{"#"},
+		quotes:    `"'`,
+		variables: true,
+		keywords: keywordSet("if", "then", "else", "elif", "fi", "for", "while", "until", "do", "done",
+			"case", "esac", "in", "function", "return", "export", "local", "set", "source", "exit"),
+	},
+	langJSON: {
+		quotes:    `"`,
+		constants: jsonConstants,
+		keyColon:  true,
+	},
+	langYAML: {
+		comments:  []string{"#"},
+		quotes:    `"'`,
+		constants: keywordSet("true", "false", "null", "yes", "no", "on", "off", "~"),
+	},
+	langHCL: {
+		comments:  []string{"#", "//"},
+		quotes:    `"`,
+		constants: jsonConstants,
+		keyAssign: true,
+		keywords: keywordSet("resource", "data", "variable", "output", "locals", "module", "provider",
+			"terraform", "for", "in", "if", "for_each", "dynamic"),
+	},
+	langSQL: {
+		comments:  []string{"--"},
+		quotes:    `'"`,
+		foldCase:  true,
+		constants: keywordSet("null", "true", "false"),
+		keywords: keywordSet("select", "from", "where", "and", "or", "not", "insert", "into", "values",
+			"update", "set", "delete", "create", "alter", "drop", "table", "view", "index", "grant",
+			"revoke", "on", "to", "as", "join", "left", "right", "inner", "outer", "group", "by",
+			"order", "having", "limit", "with", "union", "all", "distinct", "is", "in", "exists",
+			"case", "when", "then", "else", "end", "primary", "key", "references", "default", "user",
+			"role", "database", "schema", "if"),
+	},
+}
+
+// highlightCode colors a single line of heredoc content. Highlighting is
+// line-local, so constructs spanning lines (block comments, multi-line
+// strings) are only colored on their first line.
+func highlightCode(lang heredocLang, s string) string {
+	switch lang {
+	case langPlain:
+		return s
+	case langYAML:
+		return highlightYAMLLine(s)
+	}
+	return highlightWithRules(s, syntaxRulesByLang[lang])
+}
+
+func highlightYAMLLine(s string) string {
+	rules := syntaxRulesByLang[langYAML]
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "---" || trimmed == "..." {
+		return mutedColor.Render(s)
+	}
+	m := yamlKeyPattern.FindStringSubmatchIndex(s)
+	if m == nil {
+		return highlightYAMLValue(s, rules)
+	}
+	prefix := s[m[2]:m[3]]
+	key := s[m[4]:m[5]]
+	return prefix + syntaxKeyStyle.Render(key) + ":" + highlightYAMLValue(s[m[7]:], rules)
+}
+
+// highlightYAMLValue colors a YAML value. Plain scalars are left unstyled
+// unless they are numbers or constants, since YAML allows '#' and quotes
+// inside unquoted text.
+func highlightYAMLValue(s string, rules syntaxRules) string {
+	lead := s[:len(s)-len(strings.TrimLeft(s, " \t"))]
+	rest := s[len(lead):]
+	if strings.HasPrefix(rest, "- ") {
+		return lead + mutedColor.Render("-") + highlightYAMLValue(rest[1:], rules)
+	}
+	if rest == "" || strings.HasPrefix(rest, "#") || strings.HasPrefix(rest, `"`) || strings.HasPrefix(rest, "'") ||
+		strings.HasPrefix(rest, "[") || strings.HasPrefix(rest, "{") {
+		return lead + highlightWithRules(rest, rules)
+	}
+	value, comment, hasComment := strings.Cut(rest, " #")
+	styled := value
+	if rules.constants[strings.ToLower(strings.TrimSpace(value))] || isNumberToken(strings.TrimSpace(value)) {
+		styled = syntaxNumberStyle.Render(value)
+	}
+	if hasComment {
+		styled += syntaxCommentStyle.Render(" #" + comment)
+	}
+	return lead + styled
+}
+
+func isNumberToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	digits := 0
+	for i, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			digits++
+		case c == '.' || c == '_' || ((c == '-' || c == '+') && i == 0):
+		default:
+			return false
+		}
+	}
+	return digits > 0
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '-' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// highlightWithRules is a small line lexer: comments, strings, variables,
+// numbers, keywords and constants are styled; everything else is kept as is.
+func highlightWithRules(s string, rules syntaxRules) string {
+	var b strings.Builder
+	i := 0
+	for i < len(s) {
+		c := s[i]
+
+		if isCommentStart(s, i, rules) {
+			b.WriteString(syntaxCommentStyle.Render(s[i:]))
+			break
+		}
+
+		switch {
+		case strings.IndexByte(rules.quotes, c) >= 0:
+			j := scanStringEnd(s, i)
+			style := syntaxStringStyle
+			if rules.keyColon && strings.HasPrefix(strings.TrimLeft(s[j:], " "), ":") {
+				style = syntaxKeyStyle
+			}
+			b.WriteString(style.Render(s[i:j]))
+			i = j
+		case c == '$' && rules.variables && i+1 < len(s):
+			j := scanShellVariable(s, i)
+			if j == i+1 {
+				b.WriteByte(c)
+				i++
+				continue
+			}
+			b.WriteString(syntaxVariableStyle.Render(s[i:j]))
+			i = j
+		case isWordByte(c) && c != '-' && (i == 0 || !isWordByte(s[i-1])):
+			j := i
+			for j < len(s) && isWordByte(s[j]) {
+				j++
+			}
+			b.WriteString(styleWord(s, i, j, rules))
+			i = j
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+func styleWord(s string, start, end int, rules syntaxRules) string {
+	word := s[start:end]
+	lookup := word
+	if rules.foldCase {
+		lookup = strings.ToLower(word)
+	}
+	rest := strings.TrimLeft(s[end:], " ")
+	switch {
+	case isNumberToken(word):
+		return syntaxNumberStyle.Render(word)
+	case rules.constants[lookup]:
+		return syntaxNumberStyle.Render(word)
+	case rules.keyAssign && strings.HasPrefix(rest, "=") && !strings.HasPrefix(rest, "=="):
+		return syntaxKeyStyle.Render(word)
+	case rules.keywords[lookup]:
+		return syntaxKeywordStyle.Render(word)
+	}
+	return word
+}
+
+func isCommentStart(s string, i int, rules syntaxRules) bool {
+	for _, opener := range rules.comments {
+		if !strings.HasPrefix(s[i:], opener) {
+			continue
+		}
+		// '#' only starts a comment at a word boundary, so "a#b" and "$#" are kept.
+		if opener == "#" && i > 0 && s[i-1] != ' ' && s[i-1] != '\t' {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// scanStringEnd returns the index just past the string starting at s[i],
+// honouring backslash escapes, or len(s) for an unterminated string.
+func scanStringEnd(s string, i int) int {
+	quote := s[i]
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case quote:
+			return j + 1
+		}
+	}
+	return len(s)
+}
+
+func scanShellVariable(s string, i int) int {
+	j := i + 1
+	if s[j] == '{' {
+		if end := strings.IndexByte(s[j:], '}'); end >= 0 {
+			return j + end + 1
+		}
+		return len(s)
+	}
+	if strings.IndexByte("@*#?$!0123456789", s[j]) >= 0 {
+		return j + 1
+	}
+	for j < len(s) && (s[j] == '_' || (s[j] >= 'a' && s[j] <= 'z') || (s[j] >= 'A' && s[j] <= 'Z') || (s[j] >= '0' && s[j] <= '9')) {
+		j++
+	}
+	return j
+}
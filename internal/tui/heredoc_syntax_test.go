@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+func TestDetectHeredocLang(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		body   []string
+		want   heredocLang
+	}{
+		{"json marker", `  + policy = <<-JSON`, []string{`  x`}, langJSON},
+		{"yaml marker", `  + config = <<YAML`, []string{`  x`}, langYAML},
+		{"shebang beats attribute", `  + values = <<-EOT`, []string{`      #!/bin/bash`, `      echo hi`}, langShell},
+		{"cloud-config user_data", `  + user_data = <<-EOT`, []string{`      #cloud-config`, `      packages: [nginx]`}, langYAML},
+		{"json content", `  + document = <<-EOT`, []string{`      {`, `        "a": 1`, `      }`}, langJSON},
+		{"attribute name", `  + startup_script = <<-EOT`, []string{`      apt-get update`}, langShell},
+		{"sql content", `  + body = <<-EOT`, []string{`      SELECT id FROM users;`}, langSQL},
+		{"hcl content", `  + body = <<-EOT`, []string{`      resource "x" "y" {`, `      }`}, langHCL},
+		{"yaml content in list", `          - <<-EOT`, []string{`              controller:`, `                replicas: 2`}, langYAML},
+		{"diff-prefixed yaml", `      ~ body = <<-EOT`, []string{`            spec:`, `          +   replicas: 3`}, langYAML},
+		{"plain text", `  + description = <<-EOT`, []string{`      Hello there.`}, langPlain},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectHeredocLang(tt.header, tt.body); got != tt.want {
+				t.Errorf("detectHeredocLang() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlightCodeStylesTokens(t *testing.T) {
+	tests := []struct {
+		name string
+		lang heredocLang
+		in   string
+		want string
+	}{
+		{"json key and number", langJSON, `"a": 1,`, syntaxKeyStyle.Render(`"a"`) + ": " + syntaxNumberStyle.Render("1") + ","},
+		{"json string value", langJSON, `"a": "b"`, syntaxKeyStyle.Render(`"a"`) + ": " + syntaxStringStyle.Render(`"b"`)},
+		{"yaml key and comment", langYAML, `  replicas: 3 # scale`, "  " + syntaxKeyStyle.Render("replicas") + ":" + " " + syntaxNumberStyle.Render("3") + syntaxCommentStyle.Render(" # scale")},
+		{"yaml plain scalar with hash", langYAML, `url: http://x/#a`, syntaxKeyStyle.Render("url") + ": http://x/#a"},
+		{"shell keyword and variable", langShell, `if [ -n "$X" ]; then`, syntaxKeywordStyle.Render("if") + " [ -n " + syntaxStringStyle.Render(`"$X"`) + " ]; " + syntaxKeywordStyle.Render("then")},
+		{"shell comment", langShell, `echo $HOME # home`, "echo " + syntaxVariableStyle.Render("$HOME") + " " + syntaxCommentStyle.Render("# home")},
+		{"hcl assignment", langHCL, `name = "web"`, syntaxKeyStyle.Render("name") + " = " + syntaxStringStyle.Render(`"web"`)},
+		{"sql keywords fold case", langSQL, `Select 1 -- one`, syntaxKeywordStyle.Render("Select") + " " + syntaxNumberStyle.Render("1") + " " + syntaxCommentStyle.Render("-- one")},
+		{"plain is untouched", langPlain, `"a": 1`, `"a": 1`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlightCode(tt.lang, tt.in); got != tt.want {
+				t.Errorf("highlightCode(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderHeredocHighlightsBodyAndKeepsText(t *testing.T) {
+	r := parser.Resource{Action: parser.ActionCreate}
+	body := []string{
+		`      + policy = <<-EOT`,
+		`            {`,
+		`              "Version": "2012-10-17"`,
+		`            }`,
+		`        EOT`,
+	}
+	r.RawLines = append([]string{`  + resource "x" "y" {`}, body...)
+
+	highlighted := highlightHeredocBody(body[0], body[1:4])
+	if !strings.Contains(highlighted[1], syntaxKeyStyle.Render(`"Version"`)) {
+		t.Fatalf("expected JSON key to be highlighted, got %q", highlighted[1])
+	}
+
+	got := renderExpandedForTest(r, body)
+	if !renderedHasLine(got, `              "Version": "2012-10-17"`) {
+		t.Fatalf("highlighting changed heredoc text:\n%s", got)
+	}
+}
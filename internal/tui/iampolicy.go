@@ -0,0 +1,276 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// iamWildcardMarker is appended to added lines that introduce a wildcard.
+const iamWildcardMarker = "  ⚠ new wildcard"
+
+// iamSetFields are statement fields whose values are compared as sets, in
+// display order.
+var iamSetFields = []string{"Principal", "NotPrincipal", "Action", "NotAction", "Resource", "NotResource"}
+
+// isIAMPolicyDocument reports whether doc looks like an IAM policy: an object
+// with a Statement list (or single statement object).
+func isIAMPolicyDocument(doc any) bool {
+	obj, ok := doc.(map[string]any)
+	if !ok {
+		return false
+	}
+	switch obj["Statement"].(type) {
+	case []any, map[string]any:
+		return true
+	}
+	return false
+}
+
+// jsonDocumentDiff diffs two JSON documents, using the statement-level IAM
+// renderer when both are policies. iam reports which renderer was used.
+func jsonDocumentDiff(oldDoc, newDoc any) (diff []DiffLine, iam bool) {
+	if isIAMPolicyDocument(oldDoc) && isIAMPolicyDocument(newDoc) {
+		return iamPolicyDiff(oldDoc.(map[string]any), newDoc.(map[string]any)), true
+	}
+	return jsonStructuralDiff(oldDoc, newDoc), false
+}
+
+func iamStatements(policy map[string]any) []map[string]any {
+	var out []map[string]any
+	switch s := policy["Statement"].(type) {
+	case map[string]any:
+		out = append(out, s)
+	case []any:
+		for _, item := range s {
+			if st, ok := item.(map[string]any); ok {
+				out = append(out, st)
+			}
+		}
+	}
+	return out
+}
+
+// iamStatementKeys identifies statements by Sid, or by effect, principal and
+// resource when there is no Sid. Duplicate keys get an occurrence suffix.
+func iamStatementKeys(statements []map[string]any) []string {
+	keys := make([]string, len(statements))
+	seen := make(map[string]int)
+	for i, st := range statements {
+		key := ""
+		if sid, ok := st["Sid"].(string); ok && sid != "" {
+			key = "sid:" + sid
+		} else {
+			key = fmt.Sprintf("%v|%s|%s", st["Effect"],
+				strings.Join(iamValues("Principal", st["Principal"]), ","),
+				strings.Join(iamValues("Resource", st["Resource"]), ","))
+		}
+		seen[key]++
+		if n := seen[key]; n > 1 {
+			key = fmt.Sprintf("%s#%d", key, n)
+		}
+		keys[i] = key
+	}
+	return keys
+}
+
+// iamValues flattens a set-valued field into sorted strings. Principals are
+// qualified by type, e.g. "AWS:arn:aws:iam::123456789012:root".
+func iamValues(field string, v any) []string {
+	var out []string
+	var add func(prefix string, v any)
+	add = func(prefix string, v any) {
+		switch t := v.(type) {
+		case nil:
+		case string:
+			out = append(out, prefix+t)
+		case []any:
+			for _, item := range t {
+				add(prefix, item)
+			}
+		case map[string]any:
+			for k, item := range t {
+				add(prefix+k+":", item)
+			}
+		default:
+			out = append(out, prefix+canonicalJSON(t, ""))
+		}
+	}
+	add("", v)
+	sort.Strings(out)
+	return out
+}
+
+// isIAMWildcard reports whether value grants everything for its field:
+// "*" or "service:*" actions, "*" resources and "*" principals.
+func isIAMWildcard(field, value string) bool {
+	switch field {
+	case "Action":
+		return value == "*" || strings.HasSuffix(value, ":*")
+	case "Resource":
+		return value == "*"
+	case "Principal":
+		return value == "*" || strings.HasSuffix(value, ":*")
+	}
+	return false
+}
+
+func iamStatementLabel(st map[string]any) string {
+	if sid, ok := st["Sid"].(string); ok && sid != "" {
+		return fmt.Sprintf("Statement %q (%v)", sid, st["Effect"])
+	}
+	return fmt.Sprintf("Statement (%v)", st["Effect"])
+}
+
+// iamPolicyDiff renders a statement-level policy diff: statements are matched
+// by Sid (or effect+principal+resource), actions, principals and resources
+// are compared as sets, and added wildcards are flagged.
+func iamPolicyDiff(oldPolicy, newPolicy map[string]any) []DiffLine {
+	var out []DiffLine
+	add := func(op DiffOp, text string) {
+		out = append(out, DiffLine{Op: op, Text: text})
+	}
+
+	for _, field := range []string{"Version", "Id"} {
+		oldVal, inOld := oldPolicy[field]
+		newVal, inNew := newPolicy[field]
+		switch {
+		case inOld && inNew && canonicalJSON(oldVal, "") == canonicalJSON(newVal, ""):
+			add(DiffEqual, field+": "+canonicalJSON(newVal, ""))
+		default:
+			if inOld {
+				add(DiffDelete, field+": "+canonicalJSON(oldVal, ""))
+			}
+			if inNew {
+				add(DiffInsert, field+": "+canonicalJSON(newVal, ""))
+			}
+		}
+	}
+
+	oldStatements := iamStatements(oldPolicy)
+	newStatements := iamStatements(newPolicy)
+	mergeOrdered(iamStatementKeys(oldStatements), iamStatementKeys(newStatements), func(_ string, oldIdx, newIdx int) {
+		switch {
+		case newIdx < 0:
+			out = append(out, iamStatementLines(DiffDelete, oldStatements[oldIdx])...)
+		case oldIdx < 0:
+			out = append(out, iamStatementLines(DiffInsert, newStatements[newIdx])...)
+		default:
+			out = append(out, iamStatementDiff(oldStatements[oldIdx], newStatements[newIdx])...)
+		}
+	})
+	return out
+}
+
+// iamStatementLines renders a whole statement that was added or removed.
+func iamStatementLines(op DiffOp, st map[string]any) []DiffLine {
+	return iamStatementDiffSides(op, st, st)
+}
+
+func iamStatementDiff(oldSt, newSt map[string]any) []DiffLine {
+	return iamStatementDiffSides(DiffEqual, oldSt, newSt)
+}
+
+// iamStatementDiffSides compares two versions of a statement. For whole
+// statement additions or removals op is DiffInsert or DiffDelete and both
+// sides are the same statement.
+func iamStatementDiffSides(op DiffOp, oldSt, newSt map[string]any) []DiffLine {
+	var out []DiffLine
+	add := func(lineOp DiffOp, text string) {
+		if op != DiffEqual {
+			lineOp = op
+		}
+		out = append(out, DiffLine{Op: lineOp, Text: text})
+	}
+
+	label := iamStatementLabel(newSt)
+	if op == DiffDelete {
+		label = iamStatementLabel(oldSt)
+	}
+	add(DiffEqual, label)
+
+	if oldEffect, newEffect := fmt.Sprint(oldSt["Effect"]), fmt.Sprint(newSt["Effect"]); oldEffect != newEffect && op == DiffEqual {
+		add(DiffDelete, "  Effect: "+oldEffect)
+		add(DiffInsert, "  Effect: "+newEffect)
+	}
+
+	for _, field := range iamSetFields {
+		oldVals := iamValues(field, oldSt[field])
+		newVals := iamValues(field, newSt[field])
+		if op == DiffDelete {
+			newVals = oldVals
+		}
+		if op == DiffInsert {
+			oldVals = newVals
+		}
+		inOld := make(map[string]bool, len(oldVals))
+		for _, v := range oldVals {
+			inOld[v] = true
+		}
+		inNew := make(map[string]bool, len(newVals))
+		for _, v := range newVals {
+			inNew[v] = true
+		}
+		union := append(append([]string{}, oldVals...), newVals...)
+		sort.Strings(union)
+		for i, v := range union {
+			if i > 0 && union[i-1] == v {
+				continue
+			}
+			text := "  " + field + ": " + v
+			switch {
+			case op == DiffInsert || (inNew[v] && !inOld[v]):
+				if isIAMWildcard(field, v) {
+					text += iamWildcardMarker
+				}
+				add(DiffInsert, text)
+			case op == DiffDelete || !inNew[v]:
+				add(DiffDelete, text)
+			default:
+				add(DiffEqual, text)
+			}
+		}
+	}
+
+	// Remaining fields (Condition, etc.) are compared as canonical JSON.
+	var others []string
+	for k := range newSt {
+		others = append(others, k)
+	}
+	for k := range oldSt {
+		if _, ok := newSt[k]; !ok {
+			others = append(others, k)
+		}
+	}
+	sort.Strings(others)
+	for _, k := range others {
+		if k == "Sid" || k == "Effect" || slices.Contains(iamSetFields, k) {
+			continue
+		}
+		oldVal, inOld := oldSt[k]
+		newVal, inNew := newSt[k]
+		if inOld && inNew && canonicalJSON(oldVal, "") == canonicalJSON(newVal, "") {
+			add(DiffEqual, "  "+k+": "+canonicalJSON(newVal, ""))
+			continue
+		}
+		if inOld {
+			add(DiffDelete, "  "+k+": "+canonicalJSON(oldVal, ""))
+		}
+		if inNew {
+			add(DiffInsert, "  "+k+": "+canonicalJSON(newVal, ""))
+		}
+	}
+	return out
+}
+
+// iamNewWildcards counts the wildcard grants introduced by a policy diff.
+func iamNewWildcards(diff []DiffLine) int {
+	n := 0
+	for _, d := range diff {
+		if d.Op == DiffInsert && strings.HasSuffix(d.Text, iamWildcardMarker) {
+			n++
+		}
+	}
+	return n
+}
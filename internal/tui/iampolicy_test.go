@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+func iamDiffForTest(t *testing.T, oldJSON, newJSON string) []DiffLine {
+	t.Helper()
+	oldDoc, ok := parseJSONDocument(oldJSON)
+	if !ok {
+		t.Fatalf("invalid old policy: %s", oldJSON)
+	}
+	newDoc, ok := parseJSONDocument(newJSON)
+	if !ok {
+		t.Fatalf("invalid new policy: %s", newJSON)
+	}
+	diff, iam := jsonDocumentDiff(oldDoc, newDoc)
+	if !iam {
+		t.Fatal("expected documents to be recognised as IAM policies")
+	}
+	return diff
+}
+
+func TestIAMPolicyDiffMatchesStatementsBySid(t *testing.T) {
+	oldJSON := `{"Version":"2012-10-17","Statement":[
+		{"Sid":"Write","Effect":"Allow","Action":"s3:PutObject","Resource":"arn:aws:s3:::b/*"},
+		{"Sid":"Read","Effect":"Allow","Action":["s3:GetObject","s3:ListBucket"],"Resource":"arn:aws:s3:::b/*"}]}`
+	newJSON := `{"Version":"2012-10-17","Statement":[
+		{"Sid":"Read","Effect":"Allow","Action":["s3:GetObject","s3:GetObjectTagging"],"Resource":"arn:aws:s3:::b/*"},
+		{"Sid":"Write","Effect":"Allow","Action":"s3:PutObject","Resource":"arn:aws:s3:::b/*"}]}`
+
+	removed, added := changedYAMLLines(iamDiffForTest(t, oldJSON, newJSON))
+	if got := strings.Join(removed, "|"); got != "  Action: s3:ListBucket" {
+		t.Errorf("removed = %q", removed)
+	}
+	if got := strings.Join(added, "|"); got != "  Action: s3:GetObjectTagging" {
+		t.Errorf("added = %q", added)
+	}
+}
+
+func TestIAMPolicyDiffFlagsNewWildcards(t *testing.T) {
+	oldJSON := `{"Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`
+	newJSON := `{"Statement":[{"Effect":"Allow","Action":["s3:GetObject","*"],"Resource":"*"},
+		{"Sid":"Admin","Effect":"Allow","Action":"iam:*","Resource":"*"}]}`
+
+	diff := iamDiffForTest(t, oldJSON, newJSON)
+	if got := iamNewWildcards(diff); got != 3 {
+		t.Fatalf("new wildcards = %d, want 3 (Action *, iam:* and the new statement's Resource *):\n%+v", got, diff)
+	}
+	for _, d := range diff {
+		if d.Op == DiffEqual && strings.Contains(d.Text, iamWildcardMarker) {
+			t.Errorf("unchanged wildcard flagged as new: %q", d.Text)
+		}
+	}
+}
+
+func TestIAMPolicyDiffMatchesStatementsWithoutSid(t *testing.T) {
+	oldJSON := `{"Statement":[{"Effect":"Allow","Action":"logs:PutLogEvents","Resource":"arn:aws:logs:*:*:*"}]}`
+	newJSON := `{"Statement":[{"Effect":"Allow","Action":["logs:PutLogEvents","logs:CreateLogStream"],"Resource":"arn:aws:logs:*:*:*"}]}`
+
+	removed, added := changedYAMLLines(iamDiffForTest(t, oldJSON, newJSON))
+	if len(removed) != 0 || strings.Join(added, "|") != "  Action: logs:CreateLogStream" {
+		t.Fatalf("removed = %q, added = %q", removed, added)
+	}
+}
+
+func TestJSONEncodePolicyRendersStatementDiff(t *testing.T) {
+	r := parser.Resource{Address: "aws_iam_policy.p", Action: parser.ActionUpdate}
+	lines := []string{
+		`      ~ policy      = jsonencode(`,
+		`          ~ {`,
+		`              ~ Statement = [`,
+		`                  ~ {`,
+		`                      ~ Action   = [`,
+		`                          - "s3:GetObject",`,
+		`                          + "s3:*",`,
+		`                        ]`,
+		`                        # (2 unchanged attributes hidden)`,
+		`                    },`,
+		`                ]`,
+		`                # (1 unchanged attribute hidden)`,
+		`            }`,
+		`        )`,
+		`        # (2 unchanged attributes hidden)`,
+	}
+	blocks := findFoldBlocks(r, lines)
+	if len(blocks) != 1 || !blocks[0].IAMPolicy || blocks[0].End != 14 {
+		t.Fatalf("expected one IAM policy fold over the jsonencode block, got %#v", blocks)
+	}
+
+	got := collapseSpaces(renderExpandedWithDiffContextForTest(r, lines, 3))
+	for _, want := range []string{
+		`▼ ~ policy = iam policy diff (+1 -1, ⚠ 1 new wildcard(s))`,
+		`- Action: s3:GetObject`,
+		`+ Action: s3:* ⚠ new wildcard`,
+		`# (2 unchanged attributes hidden)`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("rendered policy diff missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, `Statement = [`) {
+		t.Fatalf("rendered policy diff still shows the raw jsonencode block:\n%s", got)
+	}
+}
@@ -0,0 +1,209 @@
+package tui
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// maxIntralineTokens bounds the token-level diff so very long values
+	// (policies, certificates) don't pay for an intraline pass.
+	maxIntralineTokens = 400
+	// maxCharRefineLen is the longest changed run that is refined to
+	// character level, e.g. "v1" -> "v2" highlights only "1" and "2".
+	maxCharRefineLen = 64
+	// minIntralineSimilarity is the minimum share of unchanged text for an
+	// intraline highlight to be useful; below it both values are shown whole.
+	minIntralineSimilarity = 0.3
+)
+
+// intralineSegment is a run of text that is either shared by both values or
+// only present on one side.
+type intralineSegment struct {
+	Text    string
+	Changed bool
+}
+
+// intralineTokens splits s into runs of letters/digits and single
+// punctuation or whitespace characters, so "app-prod-v1" diffs against
+// "app-prod-v2" as a single changed word rather than a whole value.
+func intralineTokens(s string) []string {
+	var tokens []string
+	start := -1
+	for i, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, s[start:i])
+			start = -1
+		}
+		tokens = append(tokens, string(r))
+	}
+	if start >= 0 {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
+
+func charTokens(s string) []string {
+	tokens := make([]string, 0, len(s))
+	for _, r := range s {
+		tokens = append(tokens, string(r))
+	}
+	return tokens
+}
+
+// intralineSegments diffs oldVal against newVal word by word, refining short
+// changed runs down to characters. It returns ok=false when the values share
+// too little for a highlight to help.
+func intralineSegments(oldVal, newVal string) (oldSegs, newSegs []intralineSegment, ok bool) {
+	oldTokens := intralineTokens(oldVal)
+	newTokens := intralineTokens(newVal)
+	if len(oldTokens)+len(newTokens) > maxIntralineTokens {
+		return nil, nil, false
+	}
+
+	diff := ComputeDiff(oldTokens, newTokens)
+	var pendingOld, pendingNew strings.Builder
+	flushChanged := func() {
+		if pendingOld.Len() == 0 && pendingNew.Len() == 0 {
+			return
+		}
+		o, n := refineChangedRun(pendingOld.String(), pendingNew.String())
+		oldSegs = appendSegments(oldSegs, o...)
+		newSegs = appendSegments(newSegs, n...)
+		pendingOld.Reset()
+		pendingNew.Reset()
+	}
+	for _, d := range diff {
+		switch d.Op {
+		case DiffEqual:
+			flushChanged()
+			oldSegs = appendSegments(oldSegs, intralineSegment{Text: d.Text})
+			newSegs = appendSegments(newSegs, intralineSegment{Text: d.Text})
+		case DiffDelete:
+			pendingOld.WriteString(d.Text)
+		case DiffInsert:
+			pendingNew.WriteString(d.Text)
+		}
+	}
+	flushChanged()
+
+	longest := utf8.RuneCountInString(oldVal)
+	if n := utf8.RuneCountInString(newVal); n > longest {
+		longest = n
+	}
+	if longest == 0 || float64(unchangedLen(oldSegs))/float64(longest) < minIntralineSimilarity {
+		return nil, nil, false
+	}
+	return oldSegs, newSegs, true
+}
+
+// refineChangedRun splits a changed old/new run into character-level
+// segments when both sides are short and mostly alike.
+func refineChangedRun(oldRun, newRun string) (oldSegs, newSegs []intralineSegment) {
+	whole := func() ([]intralineSegment, []intralineSegment) {
+		var o, n []intralineSegment
+		if oldRun != "" {
+			o = []intralineSegment{{Text: oldRun, Changed: true}}
+		}
+		if newRun != "" {
+			n = []intralineSegment{{Text: newRun, Changed: true}}
+		}
+		return o, n
+	}
+	if oldRun == "" || newRun == "" || len(oldRun) > maxCharRefineLen || len(newRun) > maxCharRefineLen {
+		return whole()
+	}
+
+	for _, d := range ComputeDiff(charTokens(oldRun), charTokens(newRun)) {
+		switch d.Op {
+		case DiffEqual:
+			oldSegs = appendSegments(oldSegs, intralineSegment{Text: d.Text})
+			newSegs = appendSegments(newSegs, intralineSegment{Text: d.Text})
+		case DiffDelete:
+			oldSegs = appendSegments(oldSegs, intralineSegment{Text: d.Text, Changed: true})
+		case DiffInsert:
+			newSegs = appendSegments(newSegs, intralineSegment{Text: d.Text, Changed: true})
+		}
+	}
+
+	shorter := utf8.RuneCountInString(oldRun)
+	if n := utf8.RuneCountInString(newRun); n < shorter {
+		shorter = n
+	}
+	if unchangedLen(oldSegs)*2 < shorter {
+		return whole()
+	}
+	return oldSegs, newSegs
+}
+
+// appendSegments appends segs, merging adjacent runs of the same kind.
+func appendSegments(dst []intralineSegment, segs ...intralineSegment) []intralineSegment {
+	for _, seg := range segs {
+		if seg.Text == "" {
+			continue
+		}
+		if n := len(dst); n > 0 && dst[n-1].Changed == seg.Changed {
+			dst[n-1].Text += seg.Text
+			continue
+		}
+		dst = append(dst, seg)
+	}
+	return dst
+}
+
+func unchangedLen(segs []intralineSegment) int {
+	total := 0
+	for _, seg := range segs {
+		if !seg.Changed {
+			total += utf8.RuneCountInString(seg.Text)
+		}
+	}
+	return total
+}
+
+func renderSegments(segs []intralineSegment, base, emph lipgloss.Style) string {
+	var b strings.Builder
+	for _, seg := range segs {
+		if seg.Changed {
+			b.WriteString(emph.Render(seg.Text))
+		} else {
+			b.WriteString(base.Render(seg.Text))
+		}
+	}
+	return b.String()
+}
+
+// renderIntraline renders oldVal and newVal with the unchanged parts in the
+// base styles and the changed words or characters in the emphasis styles.
+// When the values have little in common, both are rendered whole in their
+// base styles.
+func renderIntraline(oldVal, newVal string, oldBase, newBase, oldEmph, newEmph lipgloss.Style) (string, string) {
+	oldSegs, newSegs, ok := intralineSegments(oldVal, newVal)
+	if !ok {
+		return oldBase.Render(oldVal), newBase.Render(newVal)
+	}
+	return renderSegments(oldSegs, oldBase, oldEmph), renderSegments(newSegs, newBase, newEmph)
+}
+
+// intralineComparable reports whether old and new are plain values worth an
+// intraline diff (not placeholders such as "(known after apply)").
+func intralineComparable(oldVal, newVal string) bool {
+	if oldVal == "" || newVal == "" || oldVal == newVal {
+		return false
+	}
+	for _, v := range []string{oldVal, newVal} {
+		if v == "null" || strings.Contains(v, "(known after apply)") || strings.Contains(v, "(sensitive") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIntralineSegmentsMarksOnlyChangedCharacters(t *testing.T) {
+	oldSegs, newSegs, ok := intralineSegments(`"app-prod-v1"`, `"app-prod-v2"`)
+	if !ok {
+		t.Fatal("expected similar values to produce intraline segments")
+	}
+
+	changed := func(segs []intralineSegment) string {
+		var parts []string
+		for _, seg := range segs {
+			if seg.Changed {
+				parts = append(parts, seg.Text)
+			}
+		}
+		return strings.Join(parts, "|")
+	}
+	if got := changed(oldSegs); got != "1" {
+		t.Errorf("old changed = %q, want %q", got, "1")
+	}
+	if got := changed(newSegs); got != "2" {
+		t.Errorf("new changed = %q, want %q", got, "2")
+	}
+}
+
+func TestIntralineSegmentsWordLevel(t *testing.T) {
+	oldSegs, newSegs, ok := intralineSegments("instance_type = t3.micro size small", "instance_type = m5.large size small")
+	if !ok {
+		t.Fatal("expected intraline segments")
+	}
+	for _, segs := range [][]intralineSegment{oldSegs, newSegs} {
+		for _, seg := range segs {
+			if seg.Changed && strings.Contains(seg.Text, "instance_type") {
+				t.Errorf("unchanged key marked as changed: %q", seg.Text)
+			}
+		}
+	}
+}
+
+func TestIntralineSegmentsFallsBackForDissimilarValues(t *testing.T) {
+	if _, _, ok := intralineSegments(`"ami-0abc"`, `"completely different"`); ok {
+		t.Error("expected dissimilar values to fall back to whole-value rendering")
+	}
+}
+
+func TestIntralineComparableSkipsPlaceholders(t *testing.T) {
+	tests := []struct {
+		old, new string
+		want     bool
+	}{
+		{`"a"`, `"b"`, true},
+		{`"a"`, "(known after apply)", false},
+		{"null", `"b"`, false},
+		{`"a"`, `"a"`, false},
+	}
+	for _, tt := range tests {
+		if got := intralineComparable(tt.old, tt.new); got != tt.want {
+			t.Errorf("intralineComparable(%q, %q) = %v, want %v", tt.old, tt.new, got, tt.want)
+		}
+	}
+}
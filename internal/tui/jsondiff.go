@@ -0,0 +1,500 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+// parseJSONDocument parses s as a JSON object or array. Scalars are rejected
+// so plain strings and numbers are never mistaken for documents.
+func parseJSONDocument(s string) (any, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" || (s[0] != '{' && s[0] != '[') {
+		return nil, false
+	}
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, false
+	}
+	if dec.More() {
+		return nil, false
+	}
+	return doc, true
+}
+
+// scanQuotedString splits a leading HCL string literal off s and returns its
+// unescaped value and the remainder. Terraform escapes template sequences as
+// "$${" and "%%{", which are restored here.
+func scanQuotedString(s string) (value, rest string, ok bool) {
+	if len(s) < 2 || s[0] != '"' {
+		return "", "", false
+	}
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			unquoted, err := strconv.Unquote(s[:i+1])
+			if err != nil {
+				return "", "", false
+			}
+			unquoted = strings.ReplaceAll(unquoted, "$${", "${")
+			unquoted = strings.ReplaceAll(unquoted, "%%{", "%{")
+			return unquoted, s[i+1:], true
+		}
+	}
+	return "", "", false
+}
+
+// parseJSONValueChange recognises `key = "<json>" -> "<json>"` lines where
+// both sides are JSON documents stored as strings, as Terraform prints for
+// attributes such as policy or container_definitions.
+func parseJSONValueChange(line string) (key string, oldDoc, newDoc any, ok bool) {
+	content := stripDiffPrefix(strings.TrimLeft(line, " \t"))
+	eq := strings.Index(content, " = ")
+	if eq <= 0 {
+		return "", nil, nil, false
+	}
+	oldRaw, rest, ok := scanQuotedString(strings.TrimSpace(content[eq+3:]))
+	if !ok || !strings.HasPrefix(rest, " -> ") {
+		return "", nil, nil, false
+	}
+	newRaw, _, ok := scanQuotedString(strings.TrimSpace(rest[4:]))
+	if !ok {
+		return "", nil, nil, false
+	}
+	if oldDoc, ok = parseJSONDocument(oldRaw); !ok {
+		return "", nil, nil, false
+	}
+	if newDoc, ok = parseJSONDocument(newRaw); !ok {
+		return "", nil, nil, false
+	}
+	return strings.TrimSpace(content[:eq]), oldDoc, newDoc, true
+}
+
+// findJSONValueFold returns a single-line fold for a JSON string attribute
+// change. The fold body is the structural diff rather than raw plan lines.
+func findJSONValueFold(r parser.Resource, lines []string, idx int) (foldBlock, bool) {
+	key, oldDoc, newDoc, ok := parseJSONValueChange(lines[idx])
+	if !ok {
+		return foldBlock{}, false
+	}
+	block := foldBlock{
+		Start: idx,
+		End:   idx + 1,
+		Key:   fmt.Sprintf("%s:%d:json-diff:%s", r.Address, idx, key),
+	}
+	block.JSONDiff, block.IAMPolicy = jsonDocumentDiff(oldDoc, newDoc)
+	block.LineCount = len(ContextDiff(block.JSONDiff, defaultDiffContext))
+	return block, true
+}
+
+// findJSONEncodeFold returns a fold covering a changed `key = jsonencode(...)`
+// value, replacing Terraform's element-by-element rendering with the
+// structural diff, or the statement-level diff when it holds an IAM policy.
+func findJSONEncodeFold(r parser.Resource, lines []string, idx int) (foldBlock, bool) {
+	end, oldDoc, newDoc, ok := parseJSONEncodeDiff(lines, idx)
+	if !ok {
+		return foldBlock{}, false
+	}
+	key, _, _ := strings.Cut(stripDiffPrefix(strings.TrimLeft(lines[idx], " \t")), " = ")
+	block := foldBlock{
+		Start: idx,
+		End:   end,
+		Key:   fmt.Sprintf("%s:%d:json-diff:%s", r.Address, idx, strings.TrimSpace(key)),
+	}
+	block.JSONDiff, block.IAMPolicy = jsonDocumentDiff(oldDoc, newDoc)
+	block.LineCount = len(ContextDiff(block.JSONDiff, defaultDiffContext))
+	return block, true
+}
+
+// markJSONHeredocPair attaches a structural diff to a heredoc pair fold when
+// both heredoc bodies are JSON documents.
+func markJSONHeredocPair(block *foldBlock, lines []string) {
+	oldContent := extractHeredocContent(lines[block.Start+1 : block.OldEnd-1])
+	newContent := extractHeredocContent(lines[block.AddStart+1 : block.End-1])
+	oldDoc, oldOk := parseJSONDocument(strings.Join(oldContent, "\n"))
+	newDoc, newOk := parseJSONDocument(strings.Join(newContent, "\n"))
+	if oldOk && newOk {
+		block.JSONDiff, block.IAMPolicy = jsonDocumentDiff(oldDoc, newDoc)
+	}
+}
+
+// jsonStructuralDiff compares two JSON documents key by key and element by
+// element. Object keys are visited in sorted order, so reordering alone
+// produces no changes. The result is pretty-printed JSON lines tagged as
+// equal, deleted or inserted, ready for ContextDiff and renderDiffLines.
+func jsonStructuralDiff(oldDoc, newDoc any) []DiffLine {
+	var d jsonDiffer
+	d.value("", "", oldDoc, newDoc)
+	return d.out
+}
+
+type jsonDiffer struct {
+	out []DiffLine
+}
+
+func (d *jsonDiffer) line(op DiffOp, text string) {
+	d.out = append(d.out, DiffLine{Op: op, Text: text})
+}
+
+// emit writes v pretty-printed, with prefix (e.g. `"Action": `) on its first line.
+func (d *jsonDiffer) emit(op DiffOp, indent, prefix string, v any) {
+	for i, l := range strings.Split(canonicalJSON(v, "  "), "\n") {
+		if i == 0 {
+			l = prefix + l
+		}
+		d.line(op, indent+l)
+	}
+}
+
+func (d *jsonDiffer) value(indent, prefix string, oldVal, newVal any) {
+	if reflect.DeepEqual(oldVal, newVal) {
+		d.emit(DiffEqual, indent, prefix, oldVal)
+		return
+	}
+
+	switch oldTyped := oldVal.(type) {
+	case map[string]any:
+		if newTyped, ok := newVal.(map[string]any); ok {
+			d.line(DiffEqual, indent+prefix+"{")
+			d.object(indent+"  ", oldTyped, newTyped)
+			d.line(DiffEqual, indent+"}")
+			return
+		}
+	case []any:
+		if newTyped, ok := newVal.([]any); ok {
+			d.line(DiffEqual, indent+prefix+"[")
+			d.array(indent+"  ", oldTyped, newTyped)
+			d.line(DiffEqual, indent+"]")
+			return
+		}
+	}
+
+	d.emit(DiffDelete, indent, prefix, oldVal)
+	d.emit(DiffInsert, indent, prefix, newVal)
+}
+
+func (d *jsonDiffer) object(indent string, oldObj, newObj map[string]any) {
+	keys := make([]string, 0, len(oldObj)+len(newObj))
+	for k := range oldObj {
+		keys = append(keys, k)
+	}
+	for k := range newObj {
+		if _, ok := oldObj[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		prefix := canonicalJSON(k, "") + ": "
+		oldVal, inOld := oldObj[k]
+		newVal, inNew := newObj[k]
+		switch {
+		case !inNew:
+			d.emit(DiffDelete, indent, prefix, oldVal)
+		case !inOld:
+			d.emit(DiffInsert, indent, prefix, newVal)
+		default:
+			d.value(indent, prefix, oldVal, newVal)
+		}
+	}
+}
+
+// array aligns elements by their canonical encoding, then pairs the removed
+// and added elements of each changed run so a modified statement is diffed
+// in place instead of shown as removed and re-added.
+func (d *jsonDiffer) array(indent string, oldArr, newArr []any) {
+	encode := func(arr []any) []string {
+		out := make([]string, len(arr))
+		for i, v := range arr {
+			out[i] = canonicalJSON(v, "")
+		}
+		return out
+	}
+	ops := computeDiffWith(DiffMyers, encode(oldArr), encode(newArr))
+
+	i, j := 0, 0
+	for k := 0; k < len(ops); {
+		if ops[k].Op == DiffEqual {
+			d.emit(DiffEqual, indent, "", oldArr[i])
+			i++
+			j++
+			k++
+			continue
+		}
+		delStart, insStart := i, j
+		for k < len(ops) && ops[k].Op != DiffEqual {
+			if ops[k].Op == DiffDelete {
+				i++
+			} else {
+				j++
+			}
+			k++
+		}
+		removed, added := oldArr[delStart:i], newArr[insStart:j]
+		paired := min(len(removed), len(added))
+		for p := 0; p < paired; p++ {
+			d.value(indent, "", removed[p], added[p])
+		}
+		for _, v := range removed[paired:] {
+			d.emit(DiffDelete, indent, "", v)
+		}
+		for _, v := range added[paired:] {
+			d.emit(DiffInsert, indent, "", v)
+		}
+	}
+}
+
+// canonicalJSON encodes v with sorted object keys and without HTML escaping.
+func canonicalJSON(v any, indent string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if indent != "" {
+		enc.SetIndent("", indent)
+	}
+	if err := enc.Encode(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// renderJSONFoldDiff renders a structural JSON diff with the configured
+// amount of context around each change.
+func renderJSONFoldDiff(block foldBlock, baseIndent string, maxWidth, contextSize int) string {
+	diff := block.JSONDiff
+	title, label := "json diff (keys sorted)", "json diff"
+	if block.IAMPolicy {
+		title, label = "iam policy diff (by statement)", "iam policy diff"
+	}
+	var b strings.Builder
+	b.WriteString(baseIndent)
+	b.WriteString(mutedColor.Render("┄┄┄ " + title + " ┄┄┄"))
+	b.WriteString("\n")
+	if contextDiff := ContextDiff(diff, contextSize); contextDiff != nil {
+		renderDiffLines(&b, contextDiff, baseIndent, maxWidth)
+	} else {
+		b.WriteString(baseIndent)
+		b.WriteString(mutedColor.Render("  (no semantic changes, key order or formatting only)"))
+		b.WriteString("\n")
+	}
+	b.WriteString(baseIndent)
+	b.WriteString(mutedColor.Render("┄┄┄ end " + label + " ┄┄┄"))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// jsonDiffKind names the kind of structural diff shown in a fold header.
+func jsonDiffKind(block foldBlock) string {
+	if block.IAMPolicy {
+		return "iam policy diff"
+	}
+	return "json diff"
+}
+
+// jsonDiffSummary counts changed lines for the fold header.
+func jsonDiffSummary(diff []DiffLine) string {
+	added, removed := 0, 0
+	for _, d := range diff {
+		switch d.Op {
+		case DiffInsert:
+			added++
+		case DiffDelete:
+			removed++
+		}
+	}
+	if added == 0 && removed == 0 {
+		return "no semantic changes"
+	}
+	summary := fmt.Sprintf("+%d -%d", added, removed)
+	if n := iamNewWildcards(diff); n > 0 {
+		summary += fmt.Sprintf(", ⚠ %d new wildcard(s)", n)
+	}
+	return summary
+}
+
+// jsonEncodeFrame is an object or array being rebuilt from a jsonencode diff,
+// holding the old ([0]) and new ([1]) versions side by side.
+type jsonEncodeFrame struct {
+	isArray bool
+	key     string
+	in      [2]bool
+	obj     [2]map[string]any
+	arr     [2][]any
+}
+
+func (f *jsonEncodeFrame) set(side int, key string, v any) {
+	if f.isArray {
+		f.arr[side] = append(f.arr[side], v)
+		return
+	}
+	f.obj[side][key] = v
+}
+
+func (f *jsonEncodeFrame) value(side int) any {
+	if f.isArray {
+		if f.arr[side] == nil {
+			return []any{}
+		}
+		return f.arr[side]
+	}
+	return f.obj[side]
+}
+
+// parseJSONEncodeDiff rebuilds the old and new documents from Terraform's
+// rendering of a changed jsonencode(...) value, starting at the
+// `~ key = jsonencode(` line. It returns the index just past the closing
+// parenthesis, which may carry a "# forces replacement" comment. Values
+// hidden as "unchanged" are missing from both sides, so the documents are
+// only suitable for diffing against each other.
+func parseJSONEncodeDiff(lines []string, start int) (end int, oldDoc, newDoc any, ok bool) {
+	header := strings.TrimRight(stripDiffPrefix(strings.TrimLeft(lines[start], " \t")), " ")
+	if !strings.HasPrefix(strings.TrimLeft(lines[start], " \t"), "~ ") || !strings.HasSuffix(header, "= jsonencode(") {
+		return 0, nil, nil, false
+	}
+
+	var stack []*jsonEncodeFrame
+	var root [2]any
+	for i := start + 1; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if len(stack) == 0 {
+			if root[0] != nil || root[1] != nil {
+				if trimmed == ")" || strings.HasPrefix(trimmed, ") #") {
+					return i + 1, root[0], root[1], true
+				}
+				return 0, nil, nil, false
+			}
+		}
+
+		prefix := byte(' ')
+		content := trimmed
+		if hasDiffPrefix(trimmed) {
+			prefix = trimmed[0]
+			content = trimmed[2:]
+		}
+		in := [2]bool{true, true}
+		if len(stack) > 0 {
+			in = stack[len(stack)-1].in
+		}
+		switch prefix {
+		case '+':
+			in[0] = false
+		case '-':
+			in[1] = false
+		}
+
+		// Closing a container attaches it to its parent on each side.
+		if c := strings.TrimSuffix(content, ","); c == "}" || c == "]" {
+			if len(stack) == 0 {
+				return 0, nil, nil, false
+			}
+			f := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for side := 0; side < 2; side++ {
+				if !f.in[side] {
+					continue
+				}
+				if len(stack) == 0 {
+					root[side] = f.value(side)
+				} else {
+					stack[len(stack)-1].set(side, f.key, f.value(side))
+				}
+			}
+			continue
+		}
+
+		key, value, isAttr := splitJSONEncodeAttr(content)
+		if len(stack) > 0 && !stack[len(stack)-1].isArray && !isAttr {
+			return 0, nil, nil, false
+		}
+		if !isAttr {
+			value = strings.TrimSuffix(content, ",")
+		}
+
+		if value == "{" || value == "[" {
+			f := &jsonEncodeFrame{isArray: value == "[", key: key, in: in}
+			for side := 0; side < 2; side++ {
+				f.obj[side] = make(map[string]any)
+			}
+			stack = append(stack, f)
+			continue
+		}
+		if len(stack) == 0 {
+			return 0, nil, nil, false
+		}
+
+		oldLit, newLit := value, value
+		if before, after, found := strings.Cut(value, " -> "); found {
+			oldLit, newLit = before, after
+		}
+		for side, lit := range []string{oldLit, newLit} {
+			if !in[side] {
+				continue
+			}
+			v, ok := parseJSONEncodeLiteral(lit)
+			if !ok {
+				return 0, nil, nil, false
+			}
+			stack[len(stack)-1].set(side, key, v)
+		}
+	}
+	return 0, nil, nil, false
+}
+
+// splitJSONEncodeAttr splits `Key = value` or `"quoted:key" = value`,
+// dropping Terraform's alignment padding around the equals sign.
+func splitJSONEncodeAttr(content string) (key, value string, ok bool) {
+	rest := content
+	if strings.HasPrefix(content, `"`) {
+		k, r, scanned := scanQuotedString(content)
+		if !scanned {
+			return "", "", false
+		}
+		key, rest = k, r
+	} else {
+		end := strings.IndexAny(content, " =")
+		if end <= 0 {
+			return "", "", false
+		}
+		key, rest = content[:end], content[end:]
+	}
+	rest = strings.TrimLeft(rest, " ")
+	if !strings.HasPrefix(rest, "= ") {
+		return "", "", false
+	}
+	return key, strings.TrimSpace(rest[2:]), true
+}
+
+// parseJSONEncodeLiteral converts an HCL literal as printed by Terraform
+// (strings, numbers, booleans, null) into a JSON value.
+func parseJSONEncodeLiteral(lit string) (any, bool) {
+	lit = strings.TrimSpace(lit)
+	if strings.HasPrefix(lit, `"`) {
+		s, _, ok := scanQuotedString(lit)
+		return s, ok
+	}
+	if lit == "(known after apply)" || lit == "(sensitive value)" {
+		return lit, true
+	}
+	dec := json.NewDecoder(strings.NewReader(lit))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, false
+	}
+	return v, true
+}
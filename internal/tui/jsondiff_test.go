@@ -0,0 +1,162 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+// collapseSpaces normalises runs of spaces so assertions don't depend on the
+// indentation of nested JSON lines.
+func collapseSpaces(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = strings.Join(strings.Fields(l), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestJSONStructuralDiffIgnoresKeyOrder(t *testing.T) {
+	oldDoc, _ := parseJSONDocument(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject"}]}`)
+	newDoc, _ := parseJSONDocument(`{"Statement":[{"Action":"s3:GetObject","Effect":"Allow"}],"Version":"2012-10-17"}`)
+	if got := countEdits(jsonStructuralDiff(oldDoc, newDoc)); got != 0 {
+		t.Fatalf("expected reordered keys to produce no changes, got %d edits", got)
+	}
+}
+
+func TestJSONStructuralDiffReportsChangedKeysAndElements(t *testing.T) {
+	oldDoc, _ := parseJSONDocument(`{"Statement":[{"Effect":"Allow","Action":["s3:GetObject"],"Resource":"*"}]}`)
+	newDoc, _ := parseJSONDocument(`{"Statement":[{"Effect":"Allow","Action":["s3:GetObject","s3:PutObject"],"Resource":"arn:aws:s3:::bucket/*"}]}`)
+
+	var added, removed []string
+	for _, d := range jsonStructuralDiff(oldDoc, newDoc) {
+		switch d.Op {
+		case DiffInsert:
+			added = append(added, strings.TrimSpace(d.Text))
+		case DiffDelete:
+			removed = append(removed, strings.TrimSpace(d.Text))
+		}
+	}
+	wantAdded := []string{`"s3:PutObject"`, `"Resource": "arn:aws:s3:::bucket/*"`}
+	wantRemoved := []string{`"Resource": "*"`}
+	if strings.Join(added, "|") != strings.Join(wantAdded, "|") {
+		t.Errorf("added = %q, want %q", added, wantAdded)
+	}
+	if strings.Join(removed, "|") != strings.Join(wantRemoved, "|") {
+		t.Errorf("removed = %q, want %q", removed, wantRemoved)
+	}
+}
+
+func TestJSONValueChangeRendersFoldedStructuralDiff(t *testing.T) {
+	r := parser.Resource{Address: "aws_iam_policy.p", Action: parser.ActionUpdate}
+	lines := []string{
+		`      ~ policy = "{\"Version\":\"2012-10-17\",\"Statement\":[{\"Effect\":\"Allow\",\"Action\":\"s3:GetObject\"}]}" -> "{\"Statement\":[{\"Action\":\"s3:*\",\"Effect\":\"Allow\"}],\"Version\":\"2012-10-17\"}"`,
+	}
+	r.RawLines = append([]string{`  ~ resource "test" "example" {`}, lines...)
+	blocks := findFoldBlocks(r, lines)
+	if len(blocks) != 1 || blocks[0].JSONDiff == nil {
+		t.Fatalf("expected a single JSON diff fold, got %#v", blocks)
+	}
+
+	got := collapseSpaces(renderExpandedWithDiffContextForTest(r, lines, 1))
+	for _, want := range []string{
+		`▼ ~ policy = iam policy diff (+1 -1, ⚠ 1 new wildcard(s))`,
+		`- Action: s3:GetObject`,
+		`+ Action: s3:* ⚠ new wildcard`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("rendered JSON diff missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, `\"Version\"`) {
+		t.Fatalf("rendered JSON diff still shows the escaped one-line value:\n%s", got)
+	}
+}
+
+func TestJSONValueChangeRendersNonPolicyStructuralDiff(t *testing.T) {
+	r := parser.Resource{Address: "aws_wafv2_web_acl.acl", Action: parser.ActionUpdate}
+	lines := []string{
+		`      ~ rules_json = "{\"Version\":\"1\",\"Rules\":[{\"Effect\":\"Allow\",\"Action\":\"s3:GetObject\"}]}" -> "{\"Rules\":[{\"Action\":\"s3:*\",\"Effect\":\"Allow\"}],\"Version\":\"1\"}"`,
+	}
+	r.RawLines = append([]string{`  ~ resource "test" "example" {`}, lines...)
+	blocks := findFoldBlocks(r, lines)
+	if len(blocks) != 1 || blocks[0].JSONDiff == nil || blocks[0].IAMPolicy {
+		t.Fatalf("expected a single JSON diff fold, got %#v", blocks)
+	}
+
+	got := collapseSpaces(renderExpandedWithDiffContextForTest(r, lines, 1))
+	for _, want := range []string{
+		`▼ ~ rules_json = json diff (+1 -1)`,
+		`- "Action": "s3:GetObject"`,
+		`+ "Action": "s3:*"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("rendered JSON diff missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestJSONEncodeChangeRendersStructuralDiff(t *testing.T) {
+	r := parser.Resource{Address: "aws_ecs_task_definition.app", Action: parser.ActionDeleteCreate}
+	lines := []string{
+		`      ~ container_definitions = jsonencode(`,
+		`          ~ [`,
+		`              ~ {`,
+		`                  ~ image        = "app:1" -> "app:2"`,
+		`                    name         = "app"`,
+		`                  ~ environment  = [`,
+		`                      + {`,
+		`                          + name  = "DEBUG"`,
+		`                          + value = "1"`,
+		`                        },`,
+		`                        # (1 unchanged element hidden)`,
+		`                    ]`,
+		`                    # (5 unchanged attributes hidden)`,
+		`                },`,
+		`            ]`,
+		`        ) # forces replacement`,
+		`        family                = "app"`,
+	}
+	r.RawLines = append([]string{`-/+ resource "aws_ecs_task_definition" "app" {`}, lines...)
+	blocks := findFoldBlocks(r, lines)
+	if len(blocks) != 1 || blocks[0].JSONDiff == nil || blocks[0].IAMPolicy || blocks[0].End != 16 {
+		t.Fatalf("expected one JSON diff fold over the jsonencode block, got %#v", blocks)
+	}
+
+	got := collapseSpaces(renderExpandedWithDiffContextForTest(r, lines, 3))
+	for _, want := range []string{
+		`▼ ~ container_definitions = json diff (+5 -1)`,
+		`- "image": "app:1"`,
+		`+ "image": "app:2"`,
+		`+ "name": "DEBUG",`,
+		`family = "app"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("rendered jsonencode diff missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "unchanged element hidden") {
+		t.Fatalf("rendered jsonencode diff still shows the raw block:\n%s", got)
+	}
+}
+
+func TestJSONHeredocPairUsesStructuralDiff(t *testing.T) {
+	r := parser.Resource{Address: "aws_ecs_task_definition.app", Action: parser.ActionUpdate}
+	lines := []string{
+		`      ~ container_definitions = [`,
+		`          - <<-EOT`,
+		`                [{"name": "app", "image": "app:1", "cpu": 256}]`,
+		`            EOT,`,
+		`          + <<-EOT`,
+		`                [{"cpu": 256, "image": "app:2", "name": "app"}]`,
+		`            EOT,`,
+		`        ]`,
+	}
+	got := collapseSpaces(renderExpandedForTest(r, lines))
+	for _, want := range []string{`json diff <<-EOT`, `- "image": "app:1"`, `+ "image": "app:2"`} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("rendered heredoc JSON diff missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/textinput"
@@ -13,6 +14,7 @@ import (
 	"github.com/muesli/reflow/wordwrap"
 
 	"github.com/CaptShanks/terraprism/internal/parser"
+	"github.com/CaptShanks/terraprism/internal/source"
 	"github.com/CaptShanks/terraprism/internal/updater"
 )
 
@@ -21,6 +23,7 @@ type Model struct {
 	plan               *parser.Plan
 	cursor             int
 	expanded           map[int]bool
+	sideBySide         map[int]bool // resources rendered as before | after columns
 	foldedBlocks       map[string]bool
 	blockCursor        int
 	diffContext        int
@@ -32,11 +35,18 @@ type Model struct {
 	searchInput        textinput.Model
 	searchQuery        string
 	searchMatches      []int
+	contentSearch      bool  // ? search matches resource bodies instead of addresses
+	contentMatchLines  []int // rendered line of each highlighted content match
+	contentMatchCursor int   // index into contentMatchLines for n/N
 	currentMatch       int
-	pendingG           bool  // Track if 'g' was pressed, waiting for second 'g'
-	resourceLineStarts []int // rendered line offset per resource (populated during render)
-	selectedLineStart  int   // rendered line offset for the current resource or sub-block cursor
-	contentLineCount   int   // total rendered content lines (excluding padding)
+	pendingG           bool       // Track if 'g' was pressed, waiting for second 'g'
+	resourceLineStarts []int      // rendered line offset per resource (populated during render)
+	selectedLineStart  int        // rendered line offset for the current resource or sub-block cursor
+	renderedFolds      []foldSpan // fold line ranges recorded by the last renderExpandedContent
+	contentLineCount   int        // total rendered content lines (excluding padding)
+	renders            *renderCache
+	windowStart        int // first content line drawn by the last render
+	windowEnd          int // content line after the last one drawn
 
 	// Apply mode fields
 	applyMode    bool   // Whether apply is available
@@ -55,6 +65,64 @@ type Model struct {
 	sorting    bool      // sort picker is open
 	sortCursor int       // cursor in sort picker
 
+	// Attribute pivot fields
+	pivoting            bool             // attribute pivot view is open
+	pivotCursor         int              // cursor in pivot view
+	pivotEntries        []attrPivotEntry // built when the pivot view opens
+	attrFilter          string           // attribute path drilled down into from the pivot
+	attrFilterResources map[int]bool     // resources changing attrFilter
+
+	// Dashboard fields
+	dashboard        bool             // plan summary dashboard is open
+	dashboardCursor  int              // cursor in the dashboard
+	dashboardEntries []dashboardEntry // built when the dashboard opens
+
+	// Query fields
+	query        *planQuery      // active query expression, nil when none
+	querying     bool            // query bar is open
+	queryInput   textinput.Model // query bar input
+	queryErr     string          // parse error shown under the query bar
+	pickingQuery bool            // saved query picker is open
+	queryCursor  int             // cursor in saved query picker
+	reviewed     map[string]bool // addresses marked reviewed with r
+
+	// Split-pane fields
+	split          bool           // list on the left, selected resource on the right
+	splitFocus     splitPane      // pane receiving navigation keys
+	splitPercent   int            // list pane width as a percentage of the screen
+	listOffset     int            // first resource shown in the list pane
+	detailViewport viewport.Model // scrolls the detail pane
+
+	// Plan comparison fields (compare mode)
+	delta      map[string]resourceDelta // by address, nil outside compare mode
+	deltaLabel string                   // name of the previous plan
+	deltaOnly  bool                     // hide resources unchanged since the previous plan
+
+	// Source preview fields
+	sourceDir     string          // working directory searched for .tf files, "." when empty
+	sourcePreview bool            // source preview is open
+	sourceAddr    string          // address of the previewed resource
+	sourceLoc     source.Location // located block
+	sourceErr     error           // why the block could not be located
+	sourceOffset  int             // first snippet line shown
+
+	exportStatus string // result of the last x/X export, cleared by the next key
+
+	// Watch mode fields (plan --watch)
+	watching     bool      // the plan is re-run when the configuration changes
+	watchRunning bool      // a re-run is in progress
+	watchReason  string    // what started the current re-run
+	watchLastRun time.Time // when the last run finished
+	watchErr     error     // failure of the last run, nil on success
+
+	hideTagsOnly   bool // hide updates that only change tags/labels
+	showSuppressed bool // z: ignore noise-suppression rules
+
+	tagsOnlyResources int // tags-only updates in the plan, see countTagsOnly
+
+	suppressedResources int // resources hidden by suppression rules, see countSuppressed
+	suppressedLines     int // body lines hidden or folded by suppression rules
+
 	// Update nudge
 	currentVersion  string // for update check
 	updateAvailable string // non-empty when newer version available
@@ -103,21 +171,32 @@ var filterableActions = []parser.Action{
 	parser.ActionOutput,
 }
 
-// filteredResources returns indices into plan.Resources that pass the status filter.
-// When statusFilters is empty or nil, returns all indices.
+// filteredResources returns indices into plan.Resources that pass the status
+// filter, the query, the attribute drill-down, the tags-only toggle,
+// resource-level suppression rules and the plan delta filter. When none is
+// active, returns all indices.
 func (m *Model) filteredResources() []int {
-	if len(m.statusFilters) == 0 {
-		indices := make([]int, len(m.plan.Resources))
-		for i := range m.plan.Resources {
-			indices[i] = i
-		}
-		return indices
-	}
-	var indices []int
+	indices := make([]int, 0, len(m.plan.Resources))
 	for i, r := range m.plan.Resources {
-		if m.statusFilters[r.Action] {
-			indices = append(indices, i)
+		if len(m.statusFilters) > 0 && !m.statusFilters[r.Action] {
+			continue
+		}
+		if m.query != nil && !m.query.Matches(r, m.reviewed[r.Address]) {
+			continue
+		}
+		if m.attrFilterResources != nil && !m.attrFilterResources[i] {
+			continue
+		}
+		if m.hideTagsOnly && isTagsOnlyChange(r) {
+			continue
+		}
+		if m.suppressionActive() && suppressActionFor(r, "") == SuppressHide {
+			continue
 		}
+		if m.deltaOnly && m.delta[r.Address].Kind == deltaUnchanged {
+			continue
+		}
+		indices = append(indices, i)
 	}
 	return indices
 }
@@ -186,19 +265,25 @@ func NewModel(plan *parser.Plan, version string) Model {
 	ti.CharLimit = 100
 	ti.Width = 40
 
-	return Model{
+	m := Model{
 		plan:           plan,
 		expanded:       make(map[int]bool),
 		foldedBlocks:   make(map[string]bool),
+		renders:        newRenderCache(),
 		blockCursor:    -1,
 		diffContext:    defaultDiffContext,
 		searchInput:    ti,
 		searchMatches:  []int{},
+		queryInput:     newQueryInput(),
+		reviewed:       make(map[string]bool),
 		applyMode:      false,
 		statusFilters:  nil, // nil = show all
 		sortOrder:      SortDefault,
 		currentVersion: version,
 	}
+	m.countSuppressed()
+	m.countTagsOnly()
+	return m
 }
 
 // NewModelWithApply creates a TUI model with apply capability
@@ -208,14 +293,17 @@ func NewModelWithApply(plan *parser.Plan, planFile, tfCommand, version string) M
 	ti.CharLimit = 100
 	ti.Width = 40
 
-	return Model{
+	m := Model{
 		plan:           plan,
 		expanded:       make(map[int]bool),
 		foldedBlocks:   make(map[string]bool),
+		renders:        newRenderCache(),
 		blockCursor:    -1,
 		diffContext:    defaultDiffContext,
 		searchInput:    ti,
 		searchMatches:  []int{},
+		queryInput:     newQueryInput(),
+		reviewed:       make(map[string]bool),
 		applyMode:      true,
 		planFile:       planFile,
 		tfCommand:      tfCommand,
@@ -223,6 +311,9 @@ func NewModelWithApply(plan *parser.Plan, planFile, tfCommand, version string) M
 		sortOrder:      SortDefault,
 		currentVersion: version,
 	}
+	m.countSuppressed()
+	m.countTagsOnly()
+	return m
 }
 
 // ShouldApply returns true if user chose to apply
@@ -251,6 +342,17 @@ func checkUpdateCmd(version string) tea.Cmd {
 
 // Update handles messages
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	updated, cmd := m.update(msg)
+	// Handlers may scroll the viewport past the lines the last render drew;
+	// render a fresh window here so View stays free of side effects.
+	if next, ok := updated.(Model); ok {
+		next.syncRenderWindow()
+		return next, cmd
+	}
+	return updated, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
 
@@ -265,6 +367,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case editorFinishedMsg:
+		return m.handleEditorFinished(msg), nil
+
+	case PlanRunStartedMsg:
+		return m.handlePlanRunStarted(msg), nil
+
+	case PlanRefreshedMsg:
+		return m.handlePlanRefreshed(msg), nil
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -278,6 +389,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if !m.ready {
 			m.viewport = viewport.New(msg.Width-4, msg.Height-headerHeight-footerHeight)
 			m.viewport.YPosition = headerHeight
+			// Sized by the split layout; New enables the mouse wheel.
+			m.detailViewport = viewport.New(0, 0)
 			m.ready = true
 		} else {
 			m.viewport.Width = msg.Width - 4
@@ -289,9 +402,24 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.filtering {
 			return m.handleFilterKey(msg)
 		}
+		if m.pivoting {
+			return m.handlePivotKey(msg)
+		}
+		if m.dashboard {
+			return m.handleDashboardKey(msg)
+		}
+		if m.sourcePreview {
+			return m.handleSourcePreviewKey(msg)
+		}
 		if m.sorting {
 			return m.handleSortKey(msg)
 		}
+		if m.pickingQuery {
+			return m.handleSavedQueryKey(msg)
+		}
+		if m.querying {
+			return m.handleQueryKey(msg)
+		}
 		if m.searching {
 			switch msg.String() {
 			case "enter":
@@ -305,6 +433,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.searchInput.SetValue("")
 				m.searchQuery = ""
 				m.searchMatches = []int{}
+				m.contentSearch = false
 				m.clampCursorAndRefreshSearch()
 				m.updateViewportContent()
 			case "up":
@@ -324,7 +453,23 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.MouseMsg:
-		m.viewport, cmd = m.viewport.Update(msg)
+		if m.split {
+			// The wheel over the list pane moves the cursor; anywhere else
+			// it scrolls the details. appStyle pads the panes by two columns.
+			listWidth, _ := m.splitPaneWidths()
+			if msg.X < listWidth+2 && msg.Action == tea.MouseActionPress &&
+				(msg.Button == tea.MouseButtonWheelUp || msg.Button == tea.MouseButtonWheelDown) {
+				delta := 1
+				if msg.Button == tea.MouseButtonWheelUp {
+					delta = -1
+				}
+				m.moveSplitCursor(delta)
+				break
+			}
+			m.detailViewport, cmd = m.detailViewport.Update(msg)
+		} else {
+			m.viewport, cmd = m.viewport.Update(msg)
+		}
 		cmds = append(cmds, cmd)
 	}
 
@@ -369,7 +514,22 @@ var normalKeyHandlers = map[string]normalKeyHandler{
 	"C":         handleKeyCollapseEverything,
 	"f":         handleKeyFilter,
 	"s":         handleKeySort,
+	"p":         handleKeyAttrPivot,
+	"D":         handleKeyDashboard,
+	"w":         handleKeyToggleDelta,
+	"o":         handleKeySourcePreview,
+	"O":         handleKeyOpenEditor,
+	"x":         handleKeyExportMarkdown,
+	"X":         handleKeyExportHTML,
+	"v":         handleKeySideBySide,
+	"|":         handleKeyToggleSplit,
+	"T":         handleKeyToggleTagsOnly,
+	"z":         handleKeyToggleSuppression,
 	"/":         handleKeySearch,
+	"?":         handleKeyContentSearch,
+	":":         handleKeyQuery,
+	"Q":         handleKeySavedQueries,
+	"r":         handleKeyToggleReviewed,
 	"n":         handleKeyNextMatch,
 	"N":         handleKeyPrevMatch,
 	"esc":       handleKeyEsc,
@@ -535,6 +695,8 @@ func handleKeySort(m Model) (Model, tea.Cmd, bool) {
 }
 
 func handleKeySearch(m Model) (Model, tea.Cmd, bool) {
+	m.contentSearch = false
+	m.searchInput.Placeholder = "Search..."
 	m.searching = true
 	m.searchInput.Focus()
 	return m, textinput.Blink, true
@@ -555,6 +717,13 @@ func handleKeyEsc(m Model) (Model, tea.Cmd, bool) {
 		m.statusFilters = nil
 		m.clampCursorAndRefreshSearch()
 		m.updateViewportContent()
+	} else if m.attrFilter != "" {
+		m.attrFilter = ""
+		m.attrFilterResources = nil
+		m.clampCursorAndRefreshSearch()
+		m.updateViewportContent()
+	} else if m.query != nil {
+		m.setQuery("")
 	} else {
 		m.clearSearch()
 	}
@@ -679,6 +848,13 @@ func (m Model) handleNormalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if key != "g" && key != "G" {
 		m.pendingG = false
 	}
+	m.exportStatus = ""
+
+	if m.split {
+		if newM, cmd, ok := m.handleSplitKey(key); ok {
+			return newM, cmd
+		}
+	}
 
 	if handler, ok := normalKeyHandlers[key]; ok {
 		newM, cmd, _ := handler(m)
@@ -817,7 +993,8 @@ func (m Model) currentFoldBlocks() []foldBlock {
 	if len(r.RawLines) <= 1 {
 		return nil
 	}
-	return m.visibleFoldBlocks(findFoldBlocks(r, r.RawLines[1:]))
+	lines, _ := m.expandedLines(r)
+	return m.visibleFoldBlocks(findFoldBlocks(r, lines))
 }
 
 func (m *Model) currentFoldBlock() (foldBlock, bool) {
@@ -833,7 +1010,7 @@ func (m *Model) toggleCurrentFold() bool {
 	if !ok {
 		return false
 	}
-	m.foldedBlocks[block.Key] = !m.isFoldCollapsed(block)
+	m.setFold(m.currentResourceIndex(), block, !m.isFoldCollapsed(block))
 	return true
 }
 
@@ -842,7 +1019,7 @@ func (m *Model) setCurrentFoldCollapsed(collapsed bool) bool {
 	if !ok {
 		return false
 	}
-	m.foldedBlocks[block.Key] = collapsed
+	m.setFold(m.currentResourceIndex(), block, collapsed)
 	return true
 }
 
@@ -852,7 +1029,9 @@ func (m *Model) setCurrentScopeFoldsCollapsed(collapsed bool) bool {
 		return false
 	}
 
-	blocks := findFoldBlocks(m.plan.Resources[resourceIdx], m.plan.Resources[resourceIdx].RawLines[1:])
+	r := m.plan.Resources[resourceIdx]
+	lines, _ := m.expandedLines(r)
+	blocks := findFoldBlocks(r, lines)
 	if len(blocks) == 0 {
 		return false
 	}
@@ -861,7 +1040,7 @@ func (m *Model) setCurrentScopeFoldsCollapsed(collapsed bool) bool {
 		changed := false
 		for _, block := range blocks {
 			if block.Start >= current.Start && block.End <= current.End {
-				m.foldedBlocks[block.Key] = collapsed
+				m.setFold(resourceIdx, block, collapsed)
 				changed = true
 			}
 		}
@@ -869,7 +1048,7 @@ func (m *Model) setCurrentScopeFoldsCollapsed(collapsed bool) bool {
 	}
 
 	for _, block := range blocks {
-		m.foldedBlocks[block.Key] = collapsed
+		m.setFold(resourceIdx, block, collapsed)
 	}
 	return true
 }
@@ -883,8 +1062,9 @@ func (m *Model) setDisplayedFoldsCollapsed(collapsed bool) {
 		if len(r.RawLines) <= 1 {
 			continue
 		}
-		for _, block := range findFoldBlocks(r, r.RawLines[1:]) {
-			m.foldedBlocks[block.Key] = collapsed
+		lines, _ := m.expandedLines(r)
+		for _, block := range findFoldBlocks(r, lines) {
+			m.setFold(resourceIdx, block, collapsed)
 		}
 	}
 }
@@ -935,6 +1115,10 @@ func (m *Model) nextMatch() {
 	if m.searchQuery == "" || len(m.searchMatches) == 0 {
 		return
 	}
+	if m.contentSearch {
+		m.stepContentMatch(1)
+		return
+	}
 	displayed := m.displayedResourceIndices()
 	if len(displayed) > 0 {
 		m.currentMatch = (m.currentMatch + 1) % len(displayed)
@@ -949,6 +1133,10 @@ func (m *Model) prevMatch() {
 	if m.searchQuery == "" || len(m.searchMatches) == 0 {
 		return
 	}
+	if m.contentSearch {
+		m.stepContentMatch(-1)
+		return
+	}
 	displayed := m.displayedResourceIndices()
 	if len(displayed) > 0 {
 		m.currentMatch--
@@ -965,6 +1153,8 @@ func (m *Model) prevMatch() {
 func (m *Model) clearSearch() {
 	m.searchQuery = ""
 	m.searchMatches = []int{}
+	m.contentSearch = false
+	m.contentMatchLines = nil
 	m.searchInput.SetValue("")
 	m.updateViewportContent()
 }
@@ -1032,6 +1222,10 @@ func (m *Model) performSearch() {
 	if m.searchQuery == "" {
 		return // displayedResourceIndices will show full list
 	}
+	if m.contentSearch {
+		m.performContentSearch()
+		return
+	}
 
 	terms := strings.Fields(strings.ToLower(m.searchQuery))
 	if len(terms) == 0 {
@@ -1066,6 +1260,10 @@ func (m *Model) updateViewportContent() {
 	if !m.ready {
 		return
 	}
+	if m.split {
+		m.updateSplitPanes()
+		return
+	}
 	m.viewport.SetContent(m.renderResources())
 }
 
@@ -1130,12 +1328,18 @@ func (m *Model) scrollForExpanded() {
 	m.ensureCursorVisible()
 }
 
+// renderResources renders the resource list for the viewport. Line offsets
+// come from the cached bodies' line counts; only resources intersecting the
+// render window are drawn, and the runs of lines around them are written as
+// blank lines so the viewport's scroll offsets stay exact. The viewport still
+// splits one (blank) line per content line on every render.
 func (m *Model) renderResources() string {
 	var b strings.Builder
 	lineCount := 0
 
 	displayed := m.displayedResourceIndices()
 	m.resourceLineStarts = make([]int, len(displayed))
+	m.contentMatchLines = nil
 
 	if len(displayed) == 0 {
 		if m.searchQuery != "" {
@@ -1147,34 +1351,74 @@ func (m *Model) renderResources() string {
 		return b.String()
 	}
 
+	contentQuery := ""
+	if m.contentSearchActive() {
+		contentQuery = strings.TrimSpace(m.searchQuery)
+	}
+	m.windowStart, m.windowEnd = m.renderWindow()
+
 	m.selectedLineStart = 0
+	blank := 0 // off-screen lines not yet written
 	for displayIdx, resourceIdx := range displayed {
-		m.resourceLineStarts[displayIdx] = lineCount
+		start := lineCount
+		m.resourceLineStarts[displayIdx] = start
 		r := m.plan.Resources[resourceIdx]
 
 		isSelected := displayIdx == m.cursor
 		isExpanded := m.expanded[resourceIdx]
 		isMatch := m.searchQuery != "" // when filtering, all displayed items match
-		if isSelected && m.blockCursor < 0 {
-			m.selectedLineStart = lineCount
+
+		var body *renderedBody
+		if isExpanded && len(r.RawLines) > 1 {
+			body = m.renderBody(resourceIdx, isSelected && m.blockCursor >= 0)
+		}
+		lineCount++
+		if body != nil {
+			lineCount += body.lines + 1
+		}
+		if isSelected {
+			if m.blockCursor < 0 {
+				m.selectedLineStart = start
+			} else if body != nil && body.selectedLine >= 0 {
+				m.selectedLineStart = start + 1 + body.selectedLine
+			}
 		}
 
+		// Content search counts occurrences in every resource, so off-screen
+		// resources are still rendered (from cache) while it is active.
+		visible := start < m.windowEnd && lineCount > m.windowStart
+		if !visible {
+			blank += lineCount - start
+			if contentQuery == "" {
+				continue
+			}
+		}
+
+		var text string
 		if isSelected {
-			line := m.renderSelectedResourceLine(r, isExpanded, isMatch)
-			b.WriteString(line)
+			text = m.renderSelectedResourceLine(r, isExpanded, isMatch)
 		} else {
-			line := m.renderResourceLine(r, isExpanded, isMatch)
-			b.WriteString(line)
+			text = m.renderResourceLine(r, isExpanded, isMatch)
+		}
+		text += "\n"
+		if body != nil {
+			text += body.text + "\n"
 		}
-		b.WriteString("\n")
-		lineCount++
 
-		if isExpanded && len(r.RawLines) > 1 {
-			m.renderExpandedContent(&b, r, isSelected && m.blockCursor >= 0, &lineCount)
-			b.WriteString("\n")
-			lineCount++
+		if contentQuery != "" {
+			var matchLines []int
+			text, matchLines = highlightContentMatches(text, contentQuery, m.contentMatchCursor-len(m.contentMatchLines))
+			for _, line := range matchLines {
+				m.contentMatchLines = append(m.contentMatchLines, start+line)
+			}
+		}
+		if visible {
+			b.WriteString(strings.Repeat("\n", blank))
+			blank = 0
+			b.WriteString(text)
 		}
 	}
+	b.WriteString(strings.Repeat("\n", blank))
 
 	m.contentLineCount = lineCount
 
@@ -1206,16 +1450,42 @@ type foldBlock struct {
 	OldLineCount   int
 	NewLineCount   int
 	HeredocEndMark string
+	JSONDiff       []DiffLine // structural diff when the value is a JSON document
+	IAMPolicy      bool       // JSONDiff is a statement-level IAM policy diff
+	PairDiff       []DiffLine // decoded diff when a heredoc pair holds YAML or PEM
+	PairDiffLabel  string     // body label for PairDiff, e.g. "yaml diff"
+	TagTable       *tagTable  // tags/tags_all/labels map rendered as a table
+	SecurityRules  bool       // inline firewall rules summarised by the rule table
+	Suppressed     bool       // folded by default by a collapse suppression rule
 }
 
 func findFoldBlocks(r parser.Resource, lines []string) []foldBlock {
 	var blocks []foldBlock
+	ruleTable := len(securityRuleTypes[r.Type]) > 0 && hasSecurityRuleTable(r)
 	for idx := 0; idx < len(lines); idx++ {
 		if idx == 0 && isResourceDeclarationLine(lines[idx]) {
 			continue
 		}
 
 		if block, ok := findHeredocPairFold(r, lines, idx); ok {
+			markJSONHeredocPair(&block, lines)
+			if block.JSONDiff == nil {
+				markBlobHeredocPair(&block, lines)
+			}
+			if block.JSONDiff == nil && block.PairDiff == nil {
+				markYAMLHeredocPair(&block, lines)
+			}
+			blocks = append(blocks, block)
+			idx = block.End - 1
+			continue
+		}
+
+		if block, ok := findJSONValueFold(r, lines, idx); ok {
+			blocks = append(blocks, block)
+			continue
+		}
+
+		if block, ok := findJSONEncodeFold(r, lines, idx); ok {
 			blocks = append(blocks, block)
 			idx = block.End - 1
 			continue
@@ -1235,9 +1505,14 @@ func findFoldBlocks(r parser.Resource, lines []string) []foldBlock {
 		}
 		end := findBalancedStructureBlockEnd(lines, idx)
 		if end > idx+1 {
-			blocks = append(blocks, newFoldBlock(r, lines, idx, end, false))
+			block := newFoldBlock(r, lines, idx, end, false)
+			markTagTable(&block, lines)
+			block.SecurityRules = ruleTable && isSecurityRuleFold(r, lines[idx])
+			blocks = append(blocks, block)
 		}
 	}
+	linkDerivedTagTables(blocks, lines)
+	markSuppressedFolds(r, blocks, lines)
 	return blocks
 }
 
@@ -1347,6 +1622,9 @@ func (m *Model) isFoldCollapsed(block foldBlock) bool {
 	if collapsed, ok := m.foldedBlocks[block.Key]; ok {
 		return collapsed
 	}
+	if block.TagTable != nil && block.TagTable.Derived || block.SecurityRules || block.Suppressed && !m.showSuppressed {
+		return true
+	}
 	return block.LineCount >= defaultCollapsedFoldLines
 }
 
@@ -1359,13 +1637,24 @@ func (m Model) renderFoldHeader(line string, action parser.Action, block foldBlo
 	rendered := m.wrapAndColorize(line, action, maxWidth)
 	indent := extractIndent(line)
 	content := strings.TrimPrefix(rendered, indent)
-	if block.HeredocPair {
+	switch {
+	case block.HeredocPair && block.JSONDiff != nil:
+		content = updateSymbol + " " + mutedColor.Render(fmt.Sprintf("%s <<-%s", jsonDiffKind(block), block.HeredocEndMark))
+	case block.HeredocPair:
 		content = updateSymbol + " " + mutedColor.Render(fmt.Sprintf("heredoc diff <<-%s", block.HeredocEndMark))
+	case block.JSONDiff != nil:
+		key, _, _ := strings.Cut(stripDiffPrefix(strings.TrimLeft(line, " \t")), " = ")
+		content = updateSymbol + " " + attrNameStyle.Render(strings.TrimSpace(key)) + " = " + mutedColor.Render(jsonDiffKind(block))
 	}
 	result := indent + indicator + " " + content
-	if block.HeredocPair {
+	switch {
+	case block.JSONDiff != nil:
+		result += mutedColor.Render(fmt.Sprintf(" (%s)", jsonDiffSummary(block.JSONDiff)))
+	case block.TagTable != nil:
+		result += mutedColor.Render(fmt.Sprintf(" (%s)", tagTableSummary(block.TagTable)))
+	case block.HeredocPair:
 		result += mutedColor.Render(fmt.Sprintf(" (%d → %d lines)", block.OldLineCount, block.NewLineCount))
-	} else if collapsed {
+	case collapsed:
 		result += mutedColor.Render(fmt.Sprintf(" ... (%d lines)", block.LineCount))
 	}
 	if !selected {
@@ -1382,16 +1671,14 @@ func (m Model) renderFoldHeader(line string, action parser.Action, block foldBlo
 	return lipgloss.NewStyle().Background(selectedBg).Foreground(textColor).Render(result)
 }
 
-func renderExpandedHeredocLines(lines []string) string {
+func renderExpandedHeredocLines(header string, lines []string) string {
 	if len(lines) == 0 {
 		return ""
 	}
 
-	contentLines := lines[:len(lines)-1]
-	baseIndent := heredocContentBaseIndent(contentLines)
 	var b strings.Builder
-	for _, contentLine := range contentLines {
-		b.WriteString(colorizeHeredocContentLine(contentLine, baseIndent))
+	for _, contentLine := range highlightHeredocBody(header, lines[:len(lines)-1]) {
+		b.WriteString(contentLine)
 		b.WriteString("\n")
 	}
 	b.WriteString(lines[len(lines)-1])
@@ -1400,23 +1687,32 @@ func renderExpandedHeredocLines(lines []string) string {
 }
 
 func renderHeredocPairFoldDiff(lines []string, block foldBlock, maxWidth int, contextSize int) string {
-	oldContent := extractHeredocContent(lines[block.Start+1 : block.OldEnd-1])
-	newContent := extractHeredocContent(lines[block.AddStart+1 : block.End-1])
+	baseIndent := extractIndent(lines[block.Start])
+	label := "heredoc diff"
+	diff := block.PairDiff
+	if diff != nil {
+		label = block.PairDiffLabel
+	} else {
+		oldContent := extractHeredocContent(lines[block.Start+1 : block.OldEnd-1])
+		newContent := extractHeredocContent(lines[block.AddStart+1 : block.End-1])
+		diff = ComputeDiff(oldContent, newContent)
+	}
 
-	diff := ComputeDiff(oldContent, newContent)
 	contextDiff := ContextDiff(diff, contextSize)
 	if contextDiff == nil {
-		return ""
+		if block.PairDiff == nil {
+			return ""
+		}
+		return baseIndent + mutedColor.Render("  (no semantic changes, key order or formatting only)") + "\n"
 	}
 
-	baseIndent := extractIndent(lines[block.Start])
 	var b strings.Builder
 	b.WriteString(baseIndent)
-	b.WriteString(mutedColor.Render("┄┄┄ heredoc diff ┄┄┄"))
+	b.WriteString(mutedColor.Render("┄┄┄ " + label + " ┄┄┄"))
 	b.WriteString("\n")
 	renderDiffLines(&b, contextDiff, baseIndent, maxWidth)
 	b.WriteString(baseIndent)
-	b.WriteString(mutedColor.Render("┄┄┄ end heredoc diff ┄┄┄"))
+	b.WriteString(mutedColor.Render("┄┄┄ end " + label + " ┄┄┄"))
 	b.WriteString("\n")
 	return b.String()
 }
@@ -1426,15 +1722,44 @@ func renderHeredocPairFoldDiff(lines []string, block foldBlock, maxWidth int, co
 // collapsible folds for multiline attributes and nested blocks.
 func (m *Model) renderExpandedContent(b *strings.Builder, r parser.Resource, selected bool, lineCount *int) {
 	maxWidth := m.viewport.Width
-	lines := r.RawLines[1:]
+	lines, paths := m.expandedLines(r)
 	folds := findFoldBlocks(r, lines)
 	foldsByStart := make(map[int]foldBlock, len(folds))
 	for _, block := range folds {
 		foldsByStart[block.Start] = block
 	}
 
+	if delta := m.renderPlanDelta(r, maxWidth); delta != "" {
+		b.WriteString(delta)
+		*lineCount += strings.Count(delta, "\n")
+	}
+	if table := renderSecurityRuleChanges(r, maxWidth); table != "" {
+		b.WriteString(table)
+		*lineCount += strings.Count(table, "\n")
+	}
+
+	// Fold line ranges are measured on the builder, since decoded and wrapped
+	// lines span several rendered lines. counted advances so each byte is
+	// scanned once.
+	counted, countedLines := 0, 0
+	renderedLine := func() int {
+		text := b.String()
+		countedLines += strings.Count(text[counted:], "\n")
+		counted = len(text)
+		return countedLines
+	}
+	var open []int // indices into m.renderedFolds of folds not yet closed
+	closeFolds := func(idx int) {
+		for len(open) > 0 && m.renderedFolds[open[len(open)-1]].block.End <= idx {
+			m.renderedFolds[open[len(open)-1]].end = renderedLine()
+			open = open[:len(open)-1]
+		}
+	}
+	defer func() { closeFolds(len(lines)) }()
+
 	foldIdx := 0
 	for idx := 0; idx < len(lines); idx++ {
+		closeFolds(idx)
 		line := lines[idx]
 
 		if decoded, ok := m.tryRenderUserdata(line, r.Action, maxWidth); ok {
@@ -1444,11 +1769,20 @@ func (m *Model) renderExpandedContent(b *strings.Builder, r parser.Resource, sel
 			continue
 		}
 
+		if decoded, ok := m.tryRenderBlob(line, r.Action, maxWidth); ok {
+			b.WriteString(decoded)
+			b.WriteString("\n")
+			*lineCount += strings.Count(decoded, "\n") + 1
+			continue
+		}
+
 		if block, ok := foldsByStart[idx]; ok {
 			blockSelected := selected && foldIdx == m.blockCursor
 			if blockSelected {
 				m.selectedLineStart = *lineCount
 			}
+			open = append(open, len(m.renderedFolds))
+			m.renderedFolds = append(m.renderedFolds, foldSpan{block: block, start: renderedLine()})
 			collapsed := m.isFoldCollapsed(block)
 			b.WriteString(m.renderFoldHeader(line, r.Action, block, collapsed, blockSelected, maxWidth))
 			b.WriteString("\n")
@@ -1459,6 +1793,20 @@ func (m *Model) renderExpandedContent(b *strings.Builder, r parser.Resource, sel
 				idx = block.End - 1
 				continue
 			}
+			if block.TagTable != nil {
+				rendered := renderTagTable(block.TagTable, extractIndent(line), maxWidth)
+				b.WriteString(rendered)
+				*lineCount += strings.Count(rendered, "\n")
+				idx = block.End - 1
+				continue
+			}
+			if block.JSONDiff != nil {
+				rendered := renderJSONFoldDiff(block, extractIndent(line), maxWidth, m.diffContextSize())
+				b.WriteString(rendered)
+				*lineCount += strings.Count(rendered, "\n")
+				idx = block.End - 1
+				continue
+			}
 			if block.HeredocPair {
 				rendered := renderHeredocPairFoldDiff(lines, block, maxWidth, m.diffContextSize())
 				b.WriteString(rendered)
@@ -1467,7 +1815,13 @@ func (m *Model) renderExpandedContent(b *strings.Builder, r parser.Resource, sel
 				continue
 			}
 			if block.Heredoc {
-				rendered := renderExpandedHeredocLines(lines[idx+1 : block.End])
+				_, _, lineAction := parseUserdataLinePrefix(strings.TrimLeft(line, " \t"), r.Action)
+				rendered, ok := renderHeredocBlob(lines[idx+1:block.End-1], extractIndent(line), lineAction, maxWidth)
+				if ok {
+					rendered += lines[block.End-1] + "\n"
+				} else {
+					rendered = renderExpandedHeredocLines(line, lines[idx+1:block.End])
+				}
 				b.WriteString(rendered)
 				*lineCount += strings.Count(rendered, "\n")
 				idx = block.End - 1
@@ -1491,6 +1845,14 @@ func (m *Model) renderExpandedContent(b *strings.Builder, r parser.Resource, sel
 			continue
 		}
 
+		if isDimmedLine(r, paths, idx) {
+			dimmed := renderDimmedLine(line, maxWidth)
+			b.WriteString(dimmed)
+			b.WriteString("\n")
+			*lineCount += strings.Count(dimmed, "\n") + 1
+			continue
+		}
+
 		coloredLine := m.wrapAndColorize(line, r.Action, maxWidth)
 		b.WriteString(coloredLine)
 		b.WriteString("\n")
@@ -1542,14 +1904,11 @@ func (m Model) tryRenderHeredocBlock(lines []string, idx int, action parser.Acti
 		return 0, ""
 	}
 
-	contentLines := lines[idx+1 : end-1]
-	baseIndent := heredocContentBaseIndent(contentLines)
-
 	var b strings.Builder
 	b.WriteString(m.wrapAndColorize(lines[idx], action, maxWidth))
 	b.WriteString("\n")
-	for _, contentLine := range contentLines {
-		b.WriteString(colorizeHeredocContentLine(contentLine, baseIndent))
+	for _, contentLine := range highlightHeredocBody(lines[idx], lines[idx+1:end-1]) {
+		b.WriteString(contentLine)
 		b.WriteString("\n")
 	}
 	b.WriteString(lines[end-1])
@@ -1615,15 +1974,14 @@ func heredocContentBaseIndent(lines []string) int {
 	return minAll
 }
 
-func colorizeHeredocContentLine(line string, baseIndent int) string {
+// colorizeHeredocContentLine colors Terraform's diff prefix on a heredoc
+// body line (when it sits in the prefix column) and highlights the content
+// for lang.
+func colorizeHeredocContentLine(line string, baseIndent int, lang heredocLang) string {
 	trimmed := strings.TrimLeft(line, " \t")
-	if !hasDiffPrefix(trimmed) || baseIndent < 2 {
-		return line
-	}
-
 	indent := line[:len(line)-len(trimmed)]
-	if len(indent) != baseIndent-2 {
-		return line
+	if !hasDiffPrefix(trimmed) || baseIndent < 2 || len(indent) != baseIndent-2 {
+		return indent + highlightCode(lang, trimmed)
 	}
 
 	prefix := trimmed[:1]
@@ -1637,7 +1995,7 @@ func colorizeHeredocContentLine(line string, baseIndent int) string {
 		prefix = lipgloss.NewStyle().Foreground(updateColor).Render(prefix)
 	}
 
-	return indent + prefix + " " + content
+	return indent + prefix + " " + highlightCode(lang, content)
 }
 
 // wrapAndColorize wraps a raw HCL line to the viewport width and colorizes
@@ -1728,7 +2086,17 @@ func (m Model) renderUserdataDiff(oldB64, newB64, key, decodedIndent string, hea
 	b.WriteString(decodedIndent)
 	b.WriteString(mutedColor.Render("┄┄┄ decoded " + key + " ┄┄┄"))
 	b.WriteString("\n")
-	if oldOk && newOk {
+	oldParts, oldMIME := SplitUserdataMIME(oldDecoded)
+	newParts, newMIME := SplitUserdataMIME(newDecoded)
+	if oldOk && newOk && (oldMIME || newMIME) {
+		if !oldMIME {
+			oldParts = []UserdataPart{userdataSinglePart(oldDecoded)}
+		}
+		if !newMIME {
+			newParts = []UserdataPart{userdataSinglePart(newDecoded)}
+		}
+		m.renderUserdataPartsDiff(&b, oldParts, newParts, decodedIndent, maxWidth)
+	} else if oldOk && newOk {
 		oldLines := strings.Split(oldDecoded, "\n")
 		newLines := strings.Split(newDecoded, "\n")
 		diff := ComputeDiff(oldLines, newLines)
@@ -1761,6 +2129,95 @@ func (m Model) renderUserdataDiff(oldB64, newB64, key, decodedIndent string, hea
 	return b.String()
 }
 
+// userdataSinglePart wraps non-MIME userdata as a part so it can be diffed
+// against a multipart archive, guessing its type from the first line.
+func userdataSinglePart(content string) UserdataPart {
+	p := UserdataPart{ContentType: "text/plain", Content: content}
+	switch first, _, _ := strings.Cut(content, "\n"); {
+	case strings.HasPrefix(first, "#!"):
+		p.ContentType = "text/x-shellscript"
+	case strings.HasPrefix(first, "#cloud-config"):
+		p.ContentType = "text/cloud-config"
+	case strings.HasPrefix(first, "#cloud-boothook"):
+		p.ContentType = "text/cloud-boothook"
+	}
+	return p
+}
+
+// userdataPartKeys identifies MIME parts by filename when every part on both
+// sides has a unique one, otherwise by content type and occurrence.
+func userdataPartKeys(oldParts, newParts []UserdataPart) (oldKeys, newKeys []string) {
+	byName := true
+	for _, parts := range [][]UserdataPart{oldParts, newParts} {
+		seen := make(map[string]bool, len(parts))
+		for _, p := range parts {
+			if p.Filename == "" || seen[p.Filename] {
+				byName = false
+			}
+			seen[p.Filename] = true
+		}
+	}
+	keys := func(parts []UserdataPart) []string {
+		out := make([]string, len(parts))
+		count := make(map[string]int)
+		for i, p := range parts {
+			if byName {
+				out[i] = p.Filename
+				continue
+			}
+			count[p.ContentType]++
+			out[i] = fmt.Sprintf("%s#%d", p.ContentType, count[p.ContentType])
+		}
+		return out
+	}
+	return keys(oldParts), keys(newParts)
+}
+
+// renderUserdataPartsDiff diffs two cloud-init multipart archives part by
+// part, labelling each part by its content type.
+func (m Model) renderUserdataPartsDiff(b *strings.Builder, oldParts, newParts []UserdataPart, indent string, maxWidth int) {
+	oldKeys, newKeys := userdataPartKeys(oldParts, newParts)
+
+	partHeader := func(symbol string, p UserdataPart, note string) {
+		b.WriteString(indent)
+		b.WriteString(symbol + " " + mutedColor.Render("part: "+p.Label()+note))
+		b.WriteString("\n")
+	}
+	writeAll := func(p UserdataPart, prefix string, style lipgloss.Style) {
+		for _, l := range strings.Split(p.Content, "\n") {
+			for _, wl := range strings.Split(wrapText(l, maxWidth-len(indent)-4), "\n") {
+				b.WriteString(indent)
+				b.WriteString(style.Render(prefix + wl))
+				b.WriteString("\n")
+			}
+		}
+	}
+
+	mergeOrdered(oldKeys, newKeys, func(_ string, oldIdx, newIdx int) {
+		switch {
+		case newIdx < 0:
+			partHeader(destroySymbol, oldParts[oldIdx], " (removed)")
+			writeAll(oldParts[oldIdx], "- ", lipgloss.NewStyle().Foreground(destroyColor))
+		case oldIdx < 0:
+			partHeader(createSymbol, newParts[newIdx], " (added)")
+			writeAll(newParts[newIdx], "+ ", lipgloss.NewStyle().Foreground(createColor))
+		default:
+			oldPart, newPart := oldParts[oldIdx], newParts[newIdx]
+			note := ""
+			if oldPart.ContentType != newPart.ContentType {
+				note = " (was " + oldPart.Label() + ")"
+			}
+			contextDiff := ContextDiff(ComputeDiff(strings.Split(oldPart.Content, "\n"), strings.Split(newPart.Content, "\n")), m.diffContextSize())
+			if contextDiff == nil {
+				partHeader(" ", newPart, note+" (unchanged)")
+				return
+			}
+			partHeader(updateSymbol, newPart, note)
+			renderDiffLines(b, contextDiff, indent, maxWidth)
+		}
+	})
+}
+
 func userdataLineStyle(lineAction parser.Action) lipgloss.Style {
 	switch lineAction {
 	case parser.ActionCreate:
@@ -1815,13 +2272,24 @@ func (m Model) tryRenderUserdata(line string, action parser.Action, maxWidth int
 	b.WriteString(mutedColor.Render("┄┄┄ decoded " + key + " ┄┄┄"))
 	b.WriteString("\n")
 	style := userdataLineStyle(lineAction)
-	for _, dl := range strings.Split(decoded, "\n") {
-		wrapped := wrapText(dl, maxWidth-len(decodedIndent)-2)
-		for _, wl := range strings.Split(wrapped, "\n") {
+	parts, isMIME := SplitUserdataMIME(decoded)
+	if !isMIME {
+		parts = []UserdataPart{{Content: decoded}}
+	}
+	for _, p := range parts {
+		if isMIME {
 			b.WriteString(decodedIndent)
-			b.WriteString(style.Render("  " + wl))
+			b.WriteString(mutedColor.Render("  part: " + p.Label()))
 			b.WriteString("\n")
 		}
+		for _, dl := range strings.Split(p.Content, "\n") {
+			wrapped := wrapText(dl, maxWidth-len(decodedIndent)-2)
+			for _, wl := range strings.Split(wrapped, "\n") {
+				b.WriteString(decodedIndent)
+				b.WriteString(style.Render("  " + wl))
+				b.WriteString("\n")
+			}
+		}
 	}
 	b.WriteString(decodedIndent)
 	b.WriteString(mutedColor.Render("┄┄┄ end " + key + " ┄┄┄"))
@@ -2000,21 +2468,28 @@ func (m Model) renderPrefixedBlockDiff(lines []string, idx int, action parser.Ac
 }
 
 // renderDiffLines writes context-diff lines into a builder, handling all
-// DiffOp types including DiffSeparator for collapsed equal runs.
+// DiffOp types including DiffSeparator for collapsed equal runs. A run of
+// deleted lines followed by inserted lines is paired line by line so the
+// changed words within each pair are highlighted.
 func renderDiffLines(b *strings.Builder, diff []DiffLine, indent string, maxWidth int) {
-	for _, d := range diff {
+	for i := 0; i < len(diff); i++ {
+		d := diff[i]
 		switch d.Op {
 		case DiffSeparator:
 			b.WriteString(indent)
 			b.WriteString(mutedColor.Render("@@ ··· @@"))
 			b.WriteString("\n")
 		case DiffDelete:
-			wrapped := wrapText(d.Text, maxWidth-len(indent)-4)
-			for _, wl := range strings.Split(wrapped, "\n") {
-				b.WriteString(indent)
-				b.WriteString(lipgloss.NewStyle().Foreground(destroyColor).Render("- " + wl))
-				b.WriteString("\n")
+			delEnd := i
+			for delEnd < len(diff) && diff[delEnd].Op == DiffDelete {
+				delEnd++
 			}
+			insEnd := delEnd
+			for insEnd < len(diff) && diff[insEnd].Op == DiffInsert {
+				insEnd++
+			}
+			renderChangedRun(b, diff[i:delEnd], diff[delEnd:insEnd], indent, maxWidth)
+			i = insEnd - 1
 		case DiffInsert:
 			wrapped := wrapText(d.Text, maxWidth-len(indent)-4)
 			for _, wl := range strings.Split(wrapped, "\n") {
@@ -2033,6 +2508,50 @@ func renderDiffLines(b *strings.Builder, diff []DiffLine, indent string, maxWidt
 	}
 }
 
+// renderChangedRun writes a block of deleted lines followed by the inserted
+// lines that replace them, with intraline emphasis on paired lines.
+func renderChangedRun(b *strings.Builder, deletes, inserts []DiffLine, indent string, maxWidth int) {
+	oldStyle := lipgloss.NewStyle().Foreground(destroyColor)
+	newStyle := lipgloss.NewStyle().Foreground(createColor)
+	oldRendered := make([]string, len(deletes))
+	newRendered := make([]string, len(inserts))
+	for k := range deletes {
+		if k < len(inserts) && intralineComparable(deletes[k].Text, inserts[k].Text) {
+			if oldSegs, newSegs, ok := intralineSegments(deletes[k].Text, inserts[k].Text); ok {
+				oldRendered[k] = renderSegments(oldSegs, oldStyle, attrOldEmphStyle.Strikethrough(false))
+				newRendered[k] = renderSegments(newSegs, newStyle, attrNewEmphStyle)
+			}
+		}
+	}
+
+	width := maxWidth - len(indent) - 4
+	for k, d := range deletes {
+		writeDiffRunLine(b, indent, d.Text, oldRendered[k], "- ", oldStyle, width)
+	}
+	for k, d := range inserts {
+		writeDiffRunLine(b, indent, d.Text, newRendered[k], "+ ", newStyle, width)
+	}
+}
+
+// writeDiffRunLine writes one wrapped diff line. rendered holds the
+// intraline-highlighted text, or is empty to render text in style.
+func writeDiffRunLine(b *strings.Builder, indent, text, rendered, prefix string, style lipgloss.Style, width int) {
+	if rendered == "" {
+		for _, wl := range strings.Split(wrapText(text, width), "\n") {
+			b.WriteString(indent)
+			b.WriteString(style.Render(prefix + wl))
+			b.WriteString("\n")
+		}
+		return
+	}
+	for _, wl := range strings.Split(wrapText(rendered, width), "\n") {
+		b.WriteString(indent)
+		b.WriteString(style.Render(prefix))
+		b.WriteString(wl)
+		b.WriteString("\n")
+	}
+}
+
 func extractHeredocContent(lines []string) []string {
 	result := make([]string, 0, len(lines))
 	for _, line := range lines {
@@ -2103,6 +2622,12 @@ func (m Model) renderSelectedResourceLine(r parser.Resource, expanded bool, _ bo
 
 	// Resource address
 	content.WriteString(r.Address)
+	if m.reviewed[r.Address] {
+		content.WriteString(" " + reviewedMark)
+	}
+	if badge, _ := m.deltaBadge(r); badge != "" {
+		content.WriteString(" " + badge)
+	}
 
 	// Action description
 	actionDesc := getActionDescription(r.Action)
@@ -2147,6 +2672,9 @@ func (m Model) renderResourceLine(r parser.Resource, expanded bool, isMatch bool
 
 	// Resource address
 	style := GetResourceStyle(string(r.Action))
+	if m.suppressionActive() && suppressActionFor(r, "") == SuppressDim {
+		style = mutedColor
+	}
 	address := r.Address
 
 	if isMatch && m.searchQuery != "" {
@@ -2155,6 +2683,12 @@ func (m Model) renderResourceLine(r parser.Resource, expanded bool, isMatch bool
 	}
 
 	b.WriteString(style.Render(address))
+	if m.reviewed[r.Address] {
+		b.WriteString(" " + mutedColor.Render(reviewedMark))
+	}
+	if badge, color := m.deltaBadge(r); badge != "" {
+		b.WriteString(" " + lipgloss.NewStyle().Foreground(color).Render(badge))
+	}
 
 	// Action description
 	actionDesc := getActionDescription(r.Action)
@@ -2246,12 +2780,16 @@ func (m Model) colorizeValue(value string, action parser.Action) string {
 		return lipgloss.NewStyle().Foreground(replaceColor).Italic(true).Render(value)
 	}
 
-	// Change arrow: old -> new
+	// Change arrow: old -> new, with the changed words emphasised
 	if strings.Contains(value, " -> ") {
 		parts := strings.SplitN(value, " -> ", 2)
 		oldVal := strings.TrimSpace(parts[0])
-		newVal := strings.TrimSpace(parts[1])
-		return attrOldValueStyle.Render(oldVal) + " → " + attrNewValueStyle.Render(newVal)
+		newVal, comment := splitTrailingComment(strings.TrimSpace(parts[1]))
+		if intralineComparable(oldVal, newVal) {
+			oldRendered, newRendered := renderIntraline(oldVal, newVal, attrOldValueStyle, attrNewValueStyle, attrOldEmphStyle, attrNewEmphStyle)
+			return oldRendered + " → " + newRendered + comment
+		}
+		return attrOldValueStyle.Render(oldVal) + " → " + attrNewValueStyle.Render(newVal) + comment
 	}
 
 	// null
@@ -2280,6 +2818,16 @@ func (m Model) colorizeValue(value string, action parser.Action) string {
 	}
 }
 
+// splitTrailingComment separates a Terraform annotation such as
+// "# forces replacement" from a value and returns it rendered muted.
+func splitTrailingComment(value string) (string, string) {
+	idx := strings.Index(value, " # ")
+	if idx < 0 {
+		return value, ""
+	}
+	return value[:idx], " " + mutedColor.Render(strings.TrimSpace(value[idx:]))
+}
+
 func highlightMatch(text, query string) string {
 	lower := strings.ToLower(text)
 	lowerQuery := strings.ToLower(query)
@@ -2472,6 +3020,9 @@ func (m Model) viewSortStatus() string {
 
 // viewSearchBar renders the search bar or match info.
 func (m Model) viewSearchBar() string {
+	if m.contentSearch && (m.searching || m.searchQuery != "") {
+		return m.viewContentSearchBar()
+	}
 	if m.searching {
 		return searchStyle.Render("Search: ") + m.searchInput.View() + "\n\n"
 	}
@@ -2513,14 +3064,31 @@ func (m Model) viewHelpFooter() string {
 		return fmt.Sprintf("%s • j/k nav • e/c all • / search • q", applyHint)
 	}
 
+	if m.split {
+		focus := "list"
+		if m.splitFocus == detailPane {
+			focus = "details"
+		}
+		helpOptions := []string{
+			fmt.Sprintf("Split (%s) • tab: switch pane • </>: resize • j/k: navigate • Enter/l: open • h: back • d/u: scroll • /: search • ?: content • f: filter • |: single pane • q: quit", focus),
+			"tab: pane • </>: resize • j/k • Enter/l/h • d/u • / ? • |: single • q",
+		}
+		for _, help := range helpOptions {
+			if lipgloss.Width(help) <= maxWidth {
+				return help
+			}
+		}
+		return helpOptions[len(helpOptions)-1]
+	}
+
 	helpOptions := []string{
-		"j/k/↑↓: navigate • l/→: expand • h/←/⌫: collapse • e/c: scope • E/C: all • +/-: diff context • Ctrl+E/Y: line scroll • d/u: page scroll • gg/G: top/bottom • /: search • f: filter • s: sort • q: quit",
-		"j/k: nav • l/h: fold • e/c: scope • E/C: all • +/-: diff ctx • Ctrl+E/Y: line • d/u: page • /: search • f/s • q",
+		"j/k/↑↓: navigate • l/→: expand • h/←/⌫: collapse • e/c: scope • E/C: all • +/-: diff context • Ctrl+E/Y: line scroll • d/u: page scroll • gg/G: top/bottom • /: search • f: filter • s: sort • D: dashboard • o/O: source/editor • x/X: export md/html • p: attributes • v: side-by-side • |: split pane • T: hide tags-only • z: suppression • ?: content search • :: query • Q: saved queries • r: reviewed • q: quit",
+		"j/k: nav • l/h: fold • e/c: scope • E/C: all • +/-: diff ctx • Ctrl+E/Y: line • d/u: page • /: search • f/s • D: dash • o: source • x: export • p: attrs • v: split • T: tags • z: noise • ?: content • :/Q: query • r: reviewed • q",
 		"j/k nav • l/h fold • e/c scope • E/C all • +/- diff • Ctrl+E/Y scroll • / search • q",
 		"j/k nav • l/h fold • e/c • q",
 	}
 
-	if len(m.statusFilters) > 0 {
+	if len(m.statusFilters) > 0 || m.attrFilter != "" || m.query != nil {
 		for i, help := range helpOptions {
 			helpOptions[i] = help + " • Esc clears filter"
 		}
@@ -2554,14 +3122,37 @@ func (m Model) View() string {
 	if m.sorting {
 		return m.viewSortPicker()
 	}
+	if m.pivoting {
+		return m.viewAttrPivot()
+	}
+	if m.dashboard {
+		return m.viewDashboard()
+	}
+	if m.sourcePreview {
+		return m.viewSourcePreview()
+	}
+	if m.pickingQuery {
+		return m.viewSavedQueries()
+	}
 
 	var b strings.Builder
 	b.WriteString(m.viewHeader())
 	b.WriteString(m.viewFilterStatus())
+	b.WriteString(m.viewWatchStatus())
+	b.WriteString(m.viewExportStatus())
+	b.WriteString(m.viewDeltaStatus())
+	b.WriteString(m.viewQueryBar())
+	b.WriteString(m.viewAttrFilterStatus())
+	b.WriteString(m.viewTagsOnlyStatus())
+	b.WriteString(m.viewSuppressionStatus())
 	b.WriteString(m.viewSortStatus())
 	b.WriteString(m.viewSearchBar())
 	b.WriteString(m.viewConfirmationPrompt())
-	b.WriteString(m.viewport.View())
+	if m.split {
+		b.WriteString(m.viewSplitPanes())
+	} else {
+		b.WriteString(m.viewport.View())
+	}
 	b.WriteString("\n")
 	b.WriteString(helpStyle.Render(m.viewHelpFooter()))
 	b.WriteString(m.viewUpdateNudge())
@@ -0,0 +1,356 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+// attrPivotEntry aggregates every change to one attribute path across the plan.
+type attrPivotEntry struct {
+	Path      string
+	Resources []int             // indices into plan.Resources, in plan order
+	Changes   []attrValueChange // distinct old → new pairs, most frequent first
+}
+
+// attrValueChange counts how many times a specific old → new pair appears.
+type attrValueChange struct {
+	Old   string
+	New   string
+	Count int
+}
+
+const maxPivotChangesInline = 3
+
+// buildAttrPivot groups the parsed attribute changes of every resource by
+// attribute path. Container lines (maps, lists, nested blocks) are skipped so
+// the pivot lists leaf values only.
+func buildAttrPivot(plan *parser.Plan) []attrPivotEntry {
+	if plan == nil {
+		return nil
+	}
+
+	byPath := make(map[string]*attrPivotEntry)
+	seen := make(map[string]map[int]bool)
+	changeIdx := make(map[string]map[attrValueChange]int)
+
+	for resourceIdx, r := range plan.Resources {
+		if r.Action == parser.ActionOutput {
+			continue
+		}
+		for _, attr := range r.Attributes {
+			path, oldVal, newVal, ok := pivotAttrValues(attr)
+			if !ok {
+				continue
+			}
+			entry, exists := byPath[path]
+			if !exists {
+				entry = &attrPivotEntry{Path: path}
+				byPath[path] = entry
+				seen[path] = make(map[int]bool)
+				changeIdx[path] = make(map[attrValueChange]int)
+			}
+			if !seen[path][resourceIdx] {
+				seen[path][resourceIdx] = true
+				entry.Resources = append(entry.Resources, resourceIdx)
+			}
+			key := attrValueChange{Old: oldVal, New: newVal}
+			if idx, ok := changeIdx[path][key]; ok {
+				entry.Changes[idx].Count++
+				continue
+			}
+			changeIdx[path][key] = len(entry.Changes)
+			key.Count = 1
+			entry.Changes = append(entry.Changes, key)
+		}
+	}
+
+	entries := make([]attrPivotEntry, 0, len(byPath))
+	for _, entry := range byPath {
+		sort.SliceStable(entry.Changes, func(i, j int) bool {
+			return entry.Changes[i].Count > entry.Changes[j].Count
+		})
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if len(entries[i].Resources) != len(entries[j].Resources) {
+			return len(entries[i].Resources) > len(entries[j].Resources)
+		}
+		return entries[i].Path < entries[j].Path
+	})
+	return entries
+}
+
+// pivotAttrValues normalises an attribute into (path, old, new). Bare list
+// elements carry their value in Name, so it is moved to the old or new side
+// based on the element's action.
+func pivotAttrValues(attr parser.Attribute) (path, oldVal, newVal string, ok bool) {
+	path = attr.Path
+	if path == "" {
+		path = attr.Name
+	}
+	if path == "" {
+		return "", "", "", false
+	}
+
+	oldVal = cleanPivotValue(attr.OldValue)
+	newVal = cleanPivotValue(attr.NewValue)
+	if oldVal == "" && newVal == "" {
+		element := strings.TrimSuffix(strings.TrimSpace(attr.Name), ",")
+		if element == "" || isPivotStructural(element) {
+			return "", "", "", false
+		}
+		switch attr.Action {
+		case parser.ActionDestroy:
+			oldVal = element
+		default:
+			newVal = element
+		}
+		return path, oldVal, newVal, true
+	}
+
+	if isPivotStructural(oldVal) || isPivotStructural(newVal) {
+		return "", "", "", false
+	}
+	if attr.Action == parser.ActionDestroy && newVal == "" {
+		// A removed value reads "old -> null"; the null is the new side.
+		oldVal = strings.TrimSuffix(oldVal, " -> null")
+		newVal = "null"
+	}
+	if attr.Action == parser.ActionCreate && oldVal == "" {
+		oldVal = "null"
+	}
+	return path, oldVal, newVal, true
+}
+
+func cleanPivotValue(v string) string {
+	v = strings.TrimSpace(v)
+	if idx := strings.Index(v, " # "); idx >= 0 {
+		v = strings.TrimSpace(v[:idx])
+	}
+	return strings.TrimSuffix(v, ",")
+}
+
+func isPivotStructural(v string) bool {
+	switch {
+	case v == "", v == "null":
+		return false
+	case strings.HasSuffix(v, "{"), strings.HasSuffix(v, "["), strings.HasSuffix(v, "("):
+		return true
+	case strings.HasPrefix(v, "}"), strings.HasPrefix(v, "]"), strings.HasPrefix(v, ")"):
+		return true
+	}
+	return false
+}
+
+// formatPivotChange renders a single old → new pair with its occurrence count.
+func formatPivotChange(c attrValueChange) string {
+	var s string
+	switch {
+	case c.Old == "null" || c.Old == "":
+		s = attrNewValueStyle.Render(c.New)
+	case c.New == "null" || c.New == "":
+		s = lipgloss.NewStyle().Foreground(destroyColor).Render(c.Old) + " → " + mutedColor.Render("null")
+	default:
+		s = attrOldValueStyle.Render(c.Old) + " → " + attrNewValueStyle.Render(c.New)
+	}
+	return s + mutedColor.Render(fmt.Sprintf(" ×%d", c.Count))
+}
+
+func handleKeyAttrPivot(m Model) (Model, tea.Cmd, bool) {
+	m.pivotEntries = buildAttrPivot(m.plan)
+	m.pivoting = true
+	m.pivotCursor = 0
+	for i, entry := range m.pivotEntries {
+		if entry.Path == m.attrFilter {
+			m.pivotCursor = i
+			break
+		}
+	}
+	return m, nil, true
+}
+
+// handlePivotKey handles key presses in the attribute pivot view
+func (m Model) handlePivotKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q", "p":
+		m.pivoting = false
+		m.updateViewportContent()
+		return m, nil
+
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "enter", " ", "l", "right":
+		if m.pivotCursor < 0 || m.pivotCursor >= len(m.pivotEntries) {
+			return m, nil
+		}
+		entry := m.pivotEntries[m.pivotCursor]
+		m.attrFilter = entry.Path
+		m.attrFilterResources = make(map[int]bool, len(entry.Resources))
+		for _, idx := range entry.Resources {
+			m.attrFilterResources[idx] = true
+		}
+		m.pivoting = false
+		m.cursor = 0
+		m.clampCursorAndRefreshSearch()
+		m.updateViewportContent()
+		m.viewport.GotoTop()
+		return m, nil
+
+	case "up", "k":
+		if m.pivotCursor > 0 {
+			m.pivotCursor--
+		}
+		return m, nil
+
+	case "down", "j":
+		if m.pivotCursor < len(m.pivotEntries)-1 {
+			m.pivotCursor++
+		}
+		return m, nil
+
+	case "d", "ctrl+d", "pgdown":
+		m.pivotCursor += m.pivotPageSize() / 2
+		if m.pivotCursor >= len(m.pivotEntries) {
+			m.pivotCursor = len(m.pivotEntries) - 1
+		}
+		if m.pivotCursor < 0 {
+			m.pivotCursor = 0
+		}
+		return m, nil
+
+	case "u", "ctrl+u", "pgup":
+		m.pivotCursor -= m.pivotPageSize() / 2
+		if m.pivotCursor < 0 {
+			m.pivotCursor = 0
+		}
+		return m, nil
+
+	case "g", "home":
+		m.pivotCursor = 0
+		return m, nil
+
+	case "G", "end":
+		if len(m.pivotEntries) > 0 {
+			m.pivotCursor = len(m.pivotEntries) - 1
+		}
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// pivotPageSize returns how many entry rows fit above the detail panel.
+func (m Model) pivotPageSize() int {
+	rows := m.height - 16
+	if rows < 5 {
+		rows = 5
+	}
+	return rows
+}
+
+// viewAttrPivot renders the attribute pivot view (returns full view, caller returns early).
+func (m Model) viewAttrPivot() string {
+	var b strings.Builder
+	b.WriteString(searchStyle.Render(fmt.Sprintf("Changed attributes (%d) — Enter: show affected resources, Esc: close", len(m.pivotEntries))))
+	b.WriteString("\n\n")
+
+	if len(m.pivotEntries) == 0 {
+		b.WriteString(mutedColor.Render("  No attribute changes found in this plan."))
+		b.WriteString("\n")
+		return appStyle.Render(b.String())
+	}
+
+	pathWidth := 0
+	for _, entry := range m.pivotEntries {
+		if w := lipgloss.Width(entry.Path); w > pathWidth {
+			pathWidth = w
+		}
+	}
+	if pathWidth > 48 {
+		pathWidth = 48
+	}
+
+	pageSize := m.pivotPageSize()
+	start := 0
+	if m.pivotCursor >= pageSize {
+		start = m.pivotCursor - pageSize + 1
+	}
+	end := start + pageSize
+	if end > len(m.pivotEntries) {
+		end = len(m.pivotEntries)
+	}
+
+	for i := start; i < end; i++ {
+		entry := m.pivotEntries[i]
+		path := entry.Path
+		if lipgloss.Width(path) > pathWidth {
+			path = truncate.StringWithTail(path, uint(pathWidth), "…")
+		}
+		row := fmt.Sprintf("  %s  %4d resource(s)  %d distinct change(s)", padCell(path, pathWidth), len(entry.Resources), len(entry.Changes))
+		rowStyle := lipgloss.NewStyle().Foreground(textColor)
+		if i == m.pivotCursor {
+			rowStyle = rowStyle.Background(selectedBg).Bold(true)
+		}
+		b.WriteString(rowStyle.Render(row))
+		b.WriteString("\n")
+	}
+	if end < len(m.pivotEntries) {
+		b.WriteString(mutedColor.Render(fmt.Sprintf("  ... %d more", len(m.pivotEntries)-end)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.viewPivotDetail(m.pivotEntries[m.pivotCursor]))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("j/k: navigate • d/u: page • g/G: top/bottom • Enter: drill down • Esc/p: close"))
+	return appStyle.Render(b.String())
+}
+
+// viewPivotDetail renders the value distribution and affected resources for one entry.
+func (m Model) viewPivotDetail(entry attrPivotEntry) string {
+	var b strings.Builder
+	b.WriteString(headerStyle.UnsetMarginBottom().Render(entry.Path))
+	b.WriteString("\n")
+
+	shown := entry.Changes
+	if len(shown) > maxPivotChangesInline {
+		shown = shown[:maxPivotChangesInline]
+	}
+	for _, c := range shown {
+		b.WriteString("  ")
+		b.WriteString(formatPivotChange(c))
+		b.WriteString("\n")
+	}
+	if extra := len(entry.Changes) - len(shown); extra > 0 {
+		b.WriteString(mutedColor.Render(fmt.Sprintf("  ... %d more distinct change(s)", extra)))
+		b.WriteString("\n")
+	}
+
+	var addresses []string
+	for _, idx := range entry.Resources {
+		addresses = append(addresses, m.plan.Resources[idx].Address)
+	}
+	summary := strings.Join(addresses, ", ")
+	if maxWidth := m.width - 12; maxWidth > 20 && lipgloss.Width(summary) > maxWidth {
+		summary = truncate.StringWithTail(summary, uint(maxWidth), "…")
+	}
+	b.WriteString(mutedColor.Render("  in: " + summary))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// viewAttrFilterStatus renders the drill-down status line when an attribute filter is active.
+func (m Model) viewAttrFilterStatus() string {
+	if m.attrFilter == "" {
+		return ""
+	}
+	return searchStyle.Render(fmt.Sprintf("Attribute: %s (%d resources) • p: pivot • Esc: clear", m.attrFilter, len(m.attrFilterResources))) + "\n\n"
+}
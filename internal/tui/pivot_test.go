@@ -0,0 +1,182 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+const pivotTestPlan = `
+  # aws_instance.a will be updated in-place
+  ~ resource "aws_instance" "a" {
+      ~ instance_type = "t3.micro" -> "t3.large"
+      ~ root_block_device {
+          ~ volume_size = 50 -> 100
+        }
+    }
+
+  # aws_instance.b will be updated in-place
+  ~ resource "aws_instance" "b" {
+      ~ instance_type = "t3.micro" -> "t3.large"
+    }
+
+  # aws_instance.c will be updated in-place
+  ~ resource "aws_instance" "c" {
+      ~ instance_type = "t3.small" -> "t3.large"
+      ~ security_groups = [
+          - "sg-old",
+          + "sg-new",
+        ]
+    }
+`
+
+func TestBuildAttrPivotGroupsByPath(t *testing.T) {
+	plan, err := parser.Parse(pivotTestPlan)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	entries := buildAttrPivot(plan)
+	if len(entries) == 0 || entries[0].Path != "instance_type" {
+		t.Fatalf("expected instance_type to be the most common path, got %#v", entries)
+	}
+	first := entries[0]
+	if len(first.Resources) != 3 {
+		t.Fatalf("instance_type resources = %v, want 3", first.Resources)
+	}
+	if len(first.Changes) != 2 || first.Changes[0].Count != 2 || first.Changes[0].Old != `"t3.micro"` {
+		t.Fatalf("unexpected instance_type distribution: %#v", first.Changes)
+	}
+
+	byPath := make(map[string]attrPivotEntry)
+	for _, entry := range entries {
+		byPath[entry.Path] = entry
+	}
+	if _, ok := byPath["root_block_device.volume_size"]; !ok {
+		t.Fatalf("expected nested attribute path, got %#v", entries)
+	}
+	sg, ok := byPath["security_groups"]
+	if !ok {
+		t.Fatalf("expected list element changes under security_groups, got %#v", entries)
+	}
+	if len(sg.Changes) != 2 {
+		t.Fatalf("security_groups changes = %#v, want removed and added element", sg.Changes)
+	}
+	if _, ok := byPath["root_block_device"]; ok {
+		t.Fatalf("did not expect container block as pivot entry: %#v", entries)
+	}
+}
+
+func TestAttrPivotDrillDownFiltersResources(t *testing.T) {
+	plan, err := parser.Parse(pivotTestPlan)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	m := NewModel(plan, "")
+	m.viewport = viewport.New(80, 20)
+
+	m, _, _ = handleKeyAttrPivot(m)
+	if !m.pivoting {
+		t.Fatal("expected pivot view to open")
+	}
+	for i, entry := range m.pivotEntries {
+		if entry.Path == "root_block_device.volume_size" {
+			m.pivotCursor = i
+		}
+	}
+
+	updated, _ := m.handlePivotKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.pivoting {
+		t.Fatal("expected drill-down to close the pivot view")
+	}
+	displayed := m.displayedResourceIndices()
+	if len(displayed) != 1 || plan.Resources[displayed[0]].Address != "aws_instance.a" {
+		t.Fatalf("drill-down displayed %v, want only aws_instance.a", displayed)
+	}
+
+	m, _, _ = handleKeyEsc(m)
+	if got := len(m.displayedResourceIndices()); got != 3 {
+		t.Fatalf("expected Esc to clear attribute drill-down, got %d resources", got)
+	}
+}
+
+func TestAttrPivotTruncatesWidePaths(t *testing.T) {
+	plan, err := parser.Parse(pivotTestPlan)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	m := NewModel(plan, "")
+	m.viewport = viewport.New(80, 20)
+	m.width = 60
+	m, _, _ = handleKeyAttrPivot(m)
+	m.pivotEntries = []attrPivotEntry{{
+		Path:      "tags." + strings.Repeat("é", 60),
+		Resources: []int{0, 1, 2},
+		Changes:   []attrValueChange{{Old: `"a"`, New: `"b"`, Count: 3}},
+	}}
+	for i := range plan.Resources {
+		plan.Resources[i].Address = `aws_instance.a["` + strings.Repeat("ü", 20) + `"]`
+	}
+
+	view := m.viewAttrPivot()
+	if !utf8.ValidString(view) {
+		t.Fatalf("truncation split a multi-byte rune:\n%q", view)
+	}
+	plain := stripRenderANSI(view)
+	if !strings.Contains(plain, "tags."+strings.Repeat("é", 42)+"…") {
+		t.Errorf("long path should be cut to 48 columns with an ellipsis:\n%s", plain)
+	}
+	if !strings.Contains(plain, `in: aws_instance.a["`+strings.Repeat("ü", 20)+`"], aws_ins…`) {
+		t.Errorf("long resource summary should be cut to the view width:\n%s", plain)
+	}
+}
+
+func TestBuildAttrPivotRemovedValue(t *testing.T) {
+	plan, err := parser.Parse(`
+  # aws_instance.a will be updated in-place
+  ~ resource "aws_instance" "a" {
+      - volume_size = 10 -> null
+    }
+`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	entries := buildAttrPivot(plan)
+	if len(entries) != 1 || len(entries[0].Changes) != 1 {
+		t.Fatalf("expected one volume_size change, got %#v", entries)
+	}
+	if got := stripRenderANSI(formatPivotChange(entries[0].Changes[0])); got != "10 → null ×1" {
+		t.Errorf("removed value rendered as %q", got)
+	}
+}
+
+func TestAttrPivotAlignsWidePaths(t *testing.T) {
+	plan, err := parser.Parse(pivotTestPlan)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	m := NewModel(plan, "")
+	m.viewport = viewport.New(80, 20)
+	m, _, _ = handleKeyAttrPivot(m)
+	m.pivotEntries = []attrPivotEntry{
+		{Path: "tags.日本語", Resources: []int{0}},
+		{Path: "tags.abcdef", Resources: []int{1}},
+	}
+
+	columns := map[int]bool{}
+	for _, line := range strings.Split(stripRenderANSI(m.viewAttrPivot()), "\n") {
+		if idx := strings.Index(line, " resource(s)"); idx >= 0 {
+			columns[lipgloss.Width(line[:idx])] = true
+		}
+	}
+	if len(columns) != 1 {
+		t.Errorf("resource counts should line up by display width, got columns %v", columns)
+	}
+}
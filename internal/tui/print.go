@@ -2,7 +2,7 @@ package tui
 
 import (
 	"fmt"
-	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/CaptShanks/terraprism/internal/parser"
@@ -11,243 +11,164 @@ import (
 )
 
 func init() {
-	// Force color output even when not a TTY (for piping)
+	// The TUI draws its cursor and highlights with colors, so render in full
+	// color by default; command-line output picks its profile with
+	// UseColorMode.
 	lipgloss.SetColorProfile(termenv.TrueColor)
 }
 
-// PrintPlan outputs the plan with colors to stdout (non-interactive mode)
-func PrintPlan(plan *parser.Plan) {
-	// Header
-	fmt.Println(headerStyle.Render("🔺 Terra-Prism - Terraform Plan Viewer"))
-	fmt.Println()
+// defaultPrintWidth is the wrap width when none is given, e.g. in CI logs.
+const defaultPrintWidth = 120
 
-	// Summary
-	if plan.Summary != "" {
-		summary := fmt.Sprintf("Plan: %s to add, %s to change, %s to destroy",
-			lipgloss.NewStyle().Foreground(createColor).Bold(true).Render(fmt.Sprintf("%d", plan.TotalAdd)),
-			lipgloss.NewStyle().Foreground(updateColor).Bold(true).Render(fmt.Sprintf("%d", plan.TotalChange)),
-			lipgloss.NewStyle().Foreground(destroyColor).Bold(true).Render(fmt.Sprintf("%d", plan.TotalDestroy)),
-		)
-		if plan.OutputCount > 0 {
-			summary += fmt.Sprintf(", %s output(s) changed",
-				lipgloss.NewStyle().Foreground(updateColor).Bold(true).Render(fmt.Sprintf("%d", plan.OutputCount)),
-			)
-		}
-		fmt.Println(summary)
-	} else if plan.OutputCount > 0 {
-		fmt.Printf("%d output(s) changed\n", plan.OutputCount)
-	} else {
-		fmt.Printf("%d resources with changes\n", len(plan.Resources))
-	}
-	fmt.Println()
-
-	// Resources
-	for _, r := range plan.Resources {
-		printResource(r)
-		fmt.Println()
-	}
+// PrintOptions controls print mode (-p) output.
+type PrintOptions struct {
+	Filters   []parser.Action // only print resources with these actions; all when empty
+	Sort      SortOrder       // resource order, SortDefault when empty
+	Context   *int            // heredoc/JSON diff context lines, the TUI default when nil
+	Collapsed bool            // print one summary line per resource, without bodies
+	Width     int             // wrap width, defaultPrintWidth when 0
 }
 
-func printResource(r parser.Resource) {
-	// Action symbol and resource address (header line)
-	symbol := GetActionSymbol(string(r.Action))
-	style := GetResourceStyle(string(r.Action))
-
-	actionDesc := getActionDesc(r.Action)
+const printTitle = "Terra-Prism - Terraform Plan Viewer"
 
-	fmt.Printf("%s %s %s\n",
-		symbol,
-		style.Render(r.Address),
-		mutedColor.Render(actionDesc),
-	)
+// PrintPlan outputs the plan to stdout (non-interactive mode), in color
+// unless UseColorMode turned colors off.
+func PrintPlan(plan *parser.Plan, opts PrintOptions) {
+	fmt.Print(RenderPlan(plan, opts))
+}
 
-	// Print the full HCL block with syntax highlighting
-	if len(r.RawLines) > 1 {
-		for _, line := range r.RawLines[1:] {
-			coloredLine := colorizeLine(line, r.Action)
-			fmt.Println(coloredLine)
+// RenderPlan renders the plan for print mode with the TUI's rendering
+// pipeline: every listed resource is expanded as if in the TUI, with all of
+// its folds open, so userdata decoding, heredoc and JSON diffs, tag and rule
+// tables and suppression rules all apply. Without colors the output is plain
+// text with no escape codes or trailing padding, safe for files, CI logs and
+// Markdown code blocks.
+func RenderPlan(plan *parser.Plan, opts PrintOptions) string {
+	m := NewModel(plan, "")
+	m.width = opts.Width
+	if m.width <= 0 {
+		m.width = defaultPrintWidth
+	}
+	m.viewport.Width = m.width
+	if opts.Context != nil {
+		m.diffContext = clampDiffContext(*opts.Context)
+	}
+	if opts.Sort != "" {
+		m.sortOrder = opts.Sort
+	}
+	if len(opts.Filters) > 0 {
+		m.statusFilters = make(map[parser.Action]bool, len(opts.Filters))
+		for _, action := range opts.Filters {
+			m.statusFilters[action] = true
 		}
 	}
-}
 
-// colorizeLine applies syntax highlighting to a single line of HCL
-func colorizeLine(line string, action parser.Action) string {
-	// Detect the line prefix symbol (+, -, ~)
-	trimmed := strings.TrimLeft(line, " \t")
-	indent := line[:len(line)-len(trimmed)]
-
-	// Check for change symbols at start of content
-	var prefix string
-	var content string
-
-	if strings.HasPrefix(trimmed, "+ ") {
-		prefix = lipgloss.NewStyle().Foreground(createColor).Render("+")
-		content = trimmed[2:]
-	} else if strings.HasPrefix(trimmed, "- ") {
-		prefix = lipgloss.NewStyle().Foreground(destroyColor).Render("-")
-		content = trimmed[2:]
-	} else if strings.HasPrefix(trimmed, "~ ") {
-		prefix = lipgloss.NewStyle().Foreground(updateColor).Render("~")
-		content = trimmed[2:]
+	plain := plainOutput()
+	var b strings.Builder
+	if plain {
+		b.WriteString(printTitle)
 	} else {
-		prefix = " "
-		content = trimmed
-	}
-
-	// Apply syntax highlighting to content
-	coloredContent := colorizeHCL(content, action)
-
-	return indent + prefix + " " + coloredContent
-}
-
-// colorizeHCL applies HCL syntax highlighting
-func colorizeHCL(content string, action parser.Action) string {
-	// Empty or structural lines
-	if content == "" || content == "{" || content == "}" || content == "]" || content == "[" {
-		return mutedColor.Render(content)
-	}
-
-	// Check for key = value pattern
-	kvPattern := regexp.MustCompile(`^([a-zA-Z_][a-zA-Z0-9_]*)\s*=\s*(.*)$`)
-	if match := kvPattern.FindStringSubmatch(content); match != nil {
-		key := match[1]
-		value := match[2]
-		return colorizeKeyValue(key, value, action)
-	}
-
-	// Check for "key" = value pattern (quoted keys)
-	quotedKvPattern := regexp.MustCompile(`^"([^"]+)"\s*=\s*(.*)$`)
-	if match := quotedKvPattern.FindStringSubmatch(content); match != nil {
-		key := match[1]
-		value := match[2]
-		return lipgloss.NewStyle().Foreground(textColor).Render("\""+key+"\"") + " = " + colorizeValue(value, action)
-	}
-
-	// Resource/block declarations
-	if strings.HasPrefix(content, "resource ") || strings.HasPrefix(content, "data ") {
-		return colorizeBlockDeclaration(content)
+		b.WriteString(headerStyle.Render("🔺 " + printTitle))
+	}
+	b.WriteString("\n\n")
+	b.WriteString(printSummary(plan))
+	b.WriteString("\n")
+
+	displayed := m.displayedResourceIndices()
+	if !opts.Collapsed {
+		// Logs have no fold toggles, so print every fold open rather than
+		// hiding large blocks behind the TUI's collapsed default.
+		m.setDisplayedFoldsCollapsed(false)
+	}
+	if len(displayed) < len(plan.Resources) {
+		b.WriteString(mutedColor.Render(fmt.Sprintf("Showing %d of %d resources", len(displayed), len(plan.Resources))))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	for _, idx := range displayed {
+		r := plan.Resources[idx]
+		expanded := !opts.Collapsed && len(r.RawLines) > 1
+		b.WriteString(m.renderResourceLine(r, expanded, false))
+		b.WriteString("\n")
+		if expanded {
+			b.WriteString(m.renderBody(idx, false).text)
+			b.WriteString("\n")
+		}
 	}
-
-	// Nested block headers (e.g., "root_block_device {")
-	blockPattern := regexp.MustCompile(`^([a-zA-Z_][a-zA-Z0-9_]*)\s*\{`)
-	if match := blockPattern.FindStringSubmatch(content); match != nil {
-		blockName := match[1]
-		return lipgloss.NewStyle().Foreground(headerColor).Render(blockName) + " {"
+	if plain {
+		return trimTrailingSpace(b.String())
 	}
-
-	// Default: just return with text color
-	return lipgloss.NewStyle().Foreground(textColor).Render(content)
+	return b.String()
 }
 
-func colorizeKeyValue(key, value string, action parser.Action) string {
-	keyStyle := lipgloss.NewStyle().Foreground(textColor)
-	return keyStyle.Render(key) + " = " + colorizeValue(value, action)
-}
-
-func colorizeValueComputed(value string) string {
-	return lipgloss.NewStyle().Foreground(computedColor).Italic(true).Render(value)
-}
-
-func colorizeValueSensitive(value string) string {
-	return lipgloss.NewStyle().Foreground(replaceColor).Italic(true).Render(value)
-}
-
-func colorizeValueChangeArrow(value string) string {
-	parts := strings.SplitN(value, " -> ", 2)
-	oldVal := strings.TrimSpace(parts[0])
-	newVal := strings.TrimSpace(parts[1])
-	oldStyle := lipgloss.NewStyle().Foreground(destroyColor)
-	newStyle := lipgloss.NewStyle().Foreground(createColor)
-	arrowStyle := lipgloss.NewStyle().Foreground(mutedColorVal)
-	return oldStyle.Render(oldVal) + arrowStyle.Render(" → ") + newStyle.Render(newVal)
-}
-
-func colorizeValueByAction(value string, action parser.Action) string {
-	switch action {
-	case parser.ActionCreate:
-		return lipgloss.NewStyle().Foreground(createColor).Render(value)
-	case parser.ActionDestroy:
-		return lipgloss.NewStyle().Foreground(destroyColor).Render(value)
-	case parser.ActionUpdate:
-		return lipgloss.NewStyle().Foreground(updateColor).Render(value)
-	default:
-		return lipgloss.NewStyle().Foreground(textColor).Render(value)
+// trimTrailingSpace removes the padding styles leave at the end of lines.
+func trimTrailingSpace(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
 	}
+	return strings.Join(lines, "\n")
 }
 
-func colorizeValue(value string, action parser.Action) string {
-	value = strings.TrimSpace(value)
-	if strings.Contains(value, "(known after apply)") {
-		return colorizeValueComputed(value)
-	}
-	if strings.Contains(value, "(sensitive") {
-		return colorizeValueSensitive(value)
-	}
-	if strings.Contains(value, " -> ") {
-		return colorizeValueChangeArrow(value)
-	}
-	if value == "null" {
-		return lipgloss.NewStyle().Foreground(destroyColor).Render(value)
-	}
-	if value == "true" || value == "false" {
-		return lipgloss.NewStyle().Foreground(readColor).Render(value)
+// printSummary renders the plan summary line.
+func printSummary(plan *parser.Plan) string {
+	count := func(color lipgloss.Color, n int) string {
+		return lipgloss.NewStyle().Foreground(color).Bold(true).Render(strconv.Itoa(n))
 	}
-	if regexp.MustCompile(`^-?[0-9]+\.?[0-9]*$`).MatchString(value) {
-		return lipgloss.NewStyle().Foreground(updateColor).Render(value)
-	}
-	if len(value) > 1 && value[0] == '"' {
-		return lipgloss.NewStyle().Foreground(createColor).Render(value)
-	}
-	if value == "{" || value == "[" || strings.HasSuffix(value, "{") || strings.HasSuffix(value, "[") {
-		return lipgloss.NewStyle().Foreground(mutedColorVal).Render(value)
+	switch {
+	case plan.Summary != "":
+		summary := fmt.Sprintf("Plan: %s to add, %s to change, %s to destroy",
+			count(createColor, plan.TotalAdd), count(updateColor, plan.TotalChange), count(destroyColor, plan.TotalDestroy))
+		if plan.OutputCount > 0 {
+			summary += fmt.Sprintf(", %s output(s) changed", count(updateColor, plan.OutputCount))
+		}
+		return summary + "\n"
+	case plan.OutputCount > 0:
+		return fmt.Sprintf("%d output(s) changed\n", plan.OutputCount)
 	}
-	return colorizeValueByAction(value, action)
+	return fmt.Sprintf("%d resources with changes\n", len(plan.Resources))
 }
 
-func colorizeBlockDeclaration(content string) string {
-	// Pattern: resource "type" "name" {
-	pattern := regexp.MustCompile(`^(resource|data)\s+"([^"]+)"\s+"([^"]+)"\s*\{?`)
-	if match := pattern.FindStringSubmatch(content); match != nil {
-		keyword := match[1]
-		resType := match[2]
-		resName := match[3]
-
-		keywordStyle := lipgloss.NewStyle().Foreground(replaceColor).Bold(true)
-		typeStyle := lipgloss.NewStyle().Foreground(headerColor)
-		nameStyle := lipgloss.NewStyle().Foreground(createColor)
-
-		result := keywordStyle.Render(keyword) + " " +
-			typeStyle.Render("\""+resType+"\"") + " " +
-			nameStyle.Render("\""+resName+"\"")
-
-		if strings.HasSuffix(content, "{") {
-			result += " {"
+// ParseActionFilter parses a comma-separated --filter value such as
+// "create,destroy". Names are the actions of the filter picker and the
+// action: query field (delete-create is also accepted as destroy+create).
+func ParseActionFilter(s string) ([]parser.Action, error) {
+	var actions []parser.Action
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		action, ok := printFilterActions[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown action %q (want create, update, replace, destroy, read, delete-create, create-delete or output)", name)
 		}
-		return result
+		actions = append(actions, action)
 	}
+	return actions, nil
+}
 
-	return lipgloss.NewStyle().Foreground(textColor).Render(content)
+var printFilterActions = map[string]parser.Action{
+	"create":         parser.ActionCreate,
+	"update":         parser.ActionUpdate,
+	"replace":        parser.ActionReplace,
+	"destroy":        parser.ActionDestroy,
+	"delete":         parser.ActionDestroy,
+	"read":           parser.ActionRead,
+	"delete-create":  parser.ActionDeleteCreate,
+	"destroy+create": parser.ActionDeleteCreate,
+	"create-delete":  parser.ActionCreateDelete,
+	"create+destroy": parser.ActionCreateDelete,
+	"output":         parser.ActionOutput,
 }
 
-func getActionDesc(action parser.Action) string {
-	switch action {
-	case parser.ActionCreate:
-		return "will be created"
-	case parser.ActionDestroy:
-		return "will be destroyed"
-	case parser.ActionUpdate:
-		return "will be updated"
-	case parser.ActionReplace:
-		return "must be replaced"
-	case parser.ActionRead:
-		return "will be read"
-	case parser.ActionDeleteCreate:
-		return "will be destroyed then created"
-	case parser.ActionCreateDelete:
-		return "will be created then destroyed"
-	case parser.ActionOutput:
-		return "output values will change"
-	default:
-		return ""
+// ParseSortOrder parses a --sort value: default, action, address or type.
+func ParseSortOrder(s string) (SortOrder, error) {
+	for _, opt := range sortOptions {
+		if string(opt) == strings.ToLower(strings.TrimSpace(s)) {
+			return opt, nil
+		}
 	}
+	return "", fmt.Errorf("unknown sort %q (want default, action, address or type)", s)
 }
@@ -0,0 +1,161 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+func TestRenderPlanExpandsResources(t *testing.T) {
+	plan, err := parser.Parse(dashboardTestPlan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := stripRenderANSI(RenderPlan(plan, PrintOptions{}))
+	for _, want := range []string{
+		"Plan: 2 to add, 1 to change, 1 to destroy",
+		"aws_instance.web must be replaced",
+		`cidr_block = "10.0.1.0/24"`,
+		"ttl      = 300 → 60",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("print output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Showing") {
+		t.Error("an unfiltered plan should not report a partial count")
+	}
+}
+
+func TestRenderPlanOptions(t *testing.T) {
+	plan, err := parser.Parse(dashboardTestPlan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := stripRenderANSI(RenderPlan(plan, PrintOptions{
+		Filters:   []parser.Action{parser.ActionCreate, parser.ActionUpdate},
+		Sort:      SortByAddress,
+		Collapsed: true,
+	}))
+	if !strings.Contains(out, "Showing 2 of 3 resources") {
+		t.Errorf("filtered output should report the shown count:\n%s", out)
+	}
+	if strings.Contains(out, "aws_instance.web") {
+		t.Error("the replaced resource should be filtered out")
+	}
+	if strings.Contains(out, "cidr_block") {
+		t.Error("collapsed output should not print resource bodies")
+	}
+	subnet := strings.Index(out, "module.network.aws_subnet.a")
+	dns := strings.Index(out, "module.network.module.dns.google_dns_record_set.www")
+	if subnet < 0 || dns < 0 || subnet > dns {
+		t.Errorf("resources should be sorted by address:\n%s", out)
+	}
+}
+
+func TestRenderPlanWrapsBodiesToWidth(t *testing.T) {
+	plan, err := parser.Parse(`
+  # aws_instance.web will be updated in-place
+  ~ resource "aws_instance" "web" {
+      ~ description = "a very long description that keeps going well past the narrow print width" -> "short"
+    }
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := stripRenderANSI(RenderPlan(plan, PrintOptions{Width: 40}))
+	if !strings.Contains(out, "\"short\"") || !strings.Contains(out, "well past") {
+		t.Fatalf("long value should be printed in full:\n%s", out)
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "  ") && len([]rune(line)) > 40 {
+			t.Errorf("body line wider than 40 columns: %q", line)
+		}
+	}
+}
+
+func TestRenderPlanExpandsLargeFolds(t *testing.T) {
+	var tags strings.Builder
+	for i := 0; i < 35; i++ {
+		fmt.Fprintf(&tags, "          ~ \"key%02d\" = \"a\" -> \"b\"\n", i)
+	}
+	plan, err := parser.Parse(`
+  # aws_instance.web will be updated in-place
+  ~ resource "aws_instance" "web" {
+      ~ tags = {
+` + tags.String() + `        }
+    }
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out := stripRenderANSI(RenderPlan(plan, PrintOptions{})); !strings.Contains(out, "key00") || !strings.Contains(out, "key34") {
+		t.Errorf("folds of %d+ lines should print in full:\n%s", defaultCollapsedFoldLines, out)
+	}
+	if out := stripRenderANSI(RenderPlan(plan, PrintOptions{Collapsed: true})); strings.Contains(out, "key00") {
+		t.Errorf("collapsed output should not print resource bodies:\n%s", out)
+	}
+}
+
+func TestRenderPlanZeroContext(t *testing.T) {
+	plan, err := parser.Parse(`
+  # helm_release.chart will be updated in-place
+  ~ resource "helm_release" "chart" {
+      ~ values = [
+          - <<-EOT
+              before-a: true
+              before-b: true
+              before-c: true
+              target: old
+            EOT,
+          + <<-EOT
+              before-a: true
+              before-b: true
+              before-c: true
+              target: new
+            EOT,
+        ]
+    }
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zero := 0
+	out := stripRenderANSI(RenderPlan(plan, PrintOptions{Context: &zero}))
+	if strings.Contains(out, "before-a") || !strings.Contains(out, "target") {
+		t.Errorf("--context 0 should keep only the changed lines:\n%s", out)
+	}
+	if out := stripRenderANSI(RenderPlan(plan, PrintOptions{})); !strings.Contains(out, "before-a") {
+		t.Errorf("the default context should keep nearby lines:\n%s", out)
+	}
+}
+
+func TestParseActionFilter(t *testing.T) {
+	actions, err := ParseActionFilter("create, destroy+create,delete")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []parser.Action{parser.ActionCreate, parser.ActionDeleteCreate, parser.ActionDestroy}
+	if len(actions) != len(want) {
+		t.Fatalf("actions = %v, want %v", actions, want)
+	}
+	for i := range want {
+		if actions[i] != want[i] {
+			t.Errorf("actions[%d] = %s, want %s", i, actions[i], want[i])
+		}
+	}
+	if _, err := ParseActionFilter("create,bogus"); err == nil {
+		t.Error("expected an error for an unknown action")
+	}
+}
+
+func TestParseSortOrder(t *testing.T) {
+	if order, err := ParseSortOrder("Address"); err != nil || order != SortByAddress {
+		t.Errorf("ParseSortOrder(Address) = %q, %v", order, err)
+	}
+	if _, err := ParseSortOrder("size"); err == nil {
+		t.Error("expected an error for an unknown sort order")
+	}
+}
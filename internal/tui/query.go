@@ -0,0 +1,527 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+// queryFields are the field:value terms a query accepts.
+var queryFields = map[string]bool{
+	"action":   true,
+	"type":     true,
+	"name":     true,
+	"address":  true,
+	"addr":     true,
+	"module":   true,
+	"attr":     true,
+	"provider": true,
+}
+
+// queryFlags are the bare-word predicates a query accepts.
+var queryFlags = map[string]bool{
+	"reviewed":           true,
+	"unknown":            true, // has (known after apply) values
+	"sensitive":          true, // changes sensitive values
+	"forces-replacement": true, // has an attribute marked # forces replacement
+}
+
+// queryTerm is one whitespace-separated part of a query, e.g. `!type:aws_iam_*`.
+// Values are comma-separated alternatives; each is a glob, or a regular
+// expression when written as /.../.
+type queryTerm struct {
+	Negate bool
+	Field  string // "" for a bare word matched against the address
+	Values []string
+	Regexp []*regexp.Regexp
+}
+
+// planQuery is a parsed query expression. All terms must match.
+type planQuery struct {
+	Expr  string
+	Terms []queryTerm
+}
+
+// parsePlanQuery parses a query such as
+// `action:destroy type:aws_iam_* module:network attr:instance_type !reviewed`.
+func parsePlanQuery(expr string) (*planQuery, error) {
+	q := &planQuery{Expr: strings.TrimSpace(expr)}
+	for _, word := range strings.Fields(expr) {
+		term := queryTerm{}
+		if rest, ok := strings.CutPrefix(word, "!"); ok {
+			term.Negate = true
+			word = rest
+		}
+		field, value, hasField := strings.Cut(word, ":")
+		switch {
+		case hasField && queryFields[field]:
+			term.Field = field
+		case hasField && !strings.HasPrefix(word, "/"):
+			return nil, fmt.Errorf("unknown field %q (want action, type, provider, name, address, module or attr)", field)
+		case queryFlags[word]:
+			term.Field = word
+			q.Terms = append(q.Terms, term)
+			continue
+		default:
+			value = word
+		}
+		if value == "" {
+			return nil, fmt.Errorf("%q needs a value", word)
+		}
+		for _, alt := range splitQueryValues(value) {
+			if len(alt) >= 2 && strings.HasPrefix(alt, "/") && strings.HasSuffix(alt, "/") {
+				re, err := regexp.Compile(alt[1 : len(alt)-1])
+				if err != nil {
+					return nil, fmt.Errorf("invalid regex %s: %w", alt, err)
+				}
+				term.Regexp = append(term.Regexp, re)
+				continue
+			}
+			if _, err := path.Match(alt, ""); err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", alt, err)
+			}
+			term.Values = append(term.Values, strings.ToLower(alt))
+		}
+		q.Terms = append(q.Terms, term)
+	}
+	return q, nil
+}
+
+// splitQueryValues splits comma-separated alternatives, leaving commas
+// inside /regex/ values alone.
+func splitQueryValues(value string) []string {
+	if strings.HasPrefix(value, "/") && strings.HasSuffix(value, "/") {
+		return []string{value}
+	}
+	return strings.Split(value, ",")
+}
+
+// Matches reports whether r satisfies every term of the query.
+func (q *planQuery) Matches(r parser.Resource, reviewed bool) bool {
+	for _, term := range q.Terms {
+		if term.matches(r, reviewed) == term.Negate {
+			return false
+		}
+	}
+	return true
+}
+
+func (t queryTerm) matches(r parser.Resource, reviewed bool) bool {
+	switch t.Field {
+	case "reviewed":
+		return reviewed
+	case "unknown":
+		return countComputedValues(r) > 0
+	case "sensitive":
+		return countSensitiveValues(r) > 0
+	case "forces-replacement":
+		return countForcedReplacements(r) > 0
+	case "action":
+		return t.matchAny(queryActionNames(r.Action)...)
+	case "type":
+		return t.matchAny(r.Type)
+	case "provider":
+		return t.matchAny(resourceProvider(r))
+	case "name":
+		return t.matchAny(r.Name)
+	case "address", "addr":
+		return t.matchAny(r.Address)
+	case "module":
+		modules := addressModules(r.Address)
+		if len(modules) == 0 {
+			return false
+		}
+		return t.matchAny(append(modules, strings.Join(modules, "."))...)
+	case "attr":
+		for _, attr := range r.Attributes {
+			attrPath := attr.Path
+			if attrPath == "" {
+				attrPath = attr.Name
+			}
+			if attrPath != "" && t.matchAttrPath(attrPath) {
+				return true
+			}
+		}
+		return false
+	}
+	// Bare words match anywhere in the address, like the / search.
+	address := strings.ToLower(r.Address)
+	for _, v := range t.Values {
+		if strings.Contains(address, v) || matchGlob(v, address) {
+			return true
+		}
+	}
+	return t.matchRegexp(r.Address)
+}
+
+func (t queryTerm) matchAny(candidates ...string) bool {
+	for _, c := range candidates {
+		lower := strings.ToLower(c)
+		for _, v := range t.Values {
+			if matchGlob(v, lower) {
+				return true
+			}
+		}
+		if t.matchRegexp(c) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t queryTerm) matchRegexp(s string) bool {
+	for _, re := range t.Regexp {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAttrPath matches globs segment by segment, so attr:tags also matches
+// tags.Env, and regexes against the whole dotted path.
+func (t queryTerm) matchAttrPath(attrPath string) bool {
+	segments := strings.Split(strings.ToLower(attrPath), ".")
+	for _, v := range t.Values {
+		if matchAttrPathSegments(strings.Split(v, "."), segments) {
+			return true
+		}
+	}
+	return t.matchRegexp(attrPath)
+}
+
+// matchGlob matches s against a query glob. Values equal to s match before
+// glob syntax applies, since brackets in count and for_each addresses such as
+// aws_instance.web[0] would otherwise start a character class.
+func matchGlob(pattern, s string) bool {
+	if pattern == s {
+		return true
+	}
+	ok, _ := path.Match(pattern, s)
+	return ok
+}
+
+// queryActionNames returns the names action:... matches for an action;
+// every kind of replacement also answers to "replace".
+func queryActionNames(action parser.Action) []string {
+	switch action {
+	case parser.ActionDeleteCreate, parser.ActionCreateDelete:
+		return []string{string(action), string(parser.ActionReplace)}
+	case parser.ActionDestroy:
+		return []string{string(action), "delete"}
+	}
+	return []string{string(action)}
+}
+
+// addressModules returns the module names in a resource address, e.g.
+// ["network", "subnets"] for module.network.module.subnets["a"].aws_subnet.x.
+func addressModules(address string) []string {
+	var modules []string
+	rest := address
+	for {
+		after, ok := strings.CutPrefix(rest, "module.")
+		if !ok {
+			return modules
+		}
+		end := strings.IndexAny(after, ".[")
+		if end < 0 {
+			return modules
+		}
+		modules = append(modules, after[:end])
+		rest = after[end:]
+		if strings.HasPrefix(rest, "[") {
+			closing := strings.Index(rest, "]")
+			if closing < 0 {
+				return modules
+			}
+			rest = rest[closing+1:]
+		}
+		rest = strings.TrimPrefix(rest, ".")
+	}
+}
+
+// FilterPlan returns a copy of plan holding only the resources matching the
+// query expression, with the Plan: totals recounted from them. Expressions of
+// the form @name refer to saved queries.
+func FilterPlan(plan *parser.Plan, expr string) (*parser.Plan, error) {
+	expr, err := resolveSavedQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+	q, err := parsePlanQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+	filtered := *plan
+	filtered.Resources = nil
+	for _, r := range plan.Resources {
+		if q.Matches(r, false) {
+			filtered.Resources = append(filtered.Resources, r)
+		}
+	}
+	recountPlanTotals(&filtered, plan.OutputCount)
+	return &filtered, nil
+}
+
+// recountPlanTotals sets the add/change/destroy totals and the Plan: line
+// from the plan's resources, counting replacements as an add and a destroy
+// like Terraform does. outputs is kept as the output count when the outputs
+// pseudo-resource is still present.
+func recountPlanTotals(plan *parser.Plan, outputs int) {
+	plan.TotalAdd, plan.TotalChange, plan.TotalDestroy, plan.OutputCount = 0, 0, 0, 0
+	for _, r := range plan.Resources {
+		switch r.Action {
+		case parser.ActionCreate:
+			plan.TotalAdd++
+		case parser.ActionUpdate:
+			plan.TotalChange++
+		case parser.ActionDestroy:
+			plan.TotalDestroy++
+		case parser.ActionReplace, parser.ActionDeleteCreate, parser.ActionCreateDelete:
+			plan.TotalAdd++
+			plan.TotalDestroy++
+		case parser.ActionOutput:
+			plan.OutputCount = outputs
+		}
+	}
+	if plan.Summary != "" {
+		plan.Summary = fmt.Sprintf("Plan: %d to add, %d to change, %d to destroy.", plan.TotalAdd, plan.TotalChange, plan.TotalDestroy)
+	}
+}
+
+// SavedQuery is a named query from the saved queries file.
+type SavedQuery struct {
+	Name  string `yaml:"name"`
+	Query string `yaml:"query"`
+}
+
+var savedQueries []SavedQuery
+
+// SetSavedQueries installs the named queries offered by the Q picker.
+func SetSavedQueries(queries []SavedQuery) {
+	savedQueries = queries
+}
+
+// DefaultSavedQueriesPath returns ~/.terraprism/queries.yaml.
+func DefaultSavedQueriesPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".terraprism", "queries.yaml"), nil
+}
+
+// LoadSavedQueries reads and validates a saved queries file:
+//
+//	queries:
+//	  - name: iam-destroys
+//	    query: action:destroy type:aws_iam_*
+//
+// The error wraps fs.ErrNotExist when the file is missing.
+func LoadSavedQueries(file string) ([]SavedQuery, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var cfg struct {
+		Queries []SavedQuery `yaml:"queries"`
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	for _, sq := range cfg.Queries {
+		if sq.Name == "" {
+			return nil, fmt.Errorf("%s: query %q has no name", file, sq.Query)
+		}
+		if _, err := parsePlanQuery(sq.Query); err != nil {
+			return nil, fmt.Errorf("%s: query %s: %w", file, sq.Name, err)
+		}
+	}
+	return cfg.Queries, nil
+}
+
+func resolveSavedQuery(expr string) (string, error) {
+	name, ok := strings.CutPrefix(strings.TrimSpace(expr), "@")
+	if !ok {
+		return expr, nil
+	}
+	for _, sq := range savedQueries {
+		if sq.Name == name {
+			return sq.Query, nil
+		}
+	}
+	return "", fmt.Errorf("no saved query named %q", name)
+}
+
+// setQuery applies a query expression; an empty expression clears it.
+func (m *Model) setQuery(expr string) error {
+	expr, err := resolveSavedQuery(expr)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(expr) == "" {
+		m.query = nil
+	} else {
+		q, err := parsePlanQuery(expr)
+		if err != nil {
+			return err
+		}
+		m.query = q
+	}
+	m.clampCursorAndRefreshSearch()
+	m.updateViewportContent()
+	return nil
+}
+
+// WithQuery returns m with a query expression applied, as if entered in
+// the query bar.
+func (m Model) WithQuery(expr string) (Model, error) {
+	err := m.setQuery(expr)
+	return m, err
+}
+
+func newQueryInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "action:destroy type:aws_iam_* module:network attr:instance_type !reviewed"
+	ti.CharLimit = 200
+	ti.Width = 60
+	return ti
+}
+
+func handleKeyQuery(m Model) (Model, tea.Cmd, bool) {
+	m.querying = true
+	m.queryErr = ""
+	m.queryInput.SetValue("")
+	if m.query != nil {
+		m.queryInput.SetValue(m.query.Expr)
+	}
+	m.queryInput.CursorEnd()
+	m.queryInput.Focus()
+	return m, textinput.Blink, true
+}
+
+func (m Model) handleQueryKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		if err := m.setQuery(m.queryInput.Value()); err != nil {
+			m.queryErr = err.Error()
+			return m, nil
+		}
+		m.querying = false
+		m.queryErr = ""
+		m.queryInput.Blur()
+		return m, nil
+	case "esc":
+		m.querying = false
+		m.queryErr = ""
+		m.queryInput.Blur()
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.queryInput, cmd = m.queryInput.Update(msg)
+	return m, cmd
+}
+
+func handleKeySavedQueries(m Model) (Model, tea.Cmd, bool) {
+	m.pickingQuery = true
+	m.queryCursor = 0
+	m.queryErr = ""
+	return m, nil, true
+}
+
+func (m Model) handleSavedQueryKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "Q":
+		m.pickingQuery = false
+	case "enter", " ":
+		if m.queryCursor < len(savedQueries) {
+			if err := m.setQuery(savedQueries[m.queryCursor].Query); err != nil {
+				m.queryErr = err.Error()
+				return m, nil
+			}
+		}
+		m.pickingQuery = false
+	case "up", "k":
+		if m.queryCursor > 0 {
+			m.queryCursor--
+		}
+	case "down", "j":
+		if m.queryCursor < len(savedQueries)-1 {
+			m.queryCursor++
+		}
+	}
+	return m, nil
+}
+
+// reviewedMark follows the address of resources marked reviewed.
+const reviewedMark = "✓"
+
+func handleKeyToggleReviewed(m Model) (Model, tea.Cmd, bool) {
+	resourceIdx := m.currentResourceIndex()
+	if resourceIdx < 0 {
+		return m, nil, true
+	}
+	address := m.plan.Resources[resourceIdx].Address
+	m.reviewed[address] = !m.reviewed[address]
+	m.clampCursorAndRefreshSearch()
+	m.updateViewportContent()
+	return m, nil, true
+}
+
+// viewQueryBar renders the query input, or the active query status line.
+func (m Model) viewQueryBar() string {
+	if m.querying {
+		bar := searchStyle.Render("Query: ") + m.queryInput.View() + "\n"
+		if m.queryErr != "" {
+			bar += lipgloss.NewStyle().Foreground(destroyColor).Render("  "+m.queryErr) + "\n"
+		}
+		return bar + "\n"
+	}
+	if m.query == nil {
+		return ""
+	}
+	return searchStyle.Render(fmt.Sprintf("Query: %s (%d resources) • :: edit • Esc: clear", m.query.Expr, len(m.filteredResources()))) + "\n\n"
+}
+
+// viewSavedQueries renders the saved query picker.
+func (m Model) viewSavedQueries() string {
+	var b strings.Builder
+	b.WriteString(searchStyle.Render("Saved queries (Enter/Space: apply, Esc: close)"))
+	b.WriteString("\n\n")
+	if len(savedQueries) == 0 {
+		b.WriteString(mutedColor.Render("No saved queries. Add them to ~/.terraprism/queries.yaml:"))
+		b.WriteString("\n\n")
+		b.WriteString(mutedColor.Render("  queries:\n    - name: iam-destroys\n      query: action:destroy type:aws_iam_*"))
+		b.WriteString("\n")
+	}
+	for i, sq := range savedQueries {
+		marker := "  "
+		if m.query != nil && m.query.Expr == strings.TrimSpace(sq.Query) {
+			marker = "● "
+		}
+		rowStyle := lipgloss.NewStyle().Foreground(textColor)
+		if i == m.queryCursor {
+			rowStyle = rowStyle.Background(selectedBg)
+		}
+		b.WriteString(rowStyle.Render(marker + sq.Name + " " + mutedColor.Render(sq.Query)))
+		b.WriteString("\n")
+	}
+	if m.queryErr != "" {
+		b.WriteString("\n")
+		b.WriteString(lipgloss.NewStyle().Foreground(destroyColor).Render(m.queryErr))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("j/k: navigate • Enter/Space: apply • Esc: close"))
+	return appStyle.Render(b.String())
+}
@@ -0,0 +1,192 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+func queryTestPlan() *parser.Plan {
+	return &parser.Plan{Resources: []parser.Resource{
+		{Address: "aws_iam_role.admin", Type: "aws_iam_role", Name: "admin", Action: parser.ActionDestroy},
+		{Address: "module.network.aws_subnet.a", Type: "aws_subnet", Name: "a", Action: parser.ActionCreate},
+		{Address: `module.apps["web"].module.network.aws_instance.web`, Type: "aws_instance", Name: "web", Action: parser.ActionUpdate,
+			Attributes: []parser.Attribute{{Name: "instance_type", Path: "instance_type", OldValue: `"t3.micro"`, NewValue: `"t3.large"`, Action: parser.ActionUpdate}}},
+		{Address: "aws_db_instance.main", Type: "aws_db_instance", Name: "main", Action: parser.ActionDeleteCreate,
+			Attributes: []parser.Attribute{{Name: "Env", Path: "tags.Env", OldValue: `"a"`, NewValue: `"b"`, Action: parser.ActionUpdate}}},
+	}}
+}
+
+func queryAddresses(t *testing.T, expr string, reviewed map[string]bool) string {
+	t.Helper()
+	q, err := parsePlanQuery(expr)
+	if err != nil {
+		t.Fatalf("parsePlanQuery(%q): %v", expr, err)
+	}
+	var got []string
+	for _, r := range queryTestPlan().Resources {
+		if q.Matches(r, reviewed[r.Address]) {
+			got = append(got, r.Name)
+		}
+	}
+	return strings.Join(got, ",")
+}
+
+func TestPlanQueryMatches(t *testing.T) {
+	reviewed := map[string]bool{"aws_iam_role.admin": true}
+	tests := []struct {
+		expr, want string
+	}{
+		{"action:destroy", "admin"},
+		{"action:replace", "main"},
+		{"action:create,destroy", "admin,a"},
+		{"type:aws_iam_*", "admin"},
+		{"!type:aws_iam_*", "a,web,main"},
+		{"module:network", "a,web"},
+		{"module:apps.network", "web"},
+		{"!module:*", "admin,main"},
+		{"attr:instance_type", "web"},
+		{"attr:tags", "main"},
+		{"attr:/^tags\\.E/", "main"},
+		{"type:/^aws_(iam|db)_/", "admin,main"},
+		{"name:/^[aw]/ !reviewed", "a,web"},
+		{"reviewed", "admin"},
+		{"subnet", "a"},
+		{"action:update module:network attr:instance_type", "web"},
+	}
+	for _, tt := range tests {
+		if got := queryAddresses(t, tt.expr, reviewed); got != tt.want {
+			t.Errorf("%q matched %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParsePlanQueryErrors(t *testing.T) {
+	for _, expr := range []string{"colour:red", "type:", "type:/[/", "name:["} {
+		if _, err := parsePlanQuery(expr); err == nil {
+			t.Errorf("parsePlanQuery(%q) should fail", expr)
+		}
+	}
+}
+
+func TestFilterPlanWithSavedQuery(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "queries.yaml")
+	os.WriteFile(file, []byte("queries:\n  - name: iam\n    query: type:aws_iam_*\n"), 0644)
+	queries, err := LoadSavedQueries(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := savedQueries
+	SetSavedQueries(queries)
+	t.Cleanup(func() { SetSavedQueries(orig) })
+
+	filtered, err := FilterPlan(queryTestPlan(), "@iam")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filtered.Resources) != 1 || filtered.Resources[0].Name != "admin" {
+		t.Errorf("unexpected resources %+v", filtered.Resources)
+	}
+	if _, err := FilterPlan(queryTestPlan(), "@missing"); err == nil {
+		t.Error("unknown saved query should fail")
+	}
+
+	os.WriteFile(file, []byte("queries:\n  - name: bad\n    query: colour:red\n"), 0644)
+	if _, err := LoadSavedQueries(file); err == nil {
+		t.Error("invalid saved query should fail to load")
+	}
+}
+
+func TestFilterPlanRecountsJSONSummary(t *testing.T) {
+	plan := queryTestPlan()
+	plan.Summary = "Plan: 1 to add, 1 to change, 2 to destroy."
+	plan.TotalAdd, plan.TotalChange, plan.TotalDestroy = 1, 1, 2
+	filtered, err := FilterPlan(plan, "action:destroy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary := filtered.ToJSON().Summary
+	want := parser.JSONSummary{Text: "Plan: 0 to add, 0 to change, 1 to destroy.", Destroy: 1, Resources: 1}
+	if summary != want {
+		t.Errorf("summary = %+v, want %+v", summary, want)
+	}
+}
+
+func TestQueryCombinesWithStatusFilters(t *testing.T) {
+	m := NewModel(queryTestPlan(), "test")
+	m, err := m.WithQuery("module:network")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.filteredResources(); len(got) != 2 {
+		t.Fatalf("expected 2 resources in module network, got %v", got)
+	}
+	m.statusFilters = map[parser.Action]bool{parser.ActionUpdate: true}
+	if got := m.filteredResources(); len(got) != 1 || got[0] != 2 {
+		t.Fatalf("expected only the update, got %v", got)
+	}
+	if status := stripRenderANSI(m.viewQueryBar()); !strings.Contains(status, "Query: module:network (1 resources)") {
+		t.Errorf("unexpected status %q", status)
+	}
+}
+
+func TestQueryBarKeys(t *testing.T) {
+	m := NewModel(queryTestPlan(), "test")
+	m, _, _ = handleKeyQuery(m)
+	m.queryInput.SetValue("colour:red")
+	updated, _ := m.handleQueryKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if !m.querying || !strings.Contains(m.queryErr, "unknown field") {
+		t.Fatalf("invalid query should keep the bar open with an error, got querying=%v err=%q", m.querying, m.queryErr)
+	}
+
+	m.queryInput.SetValue("!reviewed")
+	updated, _ = m.handleQueryKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.querying || m.query == nil || len(m.filteredResources()) != 4 {
+		t.Fatalf("valid query should apply, got querying=%v query=%v", m.querying, m.query)
+	}
+
+	m, _, _ = handleKeyToggleReviewed(m)
+	if got := m.filteredResources(); len(got) != 3 || got[0] != 1 {
+		t.Fatalf("reviewed resource should drop out of !reviewed, got %v", got)
+	}
+
+	m, _, _ = handleKeyEsc(m)
+	if m.query != nil || len(m.filteredResources()) != 4 {
+		t.Error("Esc should clear the query")
+	}
+}
+
+func TestQueryMatchesIndexedAddresses(t *testing.T) {
+	plan := &parser.Plan{Resources: []parser.Resource{
+		{Address: "aws_instance.web[0]", Type: "aws_instance", Name: "web", Action: parser.ActionCreate},
+		{Address: "aws_instance.web[1]", Type: "aws_instance", Name: "web", Action: parser.ActionCreate},
+		{Address: `aws_instance.web["a"]`, Type: "aws_instance", Name: "web", Action: parser.ActionCreate},
+	}}
+	for expr, want := range map[string]string{
+		"address:aws_instance.web[0]":        "aws_instance.web[0]",
+		`address:aws_instance.web["a"]`:      `aws_instance.web["a"]`,
+		"address:aws_instance.web[1],web[0]": "aws_instance.web[1]",
+		"address:aws_instance.web*":          `aws_instance.web[0],aws_instance.web[1],aws_instance.web["a"]`,
+	} {
+		q, err := parsePlanQuery(expr)
+		if err != nil {
+			t.Fatalf("parsePlanQuery(%q): %v", expr, err)
+		}
+		var got []string
+		for _, r := range plan.Resources {
+			if q.Matches(r, false) {
+				got = append(got, r.Address)
+			}
+		}
+		if strings.Join(got, ",") != want {
+			t.Errorf("%q matched %q, want %q", expr, strings.Join(got, ","), want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package tui
+
+import (
+	"strings"
+)
+
+// bodyRenderKey captures everything a resource's expanded body depends on.
+// A cached body is reused until one of these changes.
+type bodyRenderKey struct {
+	width          int  // viewport (or detail pane) width the body wraps to
+	screenWidth    int  // terminal width, used to pad the selected fold header
+	blockCursor    int  // selected fold, -1 when the resource has no fold selection
+	diffContext    int  // heredoc/JSON diff context lines
+	sideBySide     bool // rendered as before | after columns
+	showSuppressed bool // z: suppression rules ignored
+	foldVersion    int  // bumped whenever one of the resource's folds is toggled
+	styleVersion   int  // bumped when the palette changes
+}
+
+// renderedBody is a cached rendering of one resource's expanded body.
+type renderedBody struct {
+	key          bodyRenderKey
+	text         string // rendered lines, each terminated by a newline
+	lines        int
+	selectedLine int        // line of the selected fold header within text, -1 if none
+	folds        []foldSpan // where each fold was drawn within text
+}
+
+// foldSpan is the range of rendered lines a fold occupies: its header at
+// start and, when open, its body up to end.
+type foldSpan struct {
+	block      foldBlock
+	start, end int
+}
+
+// renderCache holds rendered bodies by resource index. It is shared by the
+// copies of a Model, like the expanded and foldedBlocks maps.
+type renderCache struct {
+	bodies       map[int]*renderedBody
+	openBodies   map[int]*renderedBody // bodies with every fold open, for content search
+	foldVersions map[int]int
+}
+
+func newRenderCache() *renderCache {
+	return &renderCache{
+		bodies:       make(map[int]*renderedBody),
+		openBodies:   make(map[int]*renderedBody),
+		foldVersions: make(map[int]int),
+	}
+}
+
+// cache returns the model's render cache, creating it for models built
+// without a constructor.
+func (m *Model) cache() *renderCache {
+	if m.renders == nil {
+		m.renders = newRenderCache()
+	}
+	return m.renders
+}
+
+// setFold records a fold's collapsed state and invalidates the cached body of
+// the resource it belongs to.
+func (m *Model) setFold(resourceIdx int, block foldBlock, collapsed bool) {
+	m.foldedBlocks[block.Key] = collapsed
+	m.cache().foldVersions[resourceIdx]++
+}
+
+// renderBody returns the expanded body of a resource, rendering it only when
+// the cached copy is missing or stale.
+func (m *Model) renderBody(resourceIdx int, selected bool) *renderedBody {
+	key := bodyRenderKey{
+		width:          m.viewport.Width,
+		screenWidth:    m.width,
+		blockCursor:    -1,
+		diffContext:    m.diffContextSize(),
+		sideBySide:     m.sideBySide[resourceIdx],
+		showSuppressed: m.showSuppressed,
+		foldVersion:    m.cache().foldVersions[resourceIdx],
+		styleVersion:   styleVersion,
+	}
+	if selected {
+		key.blockCursor = m.blockCursor
+	}
+	if body, ok := m.cache().bodies[resourceIdx]; ok && body.key == key {
+		return body
+	}
+
+	r := m.plan.Resources[resourceIdx]
+	var b strings.Builder
+	lineCount := 0
+	selectedLineStart := m.selectedLineStart
+	m.selectedLineStart = -1
+	m.renderedFolds = nil
+	if key.sideBySide && supportsSideBySide(r.Action) {
+		m.renderSideBySideContent(&b, r, selected, &lineCount)
+	} else {
+		m.renderExpandedContent(&b, r, selected, &lineCount)
+	}
+	body := &renderedBody{
+		key:          key,
+		text:         b.String(),
+		lines:        strings.Count(b.String(), "\n"),
+		selectedLine: m.selectedLineStart,
+		folds:        m.renderedFolds,
+	}
+	m.selectedLineStart = selectedLineStart
+	m.renderedFolds = nil
+	m.cache().bodies[resourceIdx] = body
+	return body
+}
+
+// renderWindow returns the range of content lines renderResources draws:
+// the visible viewport plus a page of margin on either side, so short scrolls
+// do not need a re-render.
+func (m Model) renderWindow() (from, to int) {
+	page := max(1, m.viewport.Height)
+	return max(0, m.viewport.YOffset-page), m.viewport.YOffset + 2*page
+}
+
+// syncRenderWindow re-renders the resource list when the viewport has
+// scrolled past the lines the last renderResources call drew.
+func (m *Model) syncRenderWindow() {
+	if !m.ready || m.split {
+		return
+	}
+	top := m.viewport.YOffset
+	if top < m.windowStart || top+m.viewport.Height > m.windowEnd {
+		m.updateViewportContent()
+	}
+}
@@ -0,0 +1,136 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+func largeTestPlan(n int) *parser.Plan {
+	plan := &parser.Plan{}
+	for i := range n {
+		name := fmt.Sprintf("r%04d", i)
+		plan.Resources = append(plan.Resources, parser.Resource{
+			Address: "aws_instance." + name, Type: "aws_instance", Name: name, Action: parser.ActionUpdate,
+			RawLines: []string{
+				fmt.Sprintf(`  ~ resource "aws_instance" "%s" {`, name),
+				fmt.Sprintf(`      ~ instance_type = "t3.micro" -> "t3.large-%s"`, name),
+				`      ~ root_block_device {`,
+				`          ~ volume_size = 10 -> 20`,
+				`        }`,
+				`    }`,
+			},
+		})
+	}
+	return plan
+}
+
+func TestRenderResourcesOnlyDrawsVisibleWindow(t *testing.T) {
+	m := NewModel(largeTestPlan(2000), "test")
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	m = updated.(Model)
+	m, _, _ = handleKeyExpandAll(m)
+
+	content := m.renderResources()
+	if strings.Contains(content, "r1999") {
+		t.Error("resources far below the viewport should not be drawn")
+	}
+	lines := strings.Split(content, "\n")
+	if got := m.resourceLineStarts[1999]; !strings.Contains(lines[0], "r0000") || got != 1999*7 {
+		t.Fatalf("line offsets should account for undrawn resources, got start %d", got)
+	}
+	if end := lines[m.contentLineCount+1]; !strings.Contains(end, "End of Plan") {
+		t.Errorf("undrawn resources should keep their height, line %d is %q", m.contentLineCount+1, end)
+	}
+
+	m = pressKeys(m, "G")
+	view := stripRenderANSI(m.View())
+	if !strings.Contains(view, "aws_instance.r1999") || !strings.Contains(view, "t3.large-r1998") {
+		t.Errorf("G should draw the last resource:\n%s", view)
+	}
+	if strings.Contains(view, "r0000") {
+		t.Error("the first resource should no longer be drawn")
+	}
+}
+
+func TestRenderCacheInvalidation(t *testing.T) {
+	m := NewModel(largeTestPlan(3), "test")
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	m = updated.(Model)
+	m, _, _ = handleKeyExpandAll(m)
+	first, second := m.renders.bodies[0], m.renders.bodies[1]
+
+	// Moving onto a fold re-renders only the resource showing the selection.
+	m = pressKeys(m, "j")
+	if m.blockCursor != 0 || m.renders.bodies[0] == first || m.renders.bodies[1] != second {
+		t.Error("selecting a fold should only re-render the selected resource")
+	}
+
+	m = pressKeys(m, "enter")
+	if m.renders.bodies[1] != second {
+		t.Error("toggling a fold should only invalidate its resource")
+	}
+	if strings.Contains(m.renders.bodies[0].text, "volume_size") {
+		t.Error("collapsed fold should be hidden in the re-rendered body")
+	}
+
+	updated, _ = m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	m = updated.(Model)
+	if m.renders.bodies[1] == second || m.renders.bodies[1].key.width != 96 {
+		t.Error("a width change should re-render cached bodies")
+	}
+
+	second = m.renders.bodies[1]
+	SetDarkPalette()
+	m.updateViewportContent()
+	if m.renders.bodies[1] == second {
+		t.Error("a palette change should re-render cached bodies")
+	}
+}
+
+func TestContentSearchCountsUndrawnResources(t *testing.T) {
+	m := NewModel(largeTestPlan(500), "test")
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	m = updated.(Model)
+
+	m, _, _ = handleKeyContentSearch(m)
+	m.searching = false
+	m.searchQuery = "volume_size"
+	m.performSearch()
+	m.updateViewportContent()
+	if len(m.contentMatchLines) != 500 {
+		t.Fatalf("expected an occurrence per resource, got %d", len(m.contentMatchLines))
+	}
+	if end := strings.Split(m.renderResources(), "\n")[m.contentLineCount+1]; !strings.Contains(end, "End of Plan") {
+		t.Errorf("undrawn matches should keep their height, got %q", end)
+	}
+
+	m.prevMatch()
+	view := stripRenderANSI(m.View())
+	if !strings.Contains(view, "aws_instance.r0499") {
+		t.Errorf("N should wrap to the last occurrence and draw it:\n%s", view)
+	}
+}
+
+func TestUpdateSyncsRenderWindowAfterScrolling(t *testing.T) {
+	m := NewModel(largeTestPlan(500), "test")
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	m = updated.(Model)
+
+	wheel := tea.MouseMsg{Button: tea.MouseButtonWheelDown, Action: tea.MouseActionPress}
+	for range 40 {
+		updated, _ = m.Update(wheel)
+		m = updated.(Model)
+		top := m.viewport.YOffset
+		if top < m.windowStart || top+m.viewport.Height > m.windowEnd {
+			t.Fatalf("viewport %d-%d outside the rendered window %d-%d", top, top+m.viewport.Height, m.windowStart, m.windowEnd)
+		}
+	}
+	if m.viewport.YOffset == 0 {
+		t.Fatal("the mouse wheel should scroll the list")
+	}
+}
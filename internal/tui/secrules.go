@@ -0,0 +1,469 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+// secRuleExposedMarker flags rules that newly open ingress to the internet.
+const secRuleExposedMarker = "⚠ internet-exposed"
+
+// securityRuleTypes are the resource types whose rules are normalised into a
+// rule table. The value names the attribute or block holding inline rules.
+var securityRuleTypes = map[string][]string{
+	"aws_security_group":             {"ingress", "egress"},
+	"aws_security_group_rule":        nil,
+	"google_compute_firewall":        nil,
+	"azurerm_network_security_rule":  nil,
+	"azurerm_network_security_group": {"security_rule"},
+}
+
+// internetSources are rule sources that match any address.
+var internetSources = map[string]bool{
+	"0.0.0.0/0": true,
+	"::/0":      true,
+	"*":         true,
+	"internet":  true,
+	"any":       true,
+}
+
+// secRule is a firewall rule normalised to a single source, so a CIDR added
+// to an existing rule shows up as one opened rule.
+type secRule struct {
+	Direction string // "ingress" or "egress"
+	Access    string // "allow" or "deny"
+	Protocol  string
+	Ports     string
+	Source    string
+}
+
+func (r secRule) internetExposed() bool {
+	return r.Direction == "ingress" && r.Access == "allow" && internetSources[strings.ToLower(r.Source)]
+}
+
+// securityRuleChanges returns the rules a resource opens and closes. ok is
+// false for resource types without a rule renderer.
+func securityRuleChanges(r parser.Resource) (opened, closed []secRule, ok bool) {
+	if _, supported := securityRuleTypes[r.Type]; !supported || len(r.RawLines) < 2 {
+		return nil, nil, false
+	}
+	oldLines, newLines := projectPlanSides(r.RawLines[1:])
+	var oldRules, newRules []secRule
+	if r.Action != parser.ActionCreate {
+		oldRules = securityRules(r.Type, parsePlanBody(oldLines))
+	}
+	if r.Action != parser.ActionDestroy {
+		newRules = securityRules(r.Type, parsePlanBody(newLines))
+	}
+
+	inOld := make(map[secRule]bool, len(oldRules))
+	for _, rule := range oldRules {
+		inOld[rule] = true
+	}
+	inNew := make(map[secRule]bool, len(newRules))
+	for _, rule := range newRules {
+		inNew[rule] = true
+	}
+	for _, rule := range newRules {
+		if !inOld[rule] {
+			opened = append(opened, rule)
+			inOld[rule] = true
+		}
+	}
+	for _, rule := range oldRules {
+		if !inNew[rule] {
+			closed = append(closed, rule)
+			inNew[rule] = true
+		}
+	}
+	return opened, closed, true
+}
+
+// projectPlanSides splits plan lines into the before and after documents:
+// "- " lines and blocks belong to the old side, "+ " ones to the new side,
+// and `old -> new` values are split between the two.
+func projectPlanSides(lines []string) (oldLines, newLines []string) {
+	const both, oldOnly, newOnly = 0, 1, 2
+	var stack []int
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		side := both
+		if len(stack) > 0 {
+			side = stack[len(stack)-1]
+		}
+		content := trimmed
+		if hasDiffPrefix(trimmed) {
+			switch trimmed[0] {
+			case '-':
+				side = oldOnly
+			case '+':
+				side = newOnly
+			}
+			content = trimmed[2:]
+		}
+		content = parser.StripPlanComment(content)
+
+		isClose := strings.HasPrefix(content, "}") || strings.HasPrefix(content, "]")
+		if isClose && len(stack) > 0 {
+			side = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+		}
+
+		oldContent, newContent := content, content
+		if key, oldVal, newVal, ok := splitChangeArrow(content); ok {
+			oldContent, newContent = key+" = "+oldVal, key+" = "+newVal
+			if oldVal == "null" {
+				oldContent = ""
+			}
+			if newVal == "null" {
+				newContent = ""
+			}
+		}
+		if side != newOnly && oldContent != "" {
+			oldLines = append(oldLines, oldContent)
+		}
+		if side != oldOnly && newContent != "" {
+			newLines = append(newLines, newContent)
+		}
+
+		if !isClose && (strings.HasSuffix(content, "{") || strings.HasSuffix(content, "[")) {
+			stack = append(stack, side)
+		}
+	}
+	return oldLines, newLines
+}
+
+// planBodyParser reads one side of a resource body into nested maps and
+// lists. Nested blocks are collected into lists under their block name.
+type planBodyParser struct {
+	lines []string
+	pos   int
+}
+
+func parsePlanBody(lines []string) map[string]any {
+	p := &planBodyParser{lines: lines}
+	return p.object()
+}
+
+func (p *planBodyParser) object() map[string]any {
+	obj := make(map[string]any)
+	for p.pos < len(p.lines) {
+		line := strings.TrimSpace(p.lines[p.pos])
+		p.pos++
+		if strings.HasPrefix(line, "}") || strings.HasPrefix(line, "]") {
+			return obj
+		}
+		if key, value, found := strings.Cut(line, " = "); found {
+			key = strings.Trim(strings.TrimSpace(key), `"`)
+			switch value = strings.TrimSpace(value); value {
+			case "[":
+				obj[key] = p.list()
+			case "{":
+				obj[key] = p.object()
+			default:
+				obj[key] = planScalar(value)
+			}
+			continue
+		}
+		if strings.HasSuffix(line, "{") {
+			name := strings.Fields(line)[0]
+			list, _ := obj[name].([]any)
+			obj[name] = append(list, p.object())
+		}
+	}
+	return obj
+}
+
+func (p *planBodyParser) list() []any {
+	var out []any
+	for p.pos < len(p.lines) {
+		line := strings.TrimSpace(p.lines[p.pos])
+		p.pos++
+		switch {
+		case strings.HasPrefix(line, "]"):
+			return out
+		case strings.HasPrefix(line, "}"):
+			continue
+		case line == "{":
+			out = append(out, p.object())
+		case line == "[":
+			out = append(out, p.list())
+		default:
+			out = append(out, planScalar(line))
+		}
+	}
+	return out
+}
+
+func planScalar(v string) any {
+	v = strings.TrimSuffix(strings.TrimSpace(v), ",")
+	switch v {
+	case "[]":
+		return []any{}
+	case "{}":
+		return map[string]any{}
+	}
+	return unquote(v)
+}
+
+// securityRules normalises the rules of one side of a resource.
+func securityRules(resourceType string, body map[string]any) []secRule {
+	var rules []secRule
+	switch resourceType {
+	case "aws_security_group":
+		for _, dir := range securityRuleTypes[resourceType] {
+			for _, item := range planList(body[dir]) {
+				if rule, ok := item.(map[string]any); ok {
+					rules = append(rules, awsSecurityGroupRules(dir, rule)...)
+				}
+			}
+		}
+	case "aws_security_group_rule":
+		rules = awsSecurityGroupRules(planString(body["type"]), body)
+	case "google_compute_firewall":
+		rules = googleFirewallRules(body)
+	case "azurerm_network_security_rule":
+		rules = azureSecurityRules(body)
+	case "azurerm_network_security_group":
+		for _, item := range planList(body["security_rule"]) {
+			if rule, ok := item.(map[string]any); ok {
+				rules = append(rules, azureSecurityRules(rule)...)
+			}
+		}
+	}
+	return rules
+}
+
+func awsSecurityGroupRules(direction string, rule map[string]any) []secRule {
+	base := secRule{
+		Direction: strings.ToLower(direction),
+		Access:    "allow",
+		Protocol:  normaliseProtocol(planString(rule["protocol"])),
+		Ports:     portRange(planString(rule["from_port"]), planString(rule["to_port"])),
+	}
+	if base.Protocol == "all" {
+		base.Ports = "all"
+	}
+	var sources []string
+	for _, field := range []string{"cidr_blocks", "ipv6_cidr_blocks", "security_groups", "prefix_list_ids"} {
+		sources = append(sources, planStrings(rule[field])...)
+	}
+	if sgID := planString(rule["source_security_group_id"]); sgID != "" {
+		sources = append(sources, sgID)
+	}
+	if planString(rule["self"]) == "true" {
+		sources = append(sources, "self")
+	}
+	return expandRuleSources(base, []string{base.Ports}, sources)
+}
+
+func googleFirewallRules(body map[string]any) []secRule {
+	direction := strings.ToLower(planString(body["direction"]))
+	if direction == "" {
+		direction = "ingress"
+	}
+	var sources []string
+	if direction == "egress" {
+		sources = planStrings(body["destination_ranges"])
+	} else {
+		sources = planStrings(body["source_ranges"])
+		for _, tag := range planStrings(body["source_tags"]) {
+			sources = append(sources, "tag:"+tag)
+		}
+		for _, sa := range planStrings(body["source_service_accounts"]) {
+			sources = append(sources, "sa:"+sa)
+		}
+	}
+
+	var rules []secRule
+	for _, access := range []string{"allow", "deny"} {
+		for _, item := range planList(body[access]) {
+			block, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			base := secRule{Direction: direction, Access: access, Protocol: normaliseProtocol(planString(block["protocol"]))}
+			ports := planStrings(block["ports"])
+			if len(ports) == 0 {
+				ports = []string{"all"}
+			}
+			rules = append(rules, expandRuleSources(base, ports, sources)...)
+		}
+	}
+	return rules
+}
+
+func azureSecurityRules(body map[string]any) []secRule {
+	base := secRule{
+		Direction: strings.ToLower(planString(body["direction"])),
+		Access:    strings.ToLower(planString(body["access"])),
+		Protocol:  normaliseProtocol(planString(body["protocol"])),
+	}
+	if base.Direction == "inbound" {
+		base.Direction = "ingress"
+	} else if base.Direction == "outbound" {
+		base.Direction = "egress"
+	}
+	ports := planStrings(body["destination_port_ranges"])
+	if p := planString(body["destination_port_range"]); p != "" {
+		ports = append(ports, p)
+	}
+	for i, p := range ports {
+		if p == "*" {
+			ports[i] = "all"
+		}
+	}
+	sources := planStrings(body["source_address_prefixes"])
+	if s := planString(body["source_address_prefix"]); s != "" {
+		sources = append(sources, s)
+	}
+	return expandRuleSources(base, ports, sources)
+}
+
+// expandRuleSources produces one rule per port range and source.
+func expandRuleSources(base secRule, ports, sources []string) []secRule {
+	if len(ports) == 0 {
+		ports = []string{base.Ports}
+	}
+	if len(sources) == 0 {
+		sources = []string{"(none)"}
+	}
+	var rules []secRule
+	for _, port := range ports {
+		for _, source := range sources {
+			rule := base
+			rule.Ports = port
+			rule.Source = source
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+func normaliseProtocol(p string) string {
+	switch strings.ToLower(p) {
+	case "-1", "*", "all", "":
+		return "all"
+	case "6":
+		return "tcp"
+	case "17":
+		return "udp"
+	case "1":
+		return "icmp"
+	}
+	return strings.ToLower(p)
+}
+
+func portRange(from, to string) string {
+	switch {
+	case from == "" && to == "":
+		return "all"
+	case from == to || to == "":
+		return from
+	case from == "0" && (to == "0" || to == "65535"):
+		return "all"
+	}
+	return from + "-" + to
+}
+
+func planList(v any) []any {
+	list, _ := v.([]any)
+	return list
+}
+
+func planString(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+func planStrings(v any) []string {
+	var out []string
+	for _, item := range planList(v) {
+		if s, ok := item.(string); ok && s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// renderSecurityRuleChanges renders the rules a firewall resource opens and
+// closes as an aligned table, flagging rules newly exposed to the internet.
+func renderSecurityRuleChanges(r parser.Resource, maxWidth int) string {
+	opened, closed, ok := securityRuleChanges(r)
+	if !ok || len(opened)+len(closed) == 0 {
+		return ""
+	}
+
+	type row struct {
+		symbol string
+		style  lipgloss.Style
+		rule   secRule
+	}
+	var rows []row
+	for _, rule := range opened {
+		rows = append(rows, row{createSymbol, lipgloss.NewStyle().Foreground(createColor), rule})
+	}
+	for _, rule := range closed {
+		rows = append(rows, row{destroySymbol, lipgloss.NewStyle().Foreground(destroyColor), rule})
+	}
+
+	cols := func(rule secRule) []string {
+		dir := rule.Direction
+		if rule.Access != "" && rule.Access != "allow" {
+			dir += " " + rule.Access
+		}
+		return []string{dir, rule.Protocol, rule.Ports, rule.Source}
+	}
+	widths := make([]int, 4)
+	for _, rw := range rows {
+		for i, c := range cols(rw.rule) {
+			widths[i] = max(widths[i], lipgloss.Width(c))
+		}
+	}
+
+	const indent = "    "
+	var b strings.Builder
+	b.WriteString(indent)
+	b.WriteString(mutedColor.Render(fmt.Sprintf("┄┄┄ rule changes (%d opened, %d closed) ┄┄┄", len(opened), len(closed))))
+	b.WriteString("\n")
+	exposedStyle := lipgloss.NewStyle().Foreground(destroyColor).Bold(true)
+	for _, rw := range rows {
+		c := cols(rw.rule)
+		for i := 0; i < 3; i++ {
+			c[i] = padCell(c[i], widths[i])
+		}
+		text := strings.Join(c, "  ")
+		line := indent + rw.symbol + " " + rw.style.Render(truncateCell(text, maxWidth-len(indent)-4))
+		if rw.symbol == createSymbol && rw.rule.internetExposed() {
+			line += "  " + exposedStyle.Render(secRuleExposedMarker)
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	b.WriteString(indent)
+	b.WriteString(mutedColor.Render("┄┄┄ end rule changes ┄┄┄"))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// isSecurityRuleFold reports whether a fold holds inline rules that the rule
+// table already summarises; such folds start collapsed.
+func isSecurityRuleFold(r parser.Resource, line string) bool {
+	attrs := securityRuleTypes[r.Type]
+	if len(attrs) == 0 {
+		return false
+	}
+	fields := strings.Fields(stripDiffPrefix(strings.TrimLeft(line, " \t")))
+	return len(fields) > 0 && slices.Contains(attrs, fields[0])
+}
+
+// hasSecurityRuleTable reports whether r gets a rule table with changes.
+func hasSecurityRuleTable(r parser.Resource) bool {
+	opened, closed, ok := securityRuleChanges(r)
+	return ok && len(opened)+len(closed) > 0
+}
@@ -0,0 +1,167 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+var awsSecurityGroupUpdate = parser.Resource{
+	Address: "aws_security_group.web",
+	Type:    "aws_security_group",
+	Action:  parser.ActionUpdate,
+	RawLines: []string{
+		`  ~ resource "aws_security_group" "web" {`,
+		`        id                     = "sg-123"`,
+		`      ~ ingress                = [`,
+		`          - {`,
+		`              - cidr_blocks      = [`,
+		`                  - "10.0.0.0/8",`,
+		`                ]`,
+		`              - description      = "https"`,
+		`              - from_port        = 443`,
+		`              - ipv6_cidr_blocks = []`,
+		`              - prefix_list_ids  = []`,
+		`              - protocol         = "tcp"`,
+		`              - security_groups  = []`,
+		`              - self             = false`,
+		`              - to_port          = 443`,
+		`            },`,
+		`          + {`,
+		`              + cidr_blocks      = [`,
+		`                  + "10.0.0.0/8",`,
+		`                  + "0.0.0.0/0",`,
+		`                ]`,
+		`              + description      = "https"`,
+		`              + from_port        = 443`,
+		`              + ipv6_cidr_blocks = [`,
+		`                  + "::/0",`,
+		`                ]`,
+		`              + prefix_list_ids  = []`,
+		`              + protocol         = "tcp"`,
+		`              + security_groups  = []`,
+		`              + self             = false`,
+		`              + to_port          = 443`,
+		`            },`,
+		`          - {`,
+		`              - cidr_blocks      = [`,
+		`                  - "192.168.0.0/16",`,
+		`                ]`,
+		`              - from_port        = 22`,
+		`              - protocol         = "tcp"`,
+		`              - to_port          = 22`,
+		`            },`,
+		`        ]`,
+		`        name                   = "web"`,
+		`        # (6 unchanged attributes hidden)`,
+		`    }`,
+	},
+}
+
+func TestSecurityRuleChangesNormalisesAWSIngress(t *testing.T) {
+	opened, closed, ok := securityRuleChanges(awsSecurityGroupUpdate)
+	if !ok {
+		t.Fatal("aws_security_group should be supported")
+	}
+	wantOpened := []secRule{
+		{Direction: "ingress", Access: "allow", Protocol: "tcp", Ports: "443", Source: "0.0.0.0/0"},
+		{Direction: "ingress", Access: "allow", Protocol: "tcp", Ports: "443", Source: "::/0"},
+	}
+	wantClosed := []secRule{
+		{Direction: "ingress", Access: "allow", Protocol: "tcp", Ports: "22", Source: "192.168.0.0/16"},
+	}
+	if len(opened) != len(wantOpened) || len(closed) != len(wantClosed) {
+		t.Fatalf("opened=%v closed=%v", opened, closed)
+	}
+	for i := range wantOpened {
+		if opened[i] != wantOpened[i] {
+			t.Errorf("opened[%d] = %+v, want %+v", i, opened[i], wantOpened[i])
+		}
+	}
+	if closed[0] != wantClosed[0] {
+		t.Errorf("closed[0] = %+v, want %+v", closed[0], wantClosed[0])
+	}
+}
+
+func TestRenderSecurityRuleChangesFlagsInternetExposure(t *testing.T) {
+	got := renderExpandedForTest(awsSecurityGroupUpdate, awsSecurityGroupUpdate.RawLines[1:])
+	for _, want := range []string{
+		"┄┄┄ rule changes (2 opened, 1 closed) ┄┄┄",
+		"+ ingress  tcp  443  0.0.0.0/0  " + secRuleExposedMarker,
+		"+ ingress  tcp  443  ::/0  " + secRuleExposedMarker,
+		"- ingress  tcp  22   192.168.0.0/16",
+		"▶ ~ ingress",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("rendered output missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "10.0.0.0/8  ") {
+		t.Errorf("unchanged 10.0.0.0/8 rule should not be listed:\n%s", got)
+	}
+}
+
+func TestSecurityRuleChangesGoogleFirewall(t *testing.T) {
+	r := parser.Resource{
+		Type:   "google_compute_firewall",
+		Action: parser.ActionUpdate,
+		RawLines: []string{
+			`  ~ resource "google_compute_firewall" "ssh" {`,
+			`        direction     = "INGRESS"`,
+			`      - allow {`,
+			`          - ports    = [`,
+			`              - "22",`,
+			`            ] -> null`,
+			`          - protocol = "tcp" -> null`,
+			`        }`,
+			`      + allow {`,
+			`          + ports    = [`,
+			`              + "22",`,
+			`              + "3389",`,
+			`            ]`,
+			`          + protocol = "tcp"`,
+			`        }`,
+			`      ~ source_ranges = [`,
+			`          - "10.0.0.0/8",`,
+			`          + "35.235.240.0/20",`,
+			`        ]`,
+			`    }`,
+		},
+	}
+	opened, closed, _ := securityRuleChanges(r)
+	var got []string
+	for _, rule := range opened {
+		got = append(got, "+"+rule.Ports+" "+rule.Source)
+	}
+	for _, rule := range closed {
+		got = append(got, "-"+rule.Ports+" "+rule.Source)
+	}
+	want := "+22 35.235.240.0/20,+3389 35.235.240.0/20,-22 10.0.0.0/8"
+	if strings.Join(got, ",") != want {
+		t.Errorf("got %s, want %s", strings.Join(got, ","), want)
+	}
+}
+
+func TestSecurityRuleChangesAzureRule(t *testing.T) {
+	r := parser.Resource{
+		Type:   "azurerm_network_security_rule",
+		Action: parser.ActionUpdate,
+		RawLines: []string{
+			`  ~ resource "azurerm_network_security_rule" "rdp" {`,
+			`        access                      = "Allow"`,
+			`        direction                   = "Inbound"`,
+			`        destination_port_range      = "3389"`,
+			`        protocol                    = "Tcp"`,
+			`      ~ source_address_prefix       = "10.0.0.0/8" -> "*"`,
+			`    }`,
+		},
+	}
+	opened, closed, _ := securityRuleChanges(r)
+	if len(opened) != 1 || !opened[0].internetExposed() || opened[0].Ports != "3389" {
+		t.Fatalf("expected internet-exposed 3389 rule opened, got %+v", opened)
+	}
+	if len(closed) != 1 || closed[0].Source != "10.0.0.0/8" {
+		t.Fatalf("expected 10.0.0.0/8 rule closed, got %+v", closed)
+	}
+}
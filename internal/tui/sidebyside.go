@@ -0,0 +1,436 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/wordwrap"
+	"github.com/muesli/reflow/wrap"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+// minSideBySideWidth is the narrowest viewport that still leaves each column
+// room for an indented attribute and its value.
+const minSideBySideWidth = 100
+
+const sideBySideSeparator = " │ "
+
+// supportsSideBySide reports whether a resource has both a before and an
+// after state worth putting next to each other.
+func supportsSideBySide(action parser.Action) bool {
+	switch action {
+	case parser.ActionUpdate, parser.ActionReplace, parser.ActionDeleteCreate, parser.ActionCreateDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func handleKeySideBySide(m Model) (Model, tea.Cmd, bool) {
+	resourceIdx := m.currentResourceIndex()
+	if resourceIdx < 0 || !supportsSideBySide(m.plan.Resources[resourceIdx].Action) {
+		return m, nil, true
+	}
+	if m.sideBySide == nil {
+		m.sideBySide = make(map[int]bool)
+	}
+	m.sideBySide[resourceIdx] = !m.sideBySide[resourceIdx]
+	if m.sideBySide[resourceIdx] {
+		m.expanded[resourceIdx] = true
+	}
+	m.updateViewportContent()
+	m.scrollForExpanded()
+	return m, nil, true
+}
+
+// sideBySideRow is one aligned row of the before | after view. An empty side
+// with a zero-width cell is rendered as blank space.
+type sideBySideRow struct {
+	left  string
+	right string
+}
+
+// sideBySideWriter collects rows for a resource and pairs removed lines with
+// the added lines that follow them, the same way Terraform prints replaced
+// nested blocks and list elements.
+type sideBySideWriter struct {
+	b         *strings.Builder
+	lineCount *int
+	colWidth  int
+	action    parser.Action
+	pending   []string // "- " lines waiting for a matching "+ " line
+}
+
+func (w *sideBySideWriter) writeRow(row sideBySideRow) {
+	left := wrapCell(row.left, w.colWidth)
+	right := wrapCell(row.right, w.colWidth)
+	height := len(left)
+	if len(right) > height {
+		height = len(right)
+	}
+	sep := mutedColor.Render(sideBySideSeparator)
+	for i := 0; i < height; i++ {
+		l, r := "", ""
+		if i < len(left) {
+			l = left[i]
+		}
+		if i < len(right) {
+			r = right[i]
+		}
+		w.b.WriteString(padCell(l, w.colWidth))
+		w.b.WriteString(sep)
+		w.b.WriteString(r)
+		w.b.WriteString("\n")
+		*w.lineCount++
+	}
+}
+
+// writeFull writes pre-rendered content spanning both columns.
+func (w *sideBySideWriter) writeFull(s string) {
+	w.flush()
+	w.b.WriteString(s)
+	if !strings.HasSuffix(s, "\n") {
+		w.b.WriteString("\n")
+	}
+	*w.lineCount += strings.Count(strings.TrimSuffix(s, "\n"), "\n") + 1
+}
+
+func (w *sideBySideWriter) flush() {
+	for _, line := range w.pending {
+		w.writeRow(sideBySideRow{left: sideBySideRemovedCell(line)})
+	}
+	w.pending = nil
+}
+
+// addLine renders one raw plan line into the before and/or after column.
+func (w *sideBySideWriter) addLine(m Model, line string) {
+	trimmed := strings.TrimLeft(line, " \t")
+	indent := line[:len(line)-len(trimmed)]
+
+	switch {
+	case strings.HasPrefix(trimmed, "- "):
+		w.pending = append(w.pending, line)
+		return
+	case strings.HasPrefix(trimmed, "+ "):
+		if len(w.pending) > 0 {
+			removed := w.pending[0]
+			w.pending = w.pending[1:]
+			w.writeRow(pairRemovedAdded(removed, line))
+			return
+		}
+		w.writeRow(sideBySideRow{right: m.colorizeHCLLine(line, parser.ActionCreate)})
+		return
+	}
+
+	w.flush()
+	if strings.HasPrefix(trimmed, "~ ") {
+		content := trimmed[2:]
+		if key, oldVal, newVal, ok := splitChangeArrow(content); ok {
+			w.writeRow(changedValueRow(indent, key, oldVal, newVal))
+			return
+		}
+	}
+	rendered := m.colorizeHCLLine(line, w.action)
+	w.writeRow(sideBySideRow{left: rendered, right: rendered})
+}
+
+// addDiff renders a line diff (e.g. heredoc contents) with deletes on the
+// left, inserts on the right, and adjacent delete/insert runs paired up.
+func (w *sideBySideWriter) addDiff(diff []DiffLine, indent string) {
+	w.flush()
+	var deletes []string
+	flushDeletes := func() {
+		for _, d := range deletes {
+			w.writeRow(sideBySideRow{left: indent + lipgloss.NewStyle().Foreground(destroyColor).Render("- "+d)})
+		}
+		deletes = nil
+	}
+	for _, d := range diff {
+		switch d.Op {
+		case DiffDelete:
+			deletes = append(deletes, d.Text)
+		case DiffInsert:
+			if len(deletes) > 0 {
+				oldText := deletes[0]
+				deletes = deletes[1:]
+				left, right := renderIntraline(oldText, d.Text,
+					lipgloss.NewStyle().Foreground(destroyColor), lipgloss.NewStyle().Foreground(createColor),
+					attrOldEmphStyle.Strikethrough(false), attrNewEmphStyle)
+				w.writeRow(sideBySideRow{
+					left:  indent + destroySymbol + " " + left,
+					right: indent + createSymbol + " " + right,
+				})
+				continue
+			}
+			w.writeRow(sideBySideRow{right: indent + lipgloss.NewStyle().Foreground(createColor).Render("+ "+d.Text)})
+		case DiffEqual:
+			flushDeletes()
+			cell := indent + mutedColor.Render("  "+d.Text)
+			w.writeRow(sideBySideRow{left: cell, right: cell})
+		case DiffSeparator:
+			flushDeletes()
+			cell := indent + mutedColor.Render("@@ ··· @@")
+			w.writeRow(sideBySideRow{left: cell, right: cell})
+		}
+	}
+	flushDeletes()
+}
+
+// addHeredocBody renders the body of a single heredoc attribute. Lines that
+// carry Terraform's own +/- markers go to one side only; everything else is
+// shown on the side(s) the heredoc attribute itself belongs to.
+func (w *sideBySideWriter) addHeredocBody(header string, body []string, lineAction parser.Action) {
+	w.flush()
+	baseIndent := heredocContentBaseIndent(body)
+	for i, colored := range highlightHeredocBody(header, body) {
+		line := body[i]
+		trimmed := strings.TrimLeft(line, " \t")
+		indent := len(line) - len(trimmed)
+		if hasDiffPrefix(trimmed) && baseIndent >= 2 && indent == baseIndent-2 {
+			switch trimmed[0] {
+			case '-':
+				w.writeRow(sideBySideRow{left: colored})
+				continue
+			case '+':
+				w.writeRow(sideBySideRow{right: colored})
+				continue
+			}
+		}
+		switch lineAction {
+		case parser.ActionDestroy:
+			w.writeRow(sideBySideRow{left: colored})
+		case parser.ActionCreate:
+			w.writeRow(sideBySideRow{right: colored})
+		default:
+			w.writeRow(sideBySideRow{left: colored, right: colored})
+		}
+	}
+}
+
+// renderSideBySideContent renders an expanded resource as two aligned
+// columns (before | after). It walks the same folds, heredocs and userdata
+// lines as renderExpandedContent so sub-block navigation stays in sync, and
+// falls back to the unified view when the viewport is too narrow.
+func (m *Model) renderSideBySideContent(b *strings.Builder, r parser.Resource, selected bool, lineCount *int) {
+	maxWidth := m.viewport.Width
+	if maxWidth < minSideBySideWidth {
+		b.WriteString(mutedColor.Render(fmt.Sprintf("    (side-by-side needs %d columns, showing unified diff)", minSideBySideWidth)))
+		b.WriteString("\n")
+		*lineCount++
+		m.renderExpandedContent(b, r, selected, lineCount)
+		return
+	}
+
+	colWidth := (maxWidth - lipgloss.Width(sideBySideSeparator)) / 2
+	w := &sideBySideWriter{b: b, lineCount: lineCount, colWidth: colWidth, action: r.Action}
+	w.writeRow(sideBySideRow{
+		left:  "    " + searchStyle.Render("before"),
+		right: "    " + searchStyle.Render("after"),
+	})
+
+	if delta := m.renderPlanDelta(r, maxWidth); delta != "" {
+		w.writeFull(delta)
+	}
+	if table := renderSecurityRuleChanges(r, maxWidth); table != "" {
+		w.writeFull(table)
+	}
+
+	lines, _ := m.expandedLines(r)
+	folds := findFoldBlocks(r, lines)
+	foldsByStart := make(map[int]foldBlock, len(folds))
+	for _, block := range folds {
+		foldsByStart[block.Start] = block
+	}
+
+	foldIdx := 0
+	for idx := 0; idx < len(lines); idx++ {
+		line := lines[idx]
+
+		if decoded, ok := m.tryRenderUserdata(line, r.Action, maxWidth); ok {
+			w.writeFull(decoded)
+			continue
+		}
+
+		if decoded, ok := m.tryRenderBlob(line, r.Action, maxWidth); ok {
+			w.writeFull(decoded)
+			continue
+		}
+
+		if block, ok := foldsByStart[idx]; ok {
+			w.flush()
+			blockSelected := selected && foldIdx == m.blockCursor
+			if blockSelected {
+				m.selectedLineStart = *lineCount
+			}
+			collapsed := m.isFoldCollapsed(block)
+			w.writeFull(m.renderFoldHeader(line, r.Action, block, collapsed, blockSelected, maxWidth))
+			foldIdx++
+
+			if collapsed {
+				idx = block.End - 1
+				continue
+			}
+			if block.TagTable != nil {
+				if table := renderTagTable(block.TagTable, extractIndent(line), maxWidth); table != "" {
+					w.writeFull(table)
+				}
+				idx = block.End - 1
+				continue
+			}
+			if block.JSONDiff != nil {
+				if contextDiff := ContextDiff(block.JSONDiff, m.diffContextSize()); contextDiff != nil {
+					w.addDiff(contextDiff, extractIndent(line))
+				}
+				idx = block.End - 1
+				continue
+			}
+			if block.HeredocPair {
+				diff := block.PairDiff
+				if diff == nil {
+					oldContent := extractHeredocContent(lines[block.Start+1 : block.OldEnd-1])
+					newContent := extractHeredocContent(lines[block.AddStart+1 : block.End-1])
+					diff = ComputeDiff(oldContent, newContent)
+				}
+				if contextDiff := ContextDiff(diff, m.diffContextSize()); contextDiff != nil {
+					w.addDiff(contextDiff, extractIndent(lines[block.Start]))
+				}
+				idx = block.End - 1
+				continue
+			}
+			if block.Heredoc {
+				_, _, lineAction := parseUserdataLinePrefix(strings.TrimLeft(line, " \t"), parser.ActionUpdate)
+				if preview, ok := renderHeredocBlob(lines[idx+1:block.End-1], extractIndent(line), lineAction, maxWidth); ok {
+					w.writeFull(preview)
+				} else {
+					w.addHeredocBody(line, lines[idx+1:block.End-1], lineAction)
+				}
+				closing := lines[block.End-1]
+				w.writeRow(sideBySideRow{left: closing, right: closing})
+				idx = block.End - 1
+				continue
+			}
+			continue
+		}
+
+		w.addLine(*m, line)
+	}
+	w.flush()
+}
+
+// splitChangeArrow splits `key = old -> new` into its parts. The key keeps
+// Terraform's alignment padding so columns line up with neighbouring rows.
+func splitChangeArrow(content string) (key, oldVal, newVal string, ok bool) {
+	eq := strings.Index(content, " = ")
+	if eq <= 0 {
+		return "", "", "", false
+	}
+	value := content[eq+3:]
+	arrow := strings.Index(value, " -> ")
+	if arrow < 0 {
+		return "", "", "", false
+	}
+	return content[:eq], strings.TrimSpace(value[:arrow]), strings.TrimSpace(value[arrow+4:]), true
+}
+
+// changedValueRow renders an in-place `~ key = old -> new` change as one row.
+func changedValueRow(indent, key, oldVal, newVal string) sideBySideRow {
+	var left, right string
+	if intralineComparable(oldVal, newVal) {
+		left, right = renderIntraline(oldVal, newVal, attrOldValueStyle, attrNewValueStyle, attrOldEmphStyle, attrNewEmphStyle)
+	} else {
+		left = colorizeSideValue(oldVal, attrOldValueStyle)
+		right = colorizeSideValue(newVal, attrNewValueStyle)
+	}
+	name := attrNameStyle.Render(key)
+	return sideBySideRow{
+		left:  indent + updateSymbol + " " + name + " = " + left,
+		right: indent + updateSymbol + " " + name + " = " + right,
+	}
+}
+
+// pairRemovedAdded puts a removed line and the added line that replaces it on
+// the same row, with intraline highlighting when they set the same key.
+func pairRemovedAdded(removed, added string) sideBySideRow {
+	removedContent := strings.TrimLeft(removed, " \t")[2:]
+	addedContent := strings.TrimLeft(added, " \t")[2:]
+	removedIndent := extractIndent(removed)
+	addedIndent := extractIndent(added)
+
+	oldKey, oldVal, oldOk := splitAssignment(removedContent)
+	newKey, newVal, newOk := splitAssignment(addedContent)
+	oldVal = strings.TrimSuffix(oldVal, " -> null")
+	if oldOk && newOk && strings.TrimSpace(oldKey) == strings.TrimSpace(newKey) && intralineComparable(oldVal, newVal) {
+		left, right := renderIntraline(oldVal, newVal,
+			lipgloss.NewStyle().Foreground(destroyColor), lipgloss.NewStyle().Foreground(createColor),
+			attrOldEmphStyle.Strikethrough(false), attrNewEmphStyle)
+		return sideBySideRow{
+			left:  removedIndent + destroySymbol + " " + attrNameStyle.Render(oldKey) + " = " + left,
+			right: addedIndent + createSymbol + " " + attrNameStyle.Render(newKey) + " = " + right,
+		}
+	}
+	return sideBySideRow{left: sideBySideRemovedCell(removed), right: addedIndent + createSymbol + " " + lipgloss.NewStyle().Foreground(createColor).Render(addedContent)}
+}
+
+// sideBySideRemovedCell renders a "- " line for the before column, dropping
+// the redundant "-> null" that Terraform appends to destroyed values.
+func sideBySideRemovedCell(line string) string {
+	content := strings.TrimSuffix(strings.TrimLeft(line, " \t")[2:], " -> null")
+	return extractIndent(line) + destroySymbol + " " + lipgloss.NewStyle().Foreground(destroyColor).Render(content)
+}
+
+func splitAssignment(content string) (key, value string, ok bool) {
+	eq := strings.Index(content, " = ")
+	if eq <= 0 {
+		return "", "", false
+	}
+	return content[:eq], strings.TrimSpace(content[eq+3:]), true
+}
+
+func colorizeSideValue(value string, base lipgloss.Style) string {
+	switch {
+	case strings.Contains(value, "(known after apply)"):
+		return attrComputedStyle.Render(value)
+	case strings.Contains(value, "(sensitive"):
+		return lipgloss.NewStyle().Foreground(replaceColor).Italic(true).Render(value)
+	default:
+		return base.Render(value)
+	}
+}
+
+// wrapCell wraps an already-colored cell to width, breaking long words too.
+// Continuation lines keep the cell's indentation plus the width of a diff
+// prefix so wrapped values stay visually attached to their attribute.
+func wrapCell(s string, width int) []string {
+	if s == "" {
+		return nil
+	}
+	if width <= 0 || lipgloss.Width(s) <= width {
+		return []string{s}
+	}
+	content := strings.TrimLeft(s, " ")
+	indent := s[:len(s)-len(content)]
+	available := width - len(indent) - 2
+	if available < 10 {
+		return strings.Split(wrap.String(wordwrap.String(s, width), width), "\n")
+	}
+	wrapped := strings.Split(wrap.String(wordwrap.String(content, available), available), "\n")
+	for i := range wrapped {
+		if i == 0 {
+			wrapped[i] = indent + wrapped[i]
+		} else {
+			wrapped[i] = indent + "  " + wrapped[i]
+		}
+	}
+	return wrapped
+}
+
+// padCell right-pads an already-colored cell to width.
+func padCell(s string, width int) string {
+	if w := lipgloss.Width(s); w < width {
+		return s + strings.Repeat(" ", width-w)
+	}
+	return s
+}
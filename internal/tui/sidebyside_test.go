@@ -0,0 +1,114 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/viewport"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+func renderSideBySideForTest(r parser.Resource, width int) string {
+	m := Model{
+		viewport:     viewport.New(width, 40),
+		foldedBlocks: make(map[string]bool),
+		blockCursor:  -1,
+		diffContext:  defaultDiffContext,
+	}
+	var b strings.Builder
+	lineCount := 0
+	m.renderSideBySideContent(&b, r, false, &lineCount)
+	return stripRenderANSI(b.String())
+}
+
+func TestSideBySideAlignsChangedAttributes(t *testing.T) {
+	r := parser.Resource{
+		Address: "aws_iam_role.app",
+		Action:  parser.ActionUpdate,
+		RawLines: []string{
+			`  ~ resource "aws_iam_role" "app" {`,
+			`        id   = "app"`,
+			`      ~ arn  = "arn:aws:iam::123:role/app-prod-v1" -> "arn:aws:iam::123:role/app-prod-v2"`,
+			`      - path = "/old/" -> null`,
+			`      + path = "/new/"`,
+			`    }`,
+		},
+	}
+
+	got := renderSideBySideForTest(r, 140)
+	var arnRow, pathRow string
+	for _, line := range strings.Split(got, "\n") {
+		if strings.Contains(line, "~ arn") {
+			arnRow = line
+		}
+		if strings.Contains(line, "path = ") {
+			pathRow = line
+		}
+	}
+	if !strings.Contains(arnRow, `"arn:aws:iam::123:role/app-prod-v1"`) || !strings.Contains(arnRow, `"arn:aws:iam::123:role/app-prod-v2"`) {
+		t.Fatalf("expected old and new arn on the same row:\n%s", got)
+	}
+	left, right, ok := strings.Cut(pathRow, sideBySideSeparator)
+	if !ok || !strings.Contains(left, `"/old/"`) || !strings.Contains(right, `"/new/"`) {
+		t.Fatalf("expected removed and added path paired in one row:\n%s", got)
+	}
+	if strings.Contains(left, "-> null") {
+		t.Fatalf("expected -> null to be dropped from the before column:\n%s", got)
+	}
+}
+
+func TestSideBySideRendersHeredocPairAsColumns(t *testing.T) {
+	r := parser.Resource{
+		Address: "helm_release.chart",
+		Action:  parser.ActionUpdate,
+		RawLines: []string{
+			`  ~ resource "helm_release" "chart" {`,
+			`      ~ values = [`,
+			`          - <<-EOT`,
+			`              controller:`,
+			`                replicaCount: 2`,
+			`            EOT,`,
+			`          + <<-EOT`,
+			`              controller:`,
+			`                replicaCount: 3`,
+			`            EOT,`,
+			`        ]`,
+		},
+	}
+
+	got := renderSideBySideForTest(r, 140)
+	if !strings.Contains(got, `heredoc diff <<-EOT (2 → 2 lines)`) {
+		t.Fatalf("expected heredoc pair fold header:\n%s", got)
+	}
+	found := false
+	for _, line := range strings.Split(got, "\n") {
+		left, right, ok := strings.Cut(line, sideBySideSeparator)
+		if ok && strings.Contains(left, "replicaCount: 2") && strings.Contains(right, "replicaCount: 3") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected heredoc lines paired across columns:\n%s", got)
+	}
+}
+
+func TestSideBySideFallsBackWhenNarrow(t *testing.T) {
+	r := parser.Resource{
+		Address: "aws_instance.web",
+		Action:  parser.ActionUpdate,
+		RawLines: []string{
+			`  ~ resource "aws_instance" "web" {`,
+			`      ~ instance_type = "t3.micro" -> "t3.large"`,
+			`    }`,
+		},
+	}
+
+	got := renderSideBySideForTest(r, 60)
+	if !strings.Contains(got, "showing unified diff") {
+		t.Fatalf("expected narrow fallback note:\n%s", got)
+	}
+	if strings.Contains(got, sideBySideSeparator) {
+		t.Fatalf("did not expect columns in narrow fallback:\n%s", got)
+	}
+}
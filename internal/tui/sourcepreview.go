@@ -0,0 +1,182 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+
+	"github.com/CaptShanks/terraprism/internal/source"
+)
+
+// editorFinishedMsg is sent when the editor opened with O exits.
+type editorFinishedMsg struct {
+	err error
+}
+
+// WithSourceDir sets the directory searched for the configuration of the
+// previewed resource, e.g. the -chdir the plan ran with.
+func (m Model) WithSourceDir(dir string) Model {
+	m.sourceDir = dir
+	return m
+}
+
+// locateSource finds the configuration block of the selected resource.
+func (m Model) locateSource() (string, source.Location, error) {
+	idx := m.currentResourceIndex()
+	if idx < 0 {
+		return "", source.Location{}, fmt.Errorf("no resource selected")
+	}
+	address := m.plan.Resources[idx].Address
+	dir := m.sourceDir
+	if dir == "" {
+		dir = "."
+	}
+	loc, err := source.Locate(dir, address)
+	return address, loc, err
+}
+
+func handleKeySourcePreview(m Model) (Model, tea.Cmd, bool) {
+	address, loc, err := m.locateSource()
+	if address == "" {
+		return m, nil, true
+	}
+	m.sourcePreview = true
+	m.sourceAddr = address
+	m.sourceLoc = loc
+	m.sourceErr = err
+	m.sourceOffset = 0
+	return m, nil, true
+}
+
+func handleKeyOpenEditor(m Model) (Model, tea.Cmd, bool) {
+	address, loc, err := m.locateSource()
+	if address == "" {
+		return m, nil, true
+	}
+	if err != nil {
+		// Show why the editor cannot be opened.
+		m, _, _ = handleKeySourcePreview(m)
+		return m, nil, true
+	}
+	return m, openEditorCmd(loc), true
+}
+
+// handleSourcePreviewKey handles key presses in the source preview.
+func (m Model) handleSourcePreviewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	maxOffset := max(0, len(m.sourceLoc.Lines)-m.sourcePageSize())
+	switch msg.String() {
+	case "esc", "q", "o":
+		m.sourcePreview = false
+		m.updateViewportContent()
+	case "ctrl+c":
+		return m, tea.Quit
+	case "e", "O":
+		if m.sourceErr == nil {
+			return m, openEditorCmd(m.sourceLoc)
+		}
+	case "down", "j":
+		m.sourceOffset = min(maxOffset, m.sourceOffset+1)
+	case "up", "k":
+		m.sourceOffset = max(0, m.sourceOffset-1)
+	case "d", "ctrl+d", "pgdown":
+		m.sourceOffset = min(maxOffset, m.sourceOffset+m.sourcePageSize()/2)
+	case "u", "ctrl+u", "pgup":
+		m.sourceOffset = max(0, m.sourceOffset-m.sourcePageSize()/2)
+	case "g", "home":
+		m.sourceOffset = 0
+	case "G", "end":
+		m.sourceOffset = maxOffset
+	}
+	return m, nil
+}
+
+// openEditorCmd suspends the TUI and opens the block in $VISUAL or $EDITOR.
+func openEditorCmd(loc source.Location) tea.Cmd {
+	cmd := editorCommand(loc.File, loc.Line)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return editorFinishedMsg{err: err}
+	})
+}
+
+// editorCommand builds the command opening file at line. VS Code style
+// editors take --goto file:line; everything else gets +line file, which vi,
+// vim, nvim, nano, emacs, micro and helix understand.
+func editorCommand(file string, line int) *exec.Cmd {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		args = []string{"vi"}
+	}
+	switch filepath.Base(args[0]) {
+	case "code", "code-insiders", "codium", "cursor":
+		args = append(args, "--goto", file+":"+strconv.Itoa(line))
+	default:
+		args = append(args, "+"+strconv.Itoa(line), file)
+	}
+	return exec.Command(args[0], args[1:]...)
+}
+
+// handleEditorFinished returns to the TUI after the editor exits, showing
+// the error in the preview if it could not be started.
+func (m Model) handleEditorFinished(msg editorFinishedMsg) Model {
+	if msg.err != nil {
+		m.sourcePreview = true
+		m.sourceErr = fmt.Errorf("editor: %w", msg.err)
+	}
+	m.updateViewportContent()
+	return m
+}
+
+// sourcePageSize returns how many snippet lines fit in the preview.
+func (m Model) sourcePageSize() int {
+	return max(5, m.height-9)
+}
+
+// viewSourcePreview renders the source preview (returns full view, caller returns early).
+func (m Model) viewSourcePreview() string {
+	var b strings.Builder
+	b.WriteString(m.viewHeader())
+	b.WriteString(searchStyle.Render("Source of " + m.sourceAddr))
+	b.WriteString("\n\n")
+
+	maxWidth := m.width - 4
+	if m.sourceErr != nil {
+		b.WriteString(lipgloss.NewStyle().Foreground(destroyColor).Render(m.sourceErr.Error()))
+		b.WriteString("\n\n")
+		b.WriteString(helpStyle.Render("Esc/o: close"))
+		return appStyle.Render(b.String())
+	}
+
+	b.WriteString(mutedColor.Render(fmt.Sprintf("%s:%d", m.sourceLoc.File, m.sourceLoc.Line)))
+	b.WriteString("\n\n")
+	lastLine := m.sourceLoc.Line + len(m.sourceLoc.Lines) - 1
+	numWidth := len(strconv.Itoa(lastLine))
+	end := min(len(m.sourceLoc.Lines), m.sourceOffset+m.sourcePageSize())
+	for i := m.sourceOffset; i < end; i++ {
+		number := mutedColor.Render(fmt.Sprintf("%*d │ ", numWidth, m.sourceLoc.Line+i))
+		line := number + highlightCode(langHCL, strings.TrimRight(m.sourceLoc.Lines[i], "\r"))
+		if maxWidth > 0 && lipgloss.Width(line) > maxWidth {
+			line = truncate.StringWithTail(line, uint(maxWidth), "…")
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	if end < len(m.sourceLoc.Lines) {
+		b.WriteString(mutedColor.Render(fmt.Sprintf("  ... %d more", len(m.sourceLoc.Lines)-end)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("j/k: scroll • d/u: page • e: open in $EDITOR • Esc/o: close"))
+	return appStyle.Render(b.String())
+}
@@ -0,0 +1,67 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+func TestSourcePreview(t *testing.T) {
+	dir := t.TempDir()
+	config := "variable \"ami\" {}\n\nresource \"aws_instance\" \"web\" {\n  ami           = var.ami\n  instance_type = \"t3.large\"\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	plan, err := parser.Parse(comparePreviousPlan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewModel(plan, "test").WithSourceDir(dir)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+
+	m = pressKeys(m, "o")
+	view := stripRenderANSI(m.View())
+	for _, want := range []string{"Source of aws_instance.web", "main.tf:3", `3 │ resource "aws_instance" "web" {`, `5 │   instance_type = "t3.large"`, "6 │ }"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("preview missing %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "variable") {
+		t.Error("preview should only show the resource block")
+	}
+
+	m = pressKeys(m, "esc", "j", "o")
+	view = stripRenderANSI(m.View())
+	if !strings.Contains(view, `resource "aws_s3_bucket" "old" not found`) {
+		t.Errorf("preview should explain a missing block:\n%s", view)
+	}
+	m = pressKeys(m, "o")
+	if m.sourcePreview {
+		t.Error("o should close the preview")
+	}
+}
+
+func TestEditorCommand(t *testing.T) {
+	tests := []struct {
+		visual, editor string
+		want           []string
+	}{
+		{"", "", []string{"vi", "+12", "main.tf"}},
+		{"", "nvim", []string{"nvim", "+12", "main.tf"}},
+		{"code --wait", "vim", []string{"code", "--wait", "--goto", "main.tf:12"}},
+	}
+	for _, tt := range tests {
+		t.Setenv("VISUAL", tt.visual)
+		t.Setenv("EDITOR", tt.editor)
+		if got := editorCommand("main.tf", 12).Args; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("VISUAL=%q EDITOR=%q: %v, want %v", tt.visual, tt.editor, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+)
+
+// splitPane identifies a pane of the split layout.
+type splitPane int
+
+const (
+	listPane splitPane = iota
+	detailPane
+)
+
+const (
+	defaultSplitPercent = 40
+	minSplitPercent     = 20
+	maxSplitPercent     = 80
+	splitPercentStep    = 5
+)
+
+func handleKeyToggleSplit(m Model) (Model, tea.Cmd, bool) {
+	m.split = !m.split
+	m.splitFocus = listPane
+	m.blockCursor = -1
+	if m.splitPercent == 0 {
+		m.splitPercent = defaultSplitPercent
+	}
+	m.detailViewport.SetYOffset(0)
+	m.updateViewportContent()
+	if !m.split {
+		m.ensureCursorVisible()
+	}
+	return m, nil, true
+}
+
+// splitPaneWidths returns the widths of the list and detail panes.
+func (m Model) splitPaneWidths() (list, detail int) {
+	total := m.viewport.Width
+	list = total * m.splitPercent / 100
+	detail = max(1, total-list-lipgloss.Width(sideBySideSeparator))
+	return list, detail
+}
+
+// updateSplitPanes sizes the panes, keeps the cursor inside the list window
+// and renders the selected resource into the detail viewport.
+func (m *Model) updateSplitPanes() {
+	_, detailWidth := m.splitPaneWidths()
+	height := max(1, m.viewport.Height-1) // one line for the pane titles
+	m.detailViewport.Width = detailWidth
+	m.detailViewport.Height = height
+
+	displayed := len(m.displayedResourceIndices())
+	if m.cursor < m.listOffset {
+		m.listOffset = m.cursor
+	}
+	if m.cursor >= m.listOffset+height {
+		m.listOffset = m.cursor - height + 1
+	}
+	m.listOffset = max(0, min(m.listOffset, displayed-height))
+
+	m.detailViewport.SetContent(m.renderDetail(detailWidth))
+}
+
+// renderDetail renders the selected resource's expanded content for the
+// detail pane, with content search matches highlighted.
+func (m *Model) renderDetail(width int) string {
+	m.contentMatchLines = nil
+	m.selectedLineStart = 0
+	resourceIdx := m.currentResourceIndex()
+	if resourceIdx < 0 {
+		return mutedColor.Render("No resource selected.")
+	}
+	r := m.plan.Resources[resourceIdx]
+
+	var b strings.Builder
+	b.WriteString(GetResourceStyle(string(r.Action)).Render(r.Address))
+	b.WriteString(" ")
+	b.WriteString(mutedColor.Render(getActionDescription(r.Action)))
+	b.WriteString("\n")
+	if len(r.RawLines) > 1 {
+		// The renderers wrap to the viewport width; narrow it to the pane.
+		fullWidth := m.viewport.Width
+		m.viewport.Width = width
+		body := m.renderBody(resourceIdx, m.splitFocus == detailPane)
+		m.viewport.Width = fullWidth
+		b.WriteString(body.text)
+		if body.selectedLine >= 0 {
+			m.selectedLineStart = 1 + body.selectedLine
+		}
+	}
+
+	content := b.String()
+	if m.contentSearchActive() {
+		content, m.contentMatchLines = highlightContentMatches(content, strings.TrimSpace(m.searchQuery), m.contentMatchCursor)
+	}
+	return content
+}
+
+// handleSplitKey handles the keys that behave differently in the split
+// layout. It returns false for keys that keep their normal meaning.
+func (m Model) handleSplitKey(key string) (Model, tea.Cmd, bool) {
+	switch key {
+	case "tab":
+		if m.splitFocus == listPane {
+			m.splitFocus = detailPane
+		} else {
+			m.splitFocus = listPane
+		}
+		m.blockCursor = -1
+		m.updateViewportContent()
+		return m, nil, true
+	case "<":
+		m.splitPercent = max(minSplitPercent, m.splitPercent-splitPercentStep)
+		m.updateViewportContent()
+		return m, nil, true
+	case ">":
+		m.splitPercent = min(maxSplitPercent, m.splitPercent+splitPercentStep)
+		m.updateViewportContent()
+		return m, nil, true
+	}
+	if m.splitFocus == listPane {
+		return m.handleSplitListKey(key)
+	}
+	return m.handleSplitDetailKey(key)
+}
+
+func (m Model) handleSplitListKey(key string) (Model, tea.Cmd, bool) {
+	page := max(1, m.detailViewport.Height/2)
+	switch key {
+	case "up", "k":
+		m.moveSplitCursor(-1)
+	case "down", "j":
+		m.moveSplitCursor(1)
+	case "u", "ctrl+u", "pgup":
+		m.moveSplitCursor(-page)
+	case "d", "ctrl+d", "pgdown":
+		m.moveSplitCursor(page)
+	case "enter", " ", "l", "right":
+		m.splitFocus = detailPane
+		m.updateViewportContent()
+	default:
+		return m, nil, false
+	}
+	return m, nil, true
+}
+
+// moveSplitCursor moves the list cursor and shows the new resource's
+// details from the top.
+func (m *Model) moveSplitCursor(delta int) {
+	displayed := m.displayedResourceIndices()
+	if len(displayed) == 0 {
+		return
+	}
+	cursor := max(0, min(len(displayed)-1, m.cursor+delta))
+	if cursor == m.cursor {
+		return
+	}
+	m.cursor = cursor
+	m.currentMatch = cursor
+	m.blockCursor = -1
+	m.contentMatchCursor = 0
+	m.detailViewport.SetYOffset(0)
+	m.updateViewportContent()
+}
+
+func (m Model) handleSplitDetailKey(key string) (Model, tea.Cmd, bool) {
+	switch key {
+	case "up", "k":
+		if m.blockCursor >= 0 {
+			m.blockCursor--
+		} else {
+			m.detailViewport.ScrollUp(1)
+			return m, nil, true
+		}
+	case "down", "j":
+		if m.blockCursor < len(m.currentFoldBlocks())-1 {
+			m.blockCursor++
+		} else {
+			m.detailViewport.ScrollDown(1)
+			return m, nil, true
+		}
+	case "enter", " ":
+		m.toggleCurrentFold()
+	case "l", "right":
+		m.setCurrentFoldCollapsed(false)
+	case "h", "left", "backspace":
+		if !m.setCurrentFoldCollapsed(true) {
+			m.splitFocus = listPane
+			m.blockCursor = -1
+		}
+	case "ctrl+e":
+		m.detailViewport.ScrollDown(1)
+		return m, nil, true
+	case "ctrl+y":
+		m.detailViewport.ScrollUp(1)
+		return m, nil, true
+	case "d", "ctrl+d":
+		m.detailViewport.HalfPageDown()
+		return m, nil, true
+	case "u", "ctrl+u":
+		m.detailViewport.HalfPageUp()
+		return m, nil, true
+	case "pgdown":
+		m.detailViewport.PageDown()
+		return m, nil, true
+	case "pgup":
+		m.detailViewport.PageUp()
+		return m, nil, true
+	case "g":
+		m.detailViewport.GotoTop()
+		return m, nil, true
+	case "G":
+		m.detailViewport.GotoBottom()
+		return m, nil, true
+	default:
+		return m, nil, false
+	}
+	m.updateViewportContent()
+	m.ensureDetailSelectionVisible()
+	return m, nil, true
+}
+
+// ensureDetailSelectionVisible scrolls the detail pane to the selected fold.
+func (m *Model) ensureDetailSelectionVisible() {
+	if m.blockCursor < 0 {
+		return
+	}
+	line := m.selectedLineStart
+	top := m.detailViewport.YOffset
+	if line < top {
+		m.detailViewport.SetYOffset(line)
+	} else if line >= top+m.detailViewport.Height {
+		m.detailViewport.SetYOffset(line - m.detailViewport.Height + 1)
+	}
+}
+
+// viewSplitPanes renders the resource list and the detail pane side by side.
+func (m Model) viewSplitPanes() string {
+	listWidth, detailWidth := m.splitPaneWidths()
+	height := m.detailViewport.Height
+	displayed := m.displayedResourceIndices()
+
+	listLines := []string{splitPaneTitle(fmt.Sprintf("Resources (%d)", len(displayed)), m.splitFocus == listPane, listWidth)}
+	for i := m.listOffset; i < len(displayed) && i < m.listOffset+height; i++ {
+		r := m.plan.Resources[displayed[i]]
+		var line string
+		if i == m.cursor {
+			line = m.renderSelectedResourceLine(r, false, false)
+		} else {
+			line = m.renderResourceLine(r, false, m.searchQuery != "")
+		}
+		listLines = append(listLines, fitPaneLine(line, listWidth))
+	}
+	for len(listLines) < height+1 {
+		listLines = append(listLines, strings.Repeat(" ", listWidth))
+	}
+
+	separator := strings.TrimRight(strings.Repeat(mutedColor.Render(sideBySideSeparator)+"\n", height+1), "\n")
+	detail := splitPaneTitle("Details", m.splitFocus == detailPane, detailWidth) + "\n" + m.detailViewport.View()
+	return lipgloss.JoinHorizontal(lipgloss.Top, strings.Join(listLines, "\n"), separator, detail)
+}
+
+// splitPaneTitle renders a pane title, highlighted when the pane has focus.
+func splitPaneTitle(title string, focused bool, width int) string {
+	if focused {
+		return fitPaneLine(searchStyle.Render("● "+title), width)
+	}
+	return fitPaneLine(mutedColor.Render("  "+title), width)
+}
+
+// fitPaneLine truncates or pads a styled line to exactly width columns.
+func fitPaneLine(line string, width int) string {
+	if lipgloss.Width(line) > width {
+		line = truncate.StringWithTail(line, uint(max(0, width)), "…")
+	}
+	if pad := width - lipgloss.Width(line); pad > 0 {
+		line += strings.Repeat(" ", pad)
+	}
+	return line
+}
@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+func splitTestModel(t *testing.T) Model {
+	t.Helper()
+	plan := &parser.Plan{Resources: []parser.Resource{
+		{Address: "aws_instance.web", Type: "aws_instance", Action: parser.ActionUpdate, RawLines: []string{
+			`  ~ resource "aws_instance" "web" {`,
+			`      ~ instance_type = "t3.micro" -> "t3.large"`,
+			`      ~ root_block_device {`,
+			`          ~ volume_size = 10 -> 20`,
+			`        }`,
+			`    }`,
+		}},
+		{Address: "aws_s3_bucket.logs", Type: "aws_s3_bucket", Action: parser.ActionCreate, RawLines: []string{
+			`  + resource "aws_s3_bucket" "logs" {`,
+			`      + bucket = "my-logs"`,
+			`    }`,
+		}},
+	}}
+	m := NewModel(plan, "test")
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	m = updated.(Model)
+	m, _, _ = handleKeyToggleSplit(m)
+	return m
+}
+
+func pressKeys(m Model, keys ...string) Model {
+	for _, key := range keys {
+		var msg tea.KeyMsg
+		switch key {
+		case "tab":
+			msg = tea.KeyMsg{Type: tea.KeyTab}
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+	return m
+}
+
+func TestSplitPaneShowsListAndSelectedDetails(t *testing.T) {
+	m := splitTestModel(t)
+	view := stripRenderANSI(m.View())
+	for _, want := range []string{"● Resources (2)", "Details", "aws_s3_bucket.logs", "instance_type", "volume_size"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("split view missing %q:\n%s", want, view)
+		}
+	}
+
+	m = pressKeys(m, "j")
+	view = stripRenderANSI(m.View())
+	if m.cursor != 1 || !strings.Contains(view, `bucket = "my-logs"`) || strings.Contains(view, "instance_type") {
+		t.Errorf("j in the list should show the next resource's details:\n%s", view)
+	}
+}
+
+func TestSplitPaneDetailFocusFoldsAndResize(t *testing.T) {
+	m := splitTestModel(t)
+	m = pressKeys(m, "tab")
+	if m.splitFocus != detailPane {
+		t.Fatal("tab should focus the detail pane")
+	}
+	m = pressKeys(m, "j")
+	if m.cursor != 0 || m.blockCursor != 0 {
+		t.Fatalf("j in details should select the first fold, cursor=%d block=%d", m.cursor, m.blockCursor)
+	}
+	m = pressKeys(m, "enter")
+	if view := stripRenderANSI(m.View()); strings.Contains(view, "volume_size") {
+		t.Errorf("enter should collapse the selected fold:\n%s", view)
+	}
+	m = pressKeys(m, "k", "h")
+	if m.splitFocus != listPane {
+		t.Error("h without a fold selected should return to the list")
+	}
+
+	before, _ := m.splitPaneWidths()
+	m = pressKeys(m, ">", ">")
+	after, _ := m.splitPaneWidths()
+	if after <= before {
+		t.Errorf("> should widen the list pane, %d -> %d", before, after)
+	}
+	for range 20 {
+		m = pressKeys(m, "<")
+	}
+	if m.splitPercent != minSplitPercent {
+		t.Errorf("split width should clamp at %d%%, got %d", minSplitPercent, m.splitPercent)
+	}
+
+	m = pressKeys(m, "|")
+	if m.split || !strings.Contains(stripRenderANSI(m.View()), "aws_instance.web") {
+		t.Error("| should return to the single-pane layout")
+	}
+}
+
+func TestSplitPaneMouseWheelScrollsDetails(t *testing.T) {
+	raw := []string{`  + resource "aws_s3_bucket" "logs" {`}
+	for i := range 60 {
+		raw = append(raw, fmt.Sprintf(`      + tag_%02d = "v"`, i))
+	}
+	raw = append(raw, `    }`)
+	m := NewModel(&parser.Plan{Resources: []parser.Resource{
+		{Address: "aws_s3_bucket.logs", Type: "aws_s3_bucket", Action: parser.ActionCreate, RawLines: raw},
+	}}, "test")
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	m = updated.(Model)
+	m, _, _ = handleKeyToggleSplit(m)
+
+	updated, _ = m.Update(tea.MouseMsg{X: 100, Button: tea.MouseButtonWheelDown, Action: tea.MouseActionPress})
+	m = updated.(Model)
+	if m.detailViewport.YOffset == 0 {
+		t.Error("the mouse wheel should scroll the detail pane")
+	}
+}
+
+func TestSplitPaneMouseWheelMovesListCursor(t *testing.T) {
+	m := splitTestModel(t)
+	updated, _ := m.Update(tea.MouseMsg{X: 5, Button: tea.MouseButtonWheelDown, Action: tea.MouseActionPress})
+	m = updated.(Model)
+	if m.cursor != 1 {
+		t.Errorf("cursor = %d, want the wheel over the list to move it to 1", m.cursor)
+	}
+}
@@ -19,6 +19,7 @@ var (
 	mutedColorVal lipgloss.Color
 	textColor     lipgloss.Color
 	computedColor lipgloss.Color
+	constantColor lipgloss.Color
 )
 
 // Catppuccin Mocha (Dark) palette
@@ -30,6 +31,7 @@ var darkPalette = map[string]string{
 	"sapphire": "#74c7ec",
 	"blue":     "#89b4fa",
 	"teal":     "#94e2d5",
+	"peach":    "#fab387",
 	"text":     "#cdd6f4",
 	"subtext":  "#a6adc8",
 	"overlay":  "#7f849c",
@@ -48,6 +50,7 @@ var lightPalette = map[string]string{
 	"sapphire": "#209fb5",
 	"blue":     "#1e66f5",
 	"teal":     "#179299",
+	"peach":    "#fe640b",
 	"text":     "#4c4f69",
 	"subtext":  "#6c6f85",
 	"overlay":  "#8c8fa1",
@@ -57,6 +60,9 @@ var lightPalette = map[string]string{
 	"base":     "#eff1f5",
 }
 
+// activePalette is the palette last applied, used by the HTML export.
+var activePalette = darkPalette
+
 // IsLightBackground returns true if terminal has a light background
 func IsLightBackground() bool {
 	return !termenv.HasDarkBackground()
@@ -79,6 +85,7 @@ func InitColors() {
 
 // SetDarkPalette sets colors for dark backgrounds (Catppuccin Mocha)
 func SetDarkPalette() {
+	activePalette = darkPalette
 	createColor = lipgloss.Color(darkPalette["green"])
 	destroyColor = lipgloss.Color(darkPalette["red"])
 	updateColor = lipgloss.Color(darkPalette["yellow"])
@@ -89,11 +96,13 @@ func SetDarkPalette() {
 	mutedColorVal = lipgloss.Color(darkPalette["overlay"])
 	textColor = lipgloss.Color(darkPalette["text"])
 	computedColor = lipgloss.Color(darkPalette["teal"])
+	constantColor = lipgloss.Color(darkPalette["peach"])
 	initStyles() // Reinitialize styles with new colors
 }
 
 // SetLightPalette sets colors for light backgrounds (Catppuccin Latte)
 func SetLightPalette() {
+	activePalette = lightPalette
 	createColor = lipgloss.Color(lightPalette["green"])
 	destroyColor = lipgloss.Color(lightPalette["red"])
 	updateColor = lipgloss.Color(lightPalette["yellow"])
@@ -104,6 +113,7 @@ func SetLightPalette() {
 	mutedColorVal = lipgloss.Color(lightPalette["overlay"])
 	textColor = lipgloss.Color(lightPalette["text"])
 	computedColor = lipgloss.Color(lightPalette["teal"])
+	constantColor = lipgloss.Color(lightPalette["peach"])
 	initStyles() // Reinitialize styles with new colors
 }
 
@@ -121,10 +131,19 @@ var (
 	attrOldValueStyle    lipgloss.Style
 	attrNewValueStyle    lipgloss.Style
 	attrComputedStyle    lipgloss.Style
+	attrOldEmphStyle     lipgloss.Style
+	attrNewEmphStyle     lipgloss.Style
 	mutedColor           lipgloss.Style
 	helpStyle            lipgloss.Style
 	searchStyle          lipgloss.Style
 	matchStyle           lipgloss.Style
+	currentMatchStyle    lipgloss.Style
+	syntaxKeyStyle       lipgloss.Style
+	syntaxStringStyle    lipgloss.Style
+	syntaxNumberStyle    lipgloss.Style
+	syntaxKeywordStyle   lipgloss.Style
+	syntaxVariableStyle  lipgloss.Style
+	syntaxCommentStyle   lipgloss.Style
 )
 
 // Action symbols - set after colors
@@ -138,7 +157,13 @@ var (
 	collapsedIndicator string
 )
 
+// styleVersion changes whenever the styles are rebuilt, so cached renders
+// made with the previous palette are discarded.
+var styleVersion int
+
 func initStyles() {
+	styleVersion++
+
 	// App container
 	appStyle = lipgloss.NewStyle().
 		Padding(1, 2)
@@ -190,6 +215,33 @@ func initStyles() {
 		Foreground(computedColor).
 		Italic(true)
 
+	// Intraline emphasis for the changed part of an old → new pair
+	attrOldEmphStyle = lipgloss.NewStyle().
+		Foreground(destroyColor).
+		Background(selectedBg).
+		Bold(true).
+		Strikethrough(true)
+
+	attrNewEmphStyle = lipgloss.NewStyle().
+		Foreground(createColor).
+		Background(selectedBg).
+		Bold(true)
+
+	// Without colors the emphasis is invisible, so mark the changed part
+	// git word-diff style instead
+	if plainOutput() {
+		attrOldEmphStyle = attrOldEmphStyle.Transform(func(s string) string { return "[-" + s + "-]" })
+		attrNewEmphStyle = attrNewEmphStyle.Transform(func(s string) string { return "{+" + s + "+}" })
+	}
+
+	// Heredoc syntax highlighting
+	syntaxKeyStyle = lipgloss.NewStyle().Foreground(headerColor)
+	syntaxStringStyle = lipgloss.NewStyle().Foreground(computedColor)
+	syntaxNumberStyle = lipgloss.NewStyle().Foreground(constantColor)
+	syntaxKeywordStyle = lipgloss.NewStyle().Foreground(replaceColor)
+	syntaxVariableStyle = lipgloss.NewStyle().Foreground(readColor)
+	syntaxCommentStyle = lipgloss.NewStyle().Foreground(mutedColorVal).Italic(true)
+
 	// Muted style for general muted text
 	mutedColor = lipgloss.NewStyle().
 		Foreground(mutedColorVal)
@@ -220,6 +272,12 @@ func initStyles() {
 		Background(selectedBg).
 		Foreground(createColor).
 		Bold(true)
+
+	// Current content search occurrence
+	currentMatchStyle = lipgloss.NewStyle().
+		Foreground(constantColor).
+		Reverse(true).
+		Bold(true)
 }
 
 // GetActionSymbol returns the appropriate symbol for an action
@@ -0,0 +1,355 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+// SuppressAction is what a suppression rule does with the lines it matches.
+type SuppressAction string
+
+const (
+	// SuppressHide removes matching attributes (or whole resources) from the view.
+	SuppressHide SuppressAction = "hide"
+	// SuppressDim renders matching attributes (or resource lines) muted.
+	SuppressDim SuppressAction = "dim"
+	// SuppressCollapse folds matching blocks by default.
+	SuppressCollapse SuppressAction = "collapse"
+)
+
+// SuppressRule matches resources by type or address and, optionally, an
+// attribute path within them. Resource is a glob matched against both the
+// resource type and its address; an empty Resource matches every resource.
+// Attribute is a dotted path whose segments are globs, "**" matches any
+// number of segments, and a rule for a block also covers everything inside
+// it. A rule without Attribute applies to the resource as a whole.
+type SuppressRule struct {
+	Resource  string         `yaml:"resource"`
+	Attribute string         `yaml:"attribute"`
+	Action    SuppressAction `yaml:"action"`
+}
+
+type suppressConfig struct {
+	Rules []SuppressRule `yaml:"rules"`
+}
+
+// suppressRules are the active rules; the first matching rule wins.
+var suppressRules []SuppressRule
+
+// SetSuppressRules installs the noise-suppression rules used by the TUI.
+func SetSuppressRules(rules []SuppressRule) {
+	suppressRules = rules
+}
+
+// DefaultSuppressRulesPath returns ~/.terraprism/suppress.yaml.
+func DefaultSuppressRulesPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".terraprism", "suppress.yaml"), nil
+}
+
+// LoadSuppressRules reads and validates a suppression rules file:
+//
+//	rules:
+//	  - resource: helm_release
+//	    attribute: metadata
+//	    action: hide
+//	  - attribute: "**.etag"
+//	    action: dim
+//
+// The error wraps fs.ErrNotExist when the file is missing.
+func LoadSuppressRules(file string) ([]SuppressRule, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var cfg suppressConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	for i, rule := range cfg.Rules {
+		if err := validateSuppressRule(rule); err != nil {
+			return nil, fmt.Errorf("%s: rule %d: %w", file, i+1, err)
+		}
+	}
+	return cfg.Rules, nil
+}
+
+func validateSuppressRule(rule SuppressRule) error {
+	switch rule.Action {
+	case SuppressHide, SuppressDim:
+	case SuppressCollapse:
+		if rule.Attribute == "" {
+			return fmt.Errorf("action %q needs an attribute", rule.Action)
+		}
+	default:
+		return fmt.Errorf("unknown action %q (want hide, dim or collapse)", rule.Action)
+	}
+	if rule.Resource == "" && rule.Attribute == "" {
+		return fmt.Errorf("rule needs a resource or an attribute")
+	}
+	if _, err := path.Match(rule.Resource, ""); err != nil {
+		return fmt.Errorf("resource pattern %q: %w", rule.Resource, err)
+	}
+	for _, segment := range strings.Split(rule.Attribute, ".") {
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("attribute pattern %q: %w", rule.Attribute, err)
+		}
+	}
+	return nil
+}
+
+// suppressActionFor returns the action of the first rule matching r and
+// attrPath, or "" when none does. An empty attrPath looks up resource-level
+// rules only.
+func suppressActionFor(r parser.Resource, attrPath string) SuppressAction {
+	for _, rule := range suppressRules {
+		if (rule.Attribute == "") != (attrPath == "") {
+			continue
+		}
+		if !matchSuppressResource(rule.Resource, r) {
+			continue
+		}
+		if attrPath != "" && !matchAttrPathSegments(strings.Split(rule.Attribute, "."), strings.Split(attrPath, ".")) {
+			continue
+		}
+		return rule.Action
+	}
+	return ""
+}
+
+func matchSuppressResource(pattern string, r parser.Resource) bool {
+	if pattern == "" {
+		return true
+	}
+	if ok, _ := path.Match(pattern, r.Type); ok {
+		return true
+	}
+	ok, _ := path.Match(pattern, r.Address)
+	return ok
+}
+
+// matchAttrPathSegments reports whether pattern matches segments or one of
+// their ancestors, so a rule for a block covers its children.
+func matchAttrPathSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return true
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchAttrPathSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	ok, _ := path.Match(pattern[0], segments[0])
+	return ok && matchAttrPathSegments(pattern[1:], segments[1:])
+}
+
+// lineAttrPaths returns the dotted attribute path of every plan body line.
+// Block openers and their closing lines carry the block's path, list items
+// share the path of their list, and heredoc bodies that of their attribute.
+func lineAttrPaths(lines []string) []string {
+	paths := make([]string, len(lines))
+	var stack []string
+	heredocEnd, heredocPath := "", ""
+	for i, line := range lines {
+		parent := ""
+		if len(stack) > 0 {
+			parent = stack[len(stack)-1]
+		}
+		trimmed := strings.TrimLeft(line, " \t")
+
+		if heredocEnd != "" {
+			paths[i] = heredocPath
+			if isHeredocEndLine(strings.TrimSpace(stripDiffPrefix(trimmed)), heredocEnd) {
+				heredocEnd = ""
+			}
+			continue
+		}
+
+		content := parser.StripPlanComment(strings.TrimSpace(stripDiffPrefix(trimmed)))
+		if content == "" {
+			paths[i] = parent
+			continue
+		}
+		if strings.ContainsAny(content[:1], "}])") {
+			paths[i] = parent
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			continue
+		}
+
+		full := parent
+		if key := planLineKey(content); key != "" {
+			full = joinAttrPath(parent, key)
+		}
+		paths[i] = full
+		if marker := parseHeredocMarkerFromLine(line); marker != "" {
+			heredocEnd, heredocPath = marker, full
+			continue
+		}
+		if last := content[len(content)-1]; last == '{' || last == '[' || last == '(' {
+			stack = append(stack, full)
+		}
+	}
+	return paths
+}
+
+// planLineKey returns the attribute or block name a plan line declares, e.g.
+// "tags" for `tags = {` and "ingress" for `ingress {`.
+func planLineKey(content string) string {
+	key, _, found := strings.Cut(content, "=")
+	if !found {
+		name, _, isBlock := strings.Cut(content, " ")
+		if !isBlock || !strings.HasSuffix(content, "{") {
+			return ""
+		}
+		key = name
+	}
+	key = unquote(strings.TrimSpace(key))
+	if key == "" || strings.ContainsAny(key, " {[\"") {
+		return ""
+	}
+	return key
+}
+
+func joinAttrPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// suppressionActive reports whether suppression rules apply to the view.
+func (m Model) suppressionActive() bool {
+	return len(suppressRules) > 0 && !m.showSuppressed
+}
+
+// expandedLines returns the body lines of r as rendered, with hidden
+// attributes removed, plus the attribute path of each remaining line. paths
+// is nil when suppression is inactive.
+func (m Model) expandedLines(r parser.Resource) (lines, paths []string) {
+	lines = r.RawLines[1:]
+	if !m.suppressionActive() {
+		return lines, nil
+	}
+	lines, paths, _ = suppressLines(r, lines)
+	return lines, paths
+}
+
+// suppressLines drops the lines matched by hide rules and returns the kept
+// lines, their attribute paths and the number of lines dropped.
+func suppressLines(r parser.Resource, lines []string) (kept, keptPaths []string, hidden int) {
+	paths := lineAttrPaths(lines)
+	for i, line := range lines {
+		if paths[i] != "" && suppressActionFor(r, paths[i]) == SuppressHide {
+			hidden++
+			continue
+		}
+		kept = append(kept, line)
+		keptPaths = append(keptPaths, paths[i])
+	}
+	return kept, keptPaths, hidden
+}
+
+// markSuppressedFolds flags folds that a collapse rule closes by default.
+func markSuppressedFolds(r parser.Resource, blocks []foldBlock, lines []string) {
+	if len(suppressRules) == 0 {
+		return
+	}
+	paths := lineAttrPaths(lines)
+	for i := range blocks {
+		if p := paths[blocks[i].Start]; p != "" && suppressActionFor(r, p) == SuppressCollapse {
+			blocks[i].Suppressed = true
+		}
+	}
+}
+
+// isDimmedLine reports whether the line at idx of expandedLines is dimmed.
+func isDimmedLine(r parser.Resource, paths []string, idx int) bool {
+	return paths != nil && paths[idx] != "" && suppressActionFor(r, paths[idx]) == SuppressDim
+}
+
+// renderDimmedLine renders a plan line muted, keeping its wrapping.
+func renderDimmedLine(line string, maxWidth int) string {
+	indent := extractIndent(line)
+	wrapped := strings.Split(wrapText(strings.TrimLeft(line, " \t"), maxWidth-len(indent)), "\n")
+	for i, part := range wrapped {
+		wrapped[i] = indent + mutedColor.Render(part)
+	}
+	return strings.Join(wrapped, "\n")
+}
+
+// countSuppressed records how many resources and body lines the rules hide
+// or fold away across the whole plan. It walks every resource body, so it runs
+// when the plan is loaded or replaced rather than on every View.
+func (m *Model) countSuppressed() {
+	resources, lines := 0, 0
+	for _, r := range m.plan.Resources {
+		if suppressActionFor(r, "") == SuppressHide {
+			resources++
+			continue
+		}
+		if len(r.RawLines) <= 1 {
+			continue
+		}
+		kept, _, hidden := suppressLines(r, r.RawLines[1:])
+		lines += hidden
+		for _, block := range findFoldBlocks(r, kept) {
+			if block.Suppressed {
+				lines += block.LineCount
+			}
+		}
+	}
+	m.suppressedResources, m.suppressedLines = resources, lines
+}
+
+func handleKeyToggleSuppression(m Model) (Model, tea.Cmd, bool) {
+	if len(suppressRules) == 0 {
+		return m, nil, true
+	}
+	m.showSuppressed = !m.showSuppressed
+	m.blockCursor = -1
+	m.clampCursorAndRefreshSearch()
+	m.updateViewportContent()
+	return m, nil, true
+}
+
+// viewSuppressionStatus renders the suppressed-lines indicator when rules
+// are loaded.
+func (m Model) viewSuppressionStatus() string {
+	if len(suppressRules) == 0 {
+		return ""
+	}
+	if m.showSuppressed {
+		return mutedColor.Render("Suppression rules off • z: re-enable") + "\n\n"
+	}
+	resources, lines := m.suppressedResources, m.suppressedLines
+	if resources == 0 && lines == 0 {
+		return ""
+	}
+	var parts []string
+	if lines > 0 {
+		parts = append(parts, fmt.Sprintf("%d line(s)", lines))
+	}
+	if resources > 0 {
+		parts = append(parts, fmt.Sprintf("%d resource(s)", resources))
+	}
+	return mutedColor.Render(fmt.Sprintf("Suppressed %s by rule • z: show", strings.Join(parts, ", "))) + "\n\n"
+}
@@ -0,0 +1,168 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+func useSuppressRules(t *testing.T, rules ...SuppressRule) {
+	t.Helper()
+	orig := suppressRules
+	SetSuppressRules(rules)
+	t.Cleanup(func() { SetSuppressRules(orig) })
+}
+
+var helmReleaseUpdate = parser.Resource{
+	Address: "helm_release.app",
+	Type:    "helm_release",
+	Action:  parser.ActionUpdate,
+	RawLines: []string{
+		`  ~ resource "helm_release" "app" {`,
+		`        id       = "app"`,
+		`      ~ metadata = [`,
+		`          - {`,
+		`              - revision = 3`,
+		`            },`,
+		`        ] -> (known after apply)`,
+		`      ~ version  = "1.0.0" -> "1.1.0"`,
+		`      ~ etag     = "abc" -> "def"`,
+		`      ~ set {`,
+		`          ~ value = "a" -> "b"`,
+		`            name  = "image.tag"`,
+		`        }`,
+		`    }`,
+	},
+}
+
+func TestLineAttrPaths(t *testing.T) {
+	got := lineAttrPaths(helmReleaseUpdate.RawLines[1:])
+	want := []string{
+		"id",
+		"metadata", "metadata", "metadata.revision", "metadata", "metadata",
+		"version",
+		"etag",
+		"set", "set.value", "set.name", "set",
+		"",
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("lineAttrPaths =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestMatchAttrPathSegments(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"metadata", "metadata", true},
+		{"metadata", "metadata.revision", true},
+		{"metadata", "spec.metadata", false},
+		{"**.etag", "etag", true},
+		{"**.etag", "bucket.object.etag", true},
+		{"set.val*", "set.value", true},
+		{"set.value", "set.name", false},
+	}
+	for _, tt := range tests {
+		got := matchAttrPathSegments(strings.Split(tt.pattern, "."), strings.Split(tt.path, "."))
+		if got != tt.want {
+			t.Errorf("match(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestLoadSuppressRules(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.yaml")
+	os.WriteFile(valid, []byte("rules:\n  - resource: helm_release\n    attribute: metadata\n    action: hide\n  - attribute: \"**.etag\"\n    action: dim\n"), 0644)
+	rules, err := LoadSuppressRules(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 2 || rules[0].Action != SuppressHide || rules[1].Attribute != "**.etag" {
+		t.Errorf("unexpected rules %+v", rules)
+	}
+
+	for name, body := range map[string]string{
+		"unknown action":     "rules:\n  - attribute: etag\n    action: drop\n",
+		"resource collapse":  "rules:\n  - resource: helm_release\n    action: collapse\n",
+		"empty rule":         "rules:\n  - action: hide\n",
+		"bad resource glob":  "rules:\n  - resource: \"[\"\n    action: hide\n",
+		"bad attribute glob": "rules:\n  - attribute: \"a.[\"\n    action: dim\n",
+	} {
+		file := filepath.Join(dir, "invalid.yaml")
+		os.WriteFile(file, []byte(body), 0644)
+		if _, err := LoadSuppressRules(file); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestSuppressionRulesRender(t *testing.T) {
+	useSuppressRules(t,
+		SuppressRule{Resource: "helm_release", Attribute: "metadata", Action: SuppressHide},
+		SuppressRule{Attribute: "**.etag", Action: SuppressDim},
+		SuppressRule{Resource: "helm_release", Attribute: "set", Action: SuppressCollapse},
+	)
+	plan := &parser.Plan{Resources: []parser.Resource{helmReleaseUpdate}}
+	m := NewModel(plan, "test")
+	m.viewport.Width = 120
+	m.expanded[0] = true
+
+	render := func() string {
+		var b strings.Builder
+		lineCount := 0
+		m.renderExpandedContent(&b, helmReleaseUpdate, false, &lineCount)
+		return stripRenderANSI(b.String())
+	}
+
+	got := render()
+	if strings.Contains(got, "metadata") || strings.Contains(got, "revision") {
+		t.Errorf("hidden metadata should not render:\n%s", got)
+	}
+	if !strings.Contains(got, "▶ ~ set {") || strings.Contains(got, "image.tag") {
+		t.Errorf("set block should be collapsed by rule:\n%s", got)
+	}
+	if !strings.Contains(got, `~ etag     = "abc" -> "def"`) {
+		t.Errorf("dimmed etag should still render:\n%s", got)
+	}
+	if status := stripRenderANSI(m.viewSuppressionStatus()); !strings.Contains(status, "Suppressed 8 line(s) by rule • z: show") {
+		t.Errorf("unexpected status %q", status)
+	}
+
+	m, _, _ = handleKeyToggleSuppression(m)
+	got = render()
+	if !strings.Contains(got, "revision") || !strings.Contains(got, "image.tag") {
+		t.Errorf("z should show suppressed lines:\n%s", got)
+	}
+	if status := stripRenderANSI(m.viewSuppressionStatus()); !strings.Contains(status, "Suppression rules off") {
+		t.Errorf("unexpected status %q", status)
+	}
+}
+
+func TestSuppressionHidesResources(t *testing.T) {
+	useSuppressRules(t, SuppressRule{Resource: "helm_release.*", Action: SuppressHide})
+	plan := &parser.Plan{Resources: []parser.Resource{
+		helmReleaseUpdate,
+		{Address: "aws_instance.web", Type: "aws_instance", Action: parser.ActionCreate},
+	}}
+	m := NewModel(plan, "test")
+	if got := m.filteredResources(); len(got) != 1 || got[0] != 1 {
+		t.Fatalf("expected helm_release to be hidden, got %v", got)
+	}
+	if status := stripRenderANSI(m.viewSuppressionStatus()); !strings.Contains(status, "Suppressed 1 resource(s) by rule") {
+		t.Errorf("unexpected status %q", status)
+	}
+	m.replacePlan(&parser.Plan{Resources: plan.Resources[1:]})
+	if status := m.viewSuppressionStatus(); status != "" {
+		t.Errorf("counts should follow a replaced plan, got %q", stripRenderANSI(status))
+	}
+	m.replacePlan(plan)
+	m, _, _ = handleKeyToggleSuppression(m)
+	if len(m.filteredResources()) != 2 {
+		t.Fatal("z should show suppressed resources")
+	}
+}
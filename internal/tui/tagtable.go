@@ -0,0 +1,353 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+// tagMapAttrs are the map attributes rendered as a key | old | new table.
+var tagMapAttrs = map[string]bool{
+	"tags":     true,
+	"tags_all": true,
+	"labels":   true,
+}
+
+// tagOp is how a single tag changes.
+type tagOp int
+
+const (
+	tagUnchanged tagOp = iota
+	tagChanged
+	tagAdded
+	tagRemoved
+)
+
+// tagRow is one entry of a tag map change. Old is empty for added tags and
+// New is empty for removed ones.
+type tagRow struct {
+	Op  tagOp
+	Key string
+	Old string
+	New string
+}
+
+// tagTable is the parsed body of a tags/tags_all/labels map.
+type tagTable struct {
+	Attr    string
+	Rows    []tagRow
+	Hidden  int // "# (N unchanged elements hidden)"
+	Derived bool
+	Extra   []tagRow // for a derived tags_all: rows not present in tags
+}
+
+var (
+	tagEntryPattern  = regexp.MustCompile(`^("(?:[^"\\]|\\.)*"|[A-Za-z0-9_.:/@-]+)\s*=\s*(.*?),?$`)
+	tagHiddenPattern = regexp.MustCompile(`^# \((\d+) unchanged elements? hidden\)$`)
+)
+
+// tagMapAttr returns the attribute name when line opens a tag map, e.g.
+// `~ tags = {`.
+func tagMapAttr(line string) string {
+	content := strings.TrimSpace(stripDiffPrefix(strings.TrimLeft(line, " \t")))
+	key, rest, found := strings.Cut(content, "=")
+	if !found || strings.TrimSpace(rest) != "{" {
+		return ""
+	}
+	key = strings.TrimSpace(key)
+	if !tagMapAttrs[key] {
+		return ""
+	}
+	return key
+}
+
+// markTagTable parses a tag map fold into a table. Maps with nested values
+// or lines it does not recognise are left as ordinary folds.
+func markTagTable(block *foldBlock, lines []string) {
+	attr := tagMapAttr(lines[block.Start])
+	if attr == "" {
+		return
+	}
+	table := &tagTable{Attr: attr}
+	for _, line := range lines[block.Start+1 : block.End-1] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" {
+			continue
+		}
+		if m := tagHiddenPattern.FindStringSubmatch(trimmed); m != nil {
+			fmt.Sscanf(m[1], "%d", &table.Hidden)
+			continue
+		}
+		row, ok := parseTagRow(trimmed)
+		if !ok {
+			return
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	block.TagTable = table
+}
+
+func parseTagRow(trimmed string) (tagRow, bool) {
+	op := tagUnchanged
+	content := trimmed
+	if hasDiffPrefix(trimmed) {
+		switch trimmed[0] {
+		case '+':
+			op = tagAdded
+		case '-':
+			op = tagRemoved
+		case '~':
+			op = tagChanged
+		}
+		content = trimmed[2:]
+	}
+	m := tagEntryPattern.FindStringSubmatch(content)
+	if m == nil {
+		return tagRow{}, false
+	}
+	value := strings.TrimSpace(m[2])
+	if strings.HasSuffix(value, "{") || strings.HasSuffix(value, "[") {
+		return tagRow{}, false
+	}
+	row := tagRow{Op: op, Key: unquote(m[1])}
+	oldVal, newVal, arrow := strings.Cut(value, " -> ")
+	switch {
+	case arrow:
+		row.Old, row.New = tagValue(oldVal), tagValue(newVal)
+	case op == tagRemoved:
+		row.Old = tagValue(value)
+	default:
+		row.New = tagValue(value)
+		if op == tagUnchanged {
+			row.Old = row.New
+		}
+	}
+	return row, true
+}
+
+// tagValue strips quotes from simple string values; "null" means absent.
+func tagValue(v string) string {
+	v = strings.TrimSpace(v)
+	if idx := strings.Index(v, " # "); idx >= 0 {
+		v = strings.TrimSpace(v[:idx])
+	}
+	if v == "null" {
+		return ""
+	}
+	return unquote(v)
+}
+
+// linkDerivedTagTables marks a tags_all table as derived when every change in
+// the sibling tags map appears identically in it. Only the remaining rows
+// (provider default tags) are shown for a derived table.
+func linkDerivedTagTables(blocks []foldBlock, lines []string) {
+	for i := range blocks {
+		all := blocks[i].TagTable
+		if all == nil || all.Attr != "tags_all" {
+			continue
+		}
+		indent := extractIndent(lines[blocks[i].Start])
+		for j := i - 1; j >= 0; j-- {
+			tags := blocks[j].TagTable
+			if tags == nil || tags.Attr != "tags" || extractIndent(lines[blocks[j].Start]) != indent {
+				continue
+			}
+			if extra, ok := tagRowsExtra(tags.Rows, all.Rows); ok {
+				all.Derived = true
+				all.Extra = extra
+			}
+			break
+		}
+	}
+}
+
+// tagRowsExtra returns the rows of all that are not in tags, or false when a
+// tags row is missing from all or differs.
+func tagRowsExtra(tags, all []tagRow) ([]tagRow, bool) {
+	byKey := make(map[string]tagRow, len(all))
+	for _, row := range all {
+		byKey[row.Key] = row
+	}
+	inTags := make(map[string]bool, len(tags))
+	for _, row := range tags {
+		inTags[row.Key] = true
+		if byKey[row.Key] != row {
+			return nil, false
+		}
+	}
+	var extra []tagRow
+	for _, row := range all {
+		if !inTags[row.Key] {
+			extra = append(extra, row)
+		}
+	}
+	return extra, true
+}
+
+// tagTableSummary describes a tag table for its fold header.
+func tagTableSummary(t *tagTable) string {
+	if t.Derived {
+		if len(t.Extra) == 0 {
+			return "same as tags"
+		}
+		return fmt.Sprintf("same as tags + %d provider default tag change(s)", len(t.Extra))
+	}
+	var changed, added, removed int
+	for _, row := range t.Rows {
+		switch row.Op {
+		case tagChanged:
+			changed++
+		case tagAdded:
+			added++
+		case tagRemoved:
+			removed++
+		}
+	}
+	var parts []string
+	for _, p := range []struct {
+		n     int
+		label string
+	}{{changed, "changed"}, {added, "added"}, {removed, "removed"}} {
+		if p.n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", p.n, p.label))
+		}
+	}
+	if t.Hidden > 0 {
+		parts = append(parts, fmt.Sprintf("%d unchanged", t.Hidden))
+	}
+	if len(parts) == 0 {
+		return fmt.Sprintf("%d tag(s)", len(t.Rows))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// renderTagTable renders a tag map as an aligned key | old | new table.
+func renderTagTable(t *tagTable, indent string, maxWidth int) string {
+	rows := t.Rows
+	if t.Derived {
+		rows = t.Extra
+	}
+	if len(rows) == 0 {
+		return ""
+	}
+
+	keyWidth, oldWidth := len("key"), len("old")
+	for _, row := range rows {
+		keyWidth = max(keyWidth, lipgloss.Width(row.Key))
+		oldWidth = max(oldWidth, lipgloss.Width(row.Old))
+	}
+	// Leave room for the prefix and separators; long values are truncated.
+	if avail := maxWidth - len(indent) - 10; avail > 20 {
+		keyWidth = min(keyWidth, avail/3)
+		oldWidth = min(oldWidth, (avail-keyWidth)/2)
+	}
+	newWidth := 0
+	if avail := maxWidth - len(indent) - 10 - keyWidth - oldWidth; avail > 0 {
+		newWidth = avail
+	}
+
+	sep := mutedColor.Render(" │ ")
+	var b strings.Builder
+	b.WriteString(indent + "    " + mutedColor.Render(padCell("key", keyWidth)) + sep + mutedColor.Render(padCell("old", oldWidth)) + sep + mutedColor.Render("new"))
+	b.WriteString("\n")
+	for _, row := range rows {
+		symbol, oldStyle, newStyle := " ", mutedColor, mutedColor
+		switch row.Op {
+		case tagChanged:
+			symbol = updateSymbol
+			oldStyle = lipgloss.NewStyle().Foreground(destroyColor)
+			newStyle = lipgloss.NewStyle().Foreground(createColor)
+		case tagAdded:
+			symbol = createSymbol
+			newStyle = lipgloss.NewStyle().Foreground(createColor)
+		case tagRemoved:
+			symbol = destroySymbol
+			oldStyle = lipgloss.NewStyle().Foreground(destroyColor)
+		}
+		newCell := row.New
+		if newWidth > 0 {
+			newCell = truncateCell(newCell, newWidth)
+		}
+		b.WriteString(indent + "  " + symbol + " " + attrNameStyle.Render(padCell(truncateCell(row.Key, keyWidth), keyWidth)) + sep +
+			oldStyle.Render(padCell(truncateCell(row.Old, oldWidth), oldWidth)) + sep + newStyle.Render(newCell))
+		b.WriteString("\n")
+	}
+	if t.Hidden > 0 && !t.Derived {
+		b.WriteString(indent + "    " + mutedColor.Render(fmt.Sprintf("(%d unchanged hidden)", t.Hidden)))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// truncateCell shortens s to width columns with a trailing ellipsis.
+func truncateCell(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	r := []rune(s)
+	if width < 1 || len(r) < width {
+		return s
+	}
+	return string(r[:width-1]) + "…"
+}
+
+// isTagsOnlyChange reports whether an in-place update only touches tag maps.
+func isTagsOnlyChange(r parser.Resource) bool {
+	if r.Action != parser.ActionUpdate {
+		return false
+	}
+	tagChanges := 0
+	for _, attr := range r.Attributes {
+		path, _, _, ok := pivotAttrValues(attr)
+		if !ok {
+			continue // nested block or map container; its entries decide
+		}
+		if !isTagPath(path) {
+			return false
+		}
+		tagChanges++
+	}
+	return tagChanges > 0
+}
+
+func isTagPath(path string) bool {
+	for _, segment := range strings.Split(path, ".") {
+		if tagMapAttrs[segment] {
+			return true
+		}
+	}
+	return false
+}
+
+// countTagsOnly records how many resources the tags-only toggle hides, so the
+// status line doesn't classify the whole plan on every render. Call it
+// whenever the plan changes.
+func (m *Model) countTagsOnly() {
+	n := 0
+	for _, r := range m.plan.Resources {
+		if isTagsOnlyChange(r) {
+			n++
+		}
+	}
+	m.tagsOnlyResources = n
+}
+
+func handleKeyToggleTagsOnly(m Model) (Model, tea.Cmd, bool) {
+	m.hideTagsOnly = !m.hideTagsOnly
+	m.clampCursorAndRefreshSearch()
+	m.updateViewportContent()
+	return m, nil, true
+}
+
+// viewTagsOnlyStatus renders the status line while tags-only changes are hidden.
+func (m Model) viewTagsOnlyStatus() string {
+	if !m.hideTagsOnly {
+		return ""
+	}
+	return searchStyle.Render(fmt.Sprintf("Hiding %d tags-only change(s) • T: show", m.tagsOnlyResources)) + "\n\n"
+}
@@ -0,0 +1,146 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+var tagChangeLines = []string{
+	`        id            = "i-123"`,
+	`      ~ tags          = {`,
+	`          ~ "Env"  = "dev" -> "prod"`,
+	`          + "Team" = "platform"`,
+	`          - "Old"  = "x" -> null`,
+	`            # (1 unchanged element hidden)`,
+	`        }`,
+	`      ~ tags_all      = {`,
+	`          ~ "Env"       = "dev" -> "prod"`,
+	`          + "Team"      = "platform"`,
+	`          - "Old"       = "x" -> null`,
+	`          ~ "ManagedBy" = "tf" -> "terraform"`,
+	`            # (3 unchanged elements hidden)`,
+	`        }`,
+}
+
+func TestFindFoldBlocksParsesTagTables(t *testing.T) {
+	blocks := findFoldBlocks(parser.Resource{Address: "aws_instance.web"}, tagChangeLines)
+	if len(blocks) != 2 || blocks[0].TagTable == nil || blocks[1].TagTable == nil {
+		t.Fatalf("expected two tag table folds, got %#v", blocks)
+	}
+
+	tags := blocks[0].TagTable
+	want := []tagRow{
+		{Op: tagChanged, Key: "Env", Old: "dev", New: "prod"},
+		{Op: tagAdded, Key: "Team", New: "platform"},
+		{Op: tagRemoved, Key: "Old", Old: "x"},
+	}
+	if len(tags.Rows) != len(want) || tags.Hidden != 1 {
+		t.Fatalf("unexpected tags table %#v", tags)
+	}
+	for i := range want {
+		if tags.Rows[i] != want[i] {
+			t.Errorf("row %d = %#v, want %#v", i, tags.Rows[i], want[i])
+		}
+	}
+	if got := tagTableSummary(tags); got != "1 changed, 1 added, 1 removed, 1 unchanged" {
+		t.Errorf("summary = %q", got)
+	}
+
+	all := blocks[1].TagTable
+	if !all.Derived || len(all.Extra) != 1 || all.Extra[0].Key != "ManagedBy" {
+		t.Fatalf("expected tags_all derived from tags plus ManagedBy, got %#v", all)
+	}
+	m := Model{foldedBlocks: map[string]bool{}}
+	if !m.isFoldCollapsed(blocks[1]) {
+		t.Error("derived tags_all should be collapsed by default")
+	}
+}
+
+func TestTagsAllNotDerivedWhenValuesDiffer(t *testing.T) {
+	lines := []string{
+		`      ~ tags     = {`,
+		`          ~ "Env" = "dev" -> "prod"`,
+		`        }`,
+		`      ~ tags_all = {`,
+		`          ~ "Env" = "dev" -> "stage"`,
+		`        }`,
+	}
+	blocks := findFoldBlocks(parser.Resource{}, lines)
+	if blocks[1].TagTable == nil || blocks[1].TagTable.Derived {
+		t.Fatalf("tags_all with a different value must not be collapsed into tags: %#v", blocks[1].TagTable)
+	}
+}
+
+func TestRenderTagTable(t *testing.T) {
+	got := renderExpandedForTest(parser.Resource{Action: parser.ActionUpdate}, tagChangeLines)
+	for _, want := range []string{
+		"▼ ~ tags          = { (1 changed, 1 added, 1 removed, 1 unchanged)",
+		"key  │ old │ new",
+		"~ Env  │ dev │ prod",
+		"+ Team │     │ platform",
+		"- Old  │ x   │",
+		"(1 unchanged hidden)",
+		"▶ ~ tags_all      = { (same as tags + 1 provider default tag change(s))",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("rendered tag table missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, `"Env"  = "dev" -> "prod"`) {
+		t.Errorf("tag map should render as a table, not raw lines:\n%s", got)
+	}
+}
+
+func TestIsTagsOnlyChange(t *testing.T) {
+	tagsOnly := parser.Resource{Action: parser.ActionUpdate, Attributes: []parser.Attribute{
+		{Name: "tags", Path: "tags", NewValue: "{", Action: parser.ActionUpdate},
+		{Name: "Env", Path: "tags.Env", OldValue: `"dev"`, NewValue: `"prod"`, Action: parser.ActionUpdate},
+		{Name: "Env", Path: "tags_all.Env", OldValue: `"dev"`, NewValue: `"prod"`, Action: parser.ActionUpdate},
+		{Name: "root_block_device", Path: "root_block_device", NewValue: "{", Action: parser.ActionUpdate},
+		{Name: "team", Path: "root_block_device.tags.team", NewValue: `"x"`, Action: parser.ActionCreate},
+	}}
+	if !isTagsOnlyChange(tagsOnly) {
+		t.Error("expected tags-only change")
+	}
+
+	mixed := tagsOnly
+	mixed.Attributes = append(append([]parser.Attribute{}, tagsOnly.Attributes...),
+		parser.Attribute{Name: "instance_type", Path: "instance_type", OldValue: `"t3.micro"`, NewValue: `"t3.large"`, Action: parser.ActionUpdate})
+	if isTagsOnlyChange(mixed) {
+		t.Error("instance_type change is not tags-only")
+	}
+
+	created := tagsOnly
+	created.Action = parser.ActionCreate
+	if isTagsOnlyChange(created) {
+		t.Error("creates are never tags-only")
+	}
+}
+
+func TestToggleTagsOnlyHidesResources(t *testing.T) {
+	plan := &parser.Plan{Resources: []parser.Resource{
+		{Address: "aws_instance.a", Action: parser.ActionUpdate, Attributes: []parser.Attribute{
+			{Name: "Env", Path: "tags.Env", OldValue: `"a"`, NewValue: `"b"`, Action: parser.ActionUpdate},
+		}},
+		{Address: "aws_instance.b", Action: parser.ActionCreate},
+	}}
+	m := NewModel(plan, "test")
+	m, _, _ = handleKeyToggleTagsOnly(m)
+	if got := m.filteredResources(); len(got) != 1 || got[0] != 1 {
+		t.Fatalf("expected only the create to remain, got %v", got)
+	}
+	if status := stripRenderANSI(m.viewTagsOnlyStatus()); !strings.Contains(status, "Hiding 1 tags-only change(s)") {
+		t.Errorf("unexpected status %q", status)
+	}
+	m.replacePlan(&parser.Plan{Resources: plan.Resources[1:]})
+	if status := stripRenderANSI(m.viewTagsOnlyStatus()); !strings.Contains(status, "Hiding 0 tags-only change(s)") {
+		t.Errorf("a re-run plan should be recounted, got %q", status)
+	}
+	m.replacePlan(plan)
+	m, _, _ = handleKeyToggleTagsOnly(m)
+	if len(m.filteredResources()) != 2 {
+		t.Fatal("toggling again should show tags-only changes")
+	}
+}
@@ -0,0 +1,142 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+// PlanRunStartedMsg is sent in watch mode when a configuration change starts
+// a new plan run.
+type PlanRunStartedMsg struct {
+	Reason string // e.g. "main.tf changed"
+}
+
+// PlanRefreshedMsg is sent in watch mode when a plan run finishes. Plan is
+// nil when the run failed.
+type PlanRefreshedMsg struct {
+	Plan *parser.Plan
+	Err  error
+	At   time.Time
+}
+
+// WithWatch puts the model in watch mode, showing when the plan last ran.
+// err is the failure of the initial run, if any.
+func (m Model) WithWatch(lastRun time.Time, err error) Model {
+	m.watching = true
+	m.watchLastRun = lastRun
+	m.watchErr = err
+	return m
+}
+
+func (m Model) handlePlanRunStarted(msg PlanRunStartedMsg) Model {
+	m.watchRunning = true
+	m.watchReason = msg.Reason
+	return m
+}
+
+func (m Model) handlePlanRefreshed(msg PlanRefreshedMsg) Model {
+	m.watchRunning = false
+	m.watchLastRun = msg.At
+	m.watchErr = msg.Err
+	if msg.Err == nil && msg.Plan != nil {
+		m.replacePlan(msg.Plan)
+	}
+	return m
+}
+
+// replacePlan swaps in a re-run plan, carrying the cursor, expanded and
+// side-by-side resources and the attribute drill-down over by address.
+// Filters, sort, search and the query apply to the new plan unchanged, and
+// fold state is already keyed by address.
+func (m *Model) replacePlan(plan *parser.Plan) {
+	cursorAddr := ""
+	if idx := m.currentResourceIndex(); idx >= 0 {
+		cursorAddr = m.plan.Resources[idx].Address
+	}
+	expanded := make(map[string]bool)
+	for idx, open := range m.expanded {
+		if open && idx < len(m.plan.Resources) {
+			expanded[m.plan.Resources[idx].Address] = true
+		}
+	}
+	sideBySide := make(map[string]bool)
+	for idx, on := range m.sideBySide {
+		if on && idx < len(m.plan.Resources) {
+			sideBySide[m.plan.Resources[idx].Address] = true
+		}
+	}
+
+	m.plan = plan
+	m.renders = newRenderCache()
+	m.countSuppressed()
+	m.countTagsOnly()
+	m.expanded = make(map[int]bool)
+	m.sideBySide = make(map[int]bool)
+	for idx, r := range plan.Resources {
+		if expanded[r.Address] {
+			m.expanded[idx] = true
+		}
+		if sideBySide[r.Address] {
+			m.sideBySide[idx] = true
+		}
+	}
+
+	if m.attrFilter != "" {
+		m.attrFilterResources = make(map[int]bool)
+		for _, entry := range buildAttrPivot(plan) {
+			if entry.Path != m.attrFilter {
+				continue
+			}
+			for _, idx := range entry.Resources {
+				m.attrFilterResources[idx] = true
+			}
+		}
+	}
+	if m.pivoting {
+		m.pivotEntries = buildAttrPivot(plan)
+		m.pivotCursor = min(m.pivotCursor, max(len(m.pivotEntries)-1, 0))
+	}
+	if m.dashboard {
+		m.dashboardEntries = buildDashboard(plan)
+		m.dashboardCursor = min(m.dashboardCursor, max(len(m.dashboardEntries)-1, 0))
+	}
+
+	m.clampCursorAndRefreshSearch()
+	if cursorAddr != "" {
+		for displayIdx, idx := range m.displayedResourceIndices() {
+			if plan.Resources[idx].Address == cursorAddr {
+				m.cursor = displayIdx
+				break
+			}
+		}
+	}
+	m.updateViewportContent()
+}
+
+// viewWatchStatus renders the watch mode status line.
+func (m Model) viewWatchStatus() string {
+	if !m.watching {
+		return ""
+	}
+	if m.watchRunning {
+		return searchStyle.Render(fmt.Sprintf("Watching • re-running plan (%s)…", m.watchReason)) + "\n\n"
+	}
+	lastRun := "never"
+	if !m.watchLastRun.IsZero() {
+		lastRun = m.watchLastRun.Format("15:04:05")
+	}
+	if m.watchErr != nil {
+		message := strings.TrimSpace(m.watchErr.Error())
+		if i := strings.IndexByte(message, '\n'); i >= 0 {
+			message = message[:i]
+		}
+		errStyle := lipgloss.NewStyle().Foreground(destroyColor).Bold(true)
+		return errStyle.Render(fmt.Sprintf("Watching • plan failed at %s: %s", lastRun, message)) + "\n\n"
+	}
+	return searchStyle.Render(fmt.Sprintf("Watching • last run %s • %d resource(s)", lastRun, len(m.plan.Resources))) + "\n\n"
+}
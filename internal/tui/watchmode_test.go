@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPlanRefreshPreservesStateByAddress(t *testing.T) {
+	previous, current := compareTestPlans(t)
+	m := NewModel(previous, "test").WithWatch(time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC), nil)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 140, Height: 40})
+	m = updated.(Model)
+
+	// Expand aws_iam_role.app (third) and leave the cursor on it.
+	m = pressKeys(m, "j", "j", "l")
+	if m.plan.Resources[m.currentResourceIndex()].Address != "aws_iam_role.app" {
+		t.Fatalf("cursor on %s", m.plan.Resources[m.currentResourceIndex()].Address)
+	}
+
+	updated, _ = m.Update(PlanRunStartedMsg{Reason: "main.tf changed"})
+	m = updated.(Model)
+	if view := stripRenderANSI(m.View()); !strings.Contains(view, "re-running plan (main.tf changed)") {
+		t.Errorf("status should show the re-run:\n%s", view)
+	}
+
+	updated, _ = m.Update(PlanRefreshedMsg{Plan: current, At: time.Date(2026, 1, 2, 10, 5, 0, 0, time.UTC)})
+	m = updated.(Model)
+	idx := m.currentResourceIndex()
+	if got := m.plan.Resources[idx].Address; got != "aws_iam_role.app" {
+		t.Errorf("cursor moved to %s", got)
+	}
+	if !m.expanded[idx] || len(m.expanded) != 1 {
+		t.Errorf("expanded = %v, want only resource %d", m.expanded, idx)
+	}
+	view := stripRenderANSI(m.View())
+	if !strings.Contains(view, "last run 10:05:00") || !strings.Contains(view, "aws_sns_topic.new") {
+		t.Errorf("view should show the refreshed plan:\n%s", view)
+	}
+
+	updated, _ = m.Update(PlanRefreshedMsg{Err: errors.New("Error: Invalid reference\nmore detail"), At: time.Date(2026, 1, 2, 10, 6, 0, 0, time.UTC)})
+	m = updated.(Model)
+	view = stripRenderANSI(m.View())
+	if !strings.Contains(view, "plan failed at 10:06:00: Error: Invalid reference") || !strings.Contains(view, "aws_sns_topic.new") {
+		t.Errorf("a failed run should keep the last plan and show the error:\n%s", view)
+	}
+}
+
+func TestPlanRefreshRecomputesAttrFilter(t *testing.T) {
+	previous, current := compareTestPlans(t)
+	m := NewModel(previous, "test")
+	m.attrFilter = "delay_seconds"
+	m.attrFilterResources = map[int]bool{3: true}
+
+	m.replacePlan(current)
+	var got []string
+	for _, idx := range m.filteredResources() {
+		got = append(got, m.plan.Resources[idx].Address)
+	}
+	if strings.Join(got, ",") != "aws_sqs_queue.q" {
+		t.Errorf("filtered = %v, want aws_sqs_queue.q", got)
+	}
+}
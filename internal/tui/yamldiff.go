@@ -0,0 +1,496 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlIdentityKeys are the fields used to match list items across versions,
+// in order of preference, e.g. containers[name=app] or ports[containerPort=80].
+var yamlIdentityKeys = []string{"name", "key", "id", "mountPath", "containerPort", "port"}
+
+// parseYAMLDocuments parses a (possibly multi-document) YAML string. It only
+// succeeds when every non-empty document is a mapping or sequence, so shell
+// scripts and plain text, which YAML accepts as scalars, are rejected.
+func parseYAMLDocuments(content string) ([]*yaml.Node, bool) {
+	dec := yaml.NewDecoder(strings.NewReader(content))
+	var docs []*yaml.Node
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, false
+		}
+		if len(doc.Content) == 0 {
+			continue
+		}
+		root := resolveYAMLNode(doc.Content[0])
+		if root.Kind == yaml.ScalarNode && root.Tag == "!!null" {
+			continue
+		}
+		if root.Kind != yaml.MappingNode && root.Kind != yaml.SequenceNode {
+			return nil, false
+		}
+		docs = append(docs, root)
+	}
+	return docs, len(docs) > 0
+}
+
+func resolveYAMLNode(n *yaml.Node) *yaml.Node {
+	for n != nil && (n.Kind == yaml.AliasNode || n.Kind == yaml.DocumentNode) {
+		if n.Kind == yaml.AliasNode {
+			n = n.Alias
+			continue
+		}
+		if len(n.Content) == 0 {
+			return n
+		}
+		n = n.Content[0]
+	}
+	return n
+}
+
+// yamlStructuralDiff compares two YAML document streams and returns one line
+// per leaf value, labelled with its path (spec.template.spec.containers[name=app].image).
+// Map keys are matched by name and list items by an identity field when one
+// exists, so reordering alone produces no changes. Lines follow the new
+// document's order, ready for ContextDiff and renderDiffLines.
+func yamlStructuralDiff(oldDocs, newDocs []*yaml.Node) []DiffLine {
+	var d yamlDiffer
+	multi := len(oldDocs) > 1 || len(newDocs) > 1
+	oldLabels, oldOk := yamlDocLabels(oldDocs)
+	newLabels, newOk := yamlDocLabels(newDocs)
+
+	if !oldOk || !newOk {
+		for i := 0; i < max(len(oldDocs), len(newDocs)); i++ {
+			if multi {
+				d.docPrefix = fmt.Sprintf("[doc %d] ", i+1)
+			}
+			switch {
+			case i >= len(newDocs):
+				d.emit(DiffDelete, "", oldDocs[i])
+			case i >= len(oldDocs):
+				d.emit(DiffInsert, "", newDocs[i])
+			default:
+				d.compare("", oldDocs[i], newDocs[i])
+			}
+		}
+		return d.out
+	}
+
+	mergeOrdered(oldLabels, newLabels, func(label string, oldIdx, newIdx int) {
+		if multi {
+			d.docPrefix = "[" + label + "] "
+		}
+		switch {
+		case newIdx < 0:
+			d.emit(DiffDelete, "", oldDocs[oldIdx])
+		case oldIdx < 0:
+			d.emit(DiffInsert, "", newDocs[newIdx])
+		default:
+			d.compare("", oldDocs[oldIdx], newDocs[newIdx])
+		}
+	})
+	return d.out
+}
+
+// yamlDocLabels identifies Kubernetes-style documents as Kind/name (or
+// Kind/namespace/name). It returns false if any document lacks a unique label.
+func yamlDocLabels(docs []*yaml.Node) ([]string, bool) {
+	labels := make([]string, len(docs))
+	seen := make(map[string]bool, len(docs))
+	for i, doc := range docs {
+		kind := yamlScalarField(doc, "kind")
+		metadata := yamlMapField(doc, "metadata")
+		name := yamlScalarField(metadata, "name")
+		if kind == "" || name == "" {
+			return nil, false
+		}
+		label := kind + "/" + name
+		if ns := yamlScalarField(metadata, "namespace"); ns != "" {
+			label = kind + "/" + ns + "/" + name
+		}
+		if seen[label] {
+			return nil, false
+		}
+		seen[label] = true
+		labels[i] = label
+	}
+	return labels, true
+}
+
+func yamlMapField(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return resolveYAMLNode(n.Content[i+1])
+		}
+	}
+	return nil
+}
+
+func yamlScalarField(n *yaml.Node, key string) string {
+	v := yamlMapField(n, key)
+	if v == nil || v.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return v.Value
+}
+
+// mergeOrdered walks two ordered key lists and calls fn once per key in the
+// new order, with keys only present in the old list placed where they used
+// to be. Missing sides are reported as index -1.
+func mergeOrdered(oldKeys, newKeys []string, fn func(key string, oldIdx, newIdx int)) {
+	oldPos := make(map[string]int, len(oldKeys))
+	for i, k := range oldKeys {
+		oldPos[k] = i
+	}
+	newPos := make(map[string]int, len(newKeys))
+	for i, k := range newKeys {
+		newPos[k] = i
+	}
+
+	oi := 0
+	flushRemoved := func(upTo int) {
+		for ; oi < upTo; oi++ {
+			if _, ok := newPos[oldKeys[oi]]; !ok {
+				fn(oldKeys[oi], oi, -1)
+			}
+		}
+	}
+	for ni, k := range newKeys {
+		oldIdx, ok := oldPos[k]
+		if !ok {
+			fn(k, -1, ni)
+			continue
+		}
+		if oldIdx >= oi {
+			flushRemoved(oldIdx)
+			oi = oldIdx + 1
+		}
+		fn(k, oldIdx, ni)
+	}
+	flushRemoved(len(oldKeys))
+}
+
+type yamlDiffer struct {
+	out       []DiffLine
+	docPrefix string
+}
+
+func (d *yamlDiffer) line(op DiffOp, text string) {
+	d.out = append(d.out, DiffLine{Op: op, Text: text})
+}
+
+func (d *yamlDiffer) joinKey(path, key string) string {
+	if strings.ContainsAny(key, ". []") {
+		key = fmt.Sprintf("[%q]", key)
+		if path == "" {
+			return d.docPrefix + key
+		}
+		return path + key
+	}
+	if path == "" {
+		return d.docPrefix + key
+	}
+	return path + "." + key
+}
+
+func (d *yamlDiffer) joinIndex(path, index string) string {
+	if path == "" {
+		return d.docPrefix + "[" + index + "]"
+	}
+	return path + "[" + index + "]"
+}
+
+func (d *yamlDiffer) leafPath(path string) string {
+	if path == "" {
+		return d.docPrefix + "."
+	}
+	return path
+}
+
+// emit writes every leaf of n under path with the same op.
+func (d *yamlDiffer) emit(op DiffOp, path string, n *yaml.Node) {
+	n = resolveYAMLNode(n)
+	switch n.Kind {
+	case yaml.MappingNode:
+		if len(n.Content) == 0 {
+			d.line(op, d.leafPath(path)+": {}")
+			return
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			d.emit(op, d.joinKey(path, n.Content[i].Value), n.Content[i+1])
+		}
+	case yaml.SequenceNode:
+		if len(n.Content) == 0 {
+			d.line(op, d.leafPath(path)+": []")
+			return
+		}
+		ids := yamlItemIDs(n.Content, yamlIdentityKey(n.Content, nil))
+		for i, item := range n.Content {
+			d.emit(op, d.joinIndex(path, ids[i]), item)
+		}
+	default:
+		if strings.Contains(n.Value, "\n") {
+			d.line(op, d.leafPath(path)+": |")
+			for _, l := range strings.Split(strings.TrimSuffix(n.Value, "\n"), "\n") {
+				d.line(op, "  "+l)
+			}
+			return
+		}
+		d.line(op, d.leafPath(path)+": "+yamlScalarText(n))
+	}
+}
+
+func (d *yamlDiffer) compare(path string, oldNode, newNode *yaml.Node) {
+	oldNode, newNode = resolveYAMLNode(oldNode), resolveYAMLNode(newNode)
+	if canonicalYAML(oldNode) == canonicalYAML(newNode) {
+		d.emit(DiffEqual, path, newNode)
+		return
+	}
+
+	switch {
+	case oldNode.Kind == yaml.MappingNode && newNode.Kind == yaml.MappingNode:
+		d.compareMaps(path, oldNode, newNode)
+		return
+	case oldNode.Kind == yaml.SequenceNode && newNode.Kind == yaml.SequenceNode:
+		d.compareSeqs(path, oldNode.Content, newNode.Content)
+		return
+	case oldNode.Kind == yaml.ScalarNode && newNode.Kind == yaml.ScalarNode &&
+		(strings.Contains(oldNode.Value, "\n") || strings.Contains(newNode.Value, "\n")):
+		d.line(DiffEqual, d.leafPath(path)+": |")
+		oldLines := strings.Split(strings.TrimSuffix(oldNode.Value, "\n"), "\n")
+		newLines := strings.Split(strings.TrimSuffix(newNode.Value, "\n"), "\n")
+		for _, l := range ComputeDiff(oldLines, newLines) {
+			d.line(l.Op, "  "+l.Text)
+		}
+		return
+	}
+
+	d.emit(DiffDelete, path, oldNode)
+	d.emit(DiffInsert, path, newNode)
+}
+
+func (d *yamlDiffer) compareMaps(path string, oldNode, newNode *yaml.Node) {
+	keys := func(n *yaml.Node) ([]string, map[string]*yaml.Node) {
+		var order []string
+		values := make(map[string]*yaml.Node, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k := n.Content[i].Value
+			if _, dup := values[k]; !dup {
+				order = append(order, k)
+			}
+			values[k] = n.Content[i+1]
+		}
+		return order, values
+	}
+	oldKeys, oldValues := keys(oldNode)
+	newKeys, newValues := keys(newNode)
+
+	mergeOrdered(oldKeys, newKeys, func(k string, oldIdx, newIdx int) {
+		childPath := d.joinKey(path, k)
+		switch {
+		case newIdx < 0:
+			d.emit(DiffDelete, childPath, oldValues[k])
+		case oldIdx < 0:
+			d.emit(DiffInsert, childPath, newValues[k])
+		default:
+			d.compare(childPath, oldValues[k], newValues[k])
+		}
+	})
+}
+
+// compareSeqs matches list items by identity field when every item has a
+// unique one, and otherwise aligns them by content with a Myers diff,
+// pairing changed runs positionally.
+func (d *yamlDiffer) compareSeqs(path string, oldItems, newItems []*yaml.Node) {
+	if idKey := yamlIdentityKey(oldItems, newItems); idKey != "" {
+		oldIDs := yamlItemIDs(oldItems, idKey)
+		newIDs := yamlItemIDs(newItems, idKey)
+		mergeOrdered(oldIDs, newIDs, func(id string, oldIdx, newIdx int) {
+			childPath := d.joinIndex(path, id)
+			switch {
+			case newIdx < 0:
+				d.emit(DiffDelete, childPath, oldItems[oldIdx])
+			case oldIdx < 0:
+				d.emit(DiffInsert, childPath, newItems[newIdx])
+			default:
+				d.compare(childPath, oldItems[oldIdx], newItems[newIdx])
+			}
+		})
+		return
+	}
+
+	encode := func(items []*yaml.Node) []string {
+		out := make([]string, len(items))
+		for i, item := range items {
+			out[i] = canonicalYAML(resolveYAMLNode(item))
+		}
+		return out
+	}
+	ops := computeDiffWith(DiffMyers, encode(oldItems), encode(newItems))
+
+	i, j := 0, 0
+	for k := 0; k < len(ops); {
+		if ops[k].Op == DiffEqual {
+			d.emit(DiffEqual, d.joinIndex(path, fmt.Sprint(j)), newItems[j])
+			i++
+			j++
+			k++
+			continue
+		}
+		delStart, insStart := i, j
+		for k < len(ops) && ops[k].Op != DiffEqual {
+			if ops[k].Op == DiffDelete {
+				i++
+			} else {
+				j++
+			}
+			k++
+		}
+		paired := min(i-delStart, j-insStart)
+		for p := 0; p < paired; p++ {
+			d.compare(d.joinIndex(path, fmt.Sprint(insStart+p)), oldItems[delStart+p], newItems[insStart+p])
+		}
+		for p := delStart + paired; p < i; p++ {
+			d.emit(DiffDelete, d.joinIndex(path, fmt.Sprint(p)), oldItems[p])
+		}
+		for p := insStart + paired; p < j; p++ {
+			d.emit(DiffInsert, d.joinIndex(path, fmt.Sprint(p)), newItems[p])
+		}
+	}
+}
+
+// yamlIdentityKey returns the first identity field that every item in both
+// lists has as a scalar, with unique values within each list.
+func yamlIdentityKey(oldItems, newItems []*yaml.Node) string {
+	if len(oldItems)+len(newItems) == 0 {
+		return ""
+	}
+	for _, key := range yamlIdentityKeys {
+		ok := true
+		for _, items := range [][]*yaml.Node{oldItems, newItems} {
+			seen := make(map[string]bool, len(items))
+			for _, item := range items {
+				v := yamlScalarField(resolveYAMLNode(item), key)
+				if v == "" || seen[v] {
+					ok = false
+					break
+				}
+				seen[v] = true
+			}
+			if !ok {
+				break
+			}
+		}
+		if ok {
+			return key
+		}
+	}
+	return ""
+}
+
+// yamlItemIDs labels list items as key=value when idKey is set, or by index.
+func yamlItemIDs(items []*yaml.Node, idKey string) []string {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		if idKey == "" {
+			ids[i] = fmt.Sprint(i)
+			continue
+		}
+		ids[i] = idKey + "=" + yamlScalarField(resolveYAMLNode(item), idKey)
+	}
+	return ids
+}
+
+func yamlScalarText(n *yaml.Node) string {
+	switch {
+	case n.Tag == "!!null":
+		return "null"
+	case n.Value == "":
+		return `""`
+	}
+	return n.Value
+}
+
+// canonicalYAML encodes a node with sorted map keys so structurally equal
+// values compare equal regardless of key order or formatting.
+func canonicalYAML(n *yaml.Node) string {
+	var b strings.Builder
+	writeCanonicalYAML(&b, n)
+	return b.String()
+}
+
+func writeCanonicalYAML(b *strings.Builder, n *yaml.Node) {
+	n = resolveYAMLNode(n)
+	switch n.Kind {
+	case yaml.MappingNode:
+		pairs := make([][2]string, 0, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			pairs = append(pairs, [2]string{n.Content[i].Value, canonicalYAML(n.Content[i+1])})
+		}
+		sort.Slice(pairs, func(i, j int) bool { return pairs[i][0] < pairs[j][0] })
+		b.WriteString("{")
+		for _, p := range pairs {
+			fmt.Fprintf(b, "%q:%s,", p[0], p[1])
+		}
+		b.WriteString("}")
+	case yaml.SequenceNode:
+		b.WriteString("[")
+		for _, item := range n.Content {
+			writeCanonicalYAML(b, item)
+			b.WriteString(",")
+		}
+		b.WriteString("]")
+	default:
+		fmt.Fprintf(b, "%s:%q", n.ShortTag(), n.Value)
+	}
+}
+
+// markYAMLHeredocPair attaches a path-based structural diff to a heredoc pair
+// fold when both heredoc bodies parse as YAML documents.
+func markYAMLHeredocPair(block *foldBlock, lines []string) {
+	oldContent := extractHeredocContent(lines[block.Start+1 : block.OldEnd-1])
+	newContent := extractHeredocContent(lines[block.AddStart+1 : block.End-1])
+	oldDocs, oldOk := parseYAMLDocuments(dedentLines(oldContent))
+	newDocs, newOk := parseYAMLDocuments(dedentLines(newContent))
+	if oldOk && newOk {
+		block.PairDiff = yamlStructuralDiff(oldDocs, newDocs)
+		block.PairDiffLabel = "yaml diff"
+	}
+}
+
+// dedentLines strips the common leading indentation Terraform adds to
+// heredoc bodies so the content parses as top-level YAML.
+func dedentLines(lines []string) string {
+	common := -1
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		indent := len(l) - len(strings.TrimLeft(l, " "))
+		if common < 0 || indent < common {
+			common = indent
+		}
+	}
+	var b strings.Builder
+	for _, l := range lines {
+		if len(l) >= common && common > 0 {
+			l = l[common:]
+		}
+		b.WriteString(l)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,139 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CaptShanks/terraprism/internal/parser"
+)
+
+func yamlDiffForTest(t *testing.T, oldYAML, newYAML string) []DiffLine {
+	t.Helper()
+	oldDocs, ok := parseYAMLDocuments(oldYAML)
+	if !ok {
+		t.Fatalf("failed to parse old YAML:\n%s", oldYAML)
+	}
+	newDocs, ok := parseYAMLDocuments(newYAML)
+	if !ok {
+		t.Fatalf("failed to parse new YAML:\n%s", newYAML)
+	}
+	return yamlStructuralDiff(oldDocs, newDocs)
+}
+
+func changedYAMLLines(diff []DiffLine) (removed, added []string) {
+	for _, d := range diff {
+		switch d.Op {
+		case DiffDelete:
+			removed = append(removed, d.Text)
+		case DiffInsert:
+			added = append(added, d.Text)
+		}
+	}
+	return removed, added
+}
+
+func TestYAMLStructuralDiffMatchesListItemsByName(t *testing.T) {
+	oldYAML := `
+spec:
+  template:
+    spec:
+      containers:
+      - name: sidecar
+        image: proxy:1
+      - name: app
+        image: app:1
+`
+	newYAML := `
+spec:
+  template:
+    spec:
+      containers:
+      - image: app:2
+        name: app
+      - name: sidecar
+        image: proxy:1
+`
+	removed, added := changedYAMLLines(yamlDiffForTest(t, oldYAML, newYAML))
+	wantRemoved := []string{"spec.template.spec.containers[name=app].image: app:1"}
+	wantAdded := []string{"spec.template.spec.containers[name=app].image: app:2"}
+	if strings.Join(removed, "|") != strings.Join(wantRemoved, "|") || strings.Join(added, "|") != strings.Join(wantAdded, "|") {
+		t.Fatalf("removed = %q, added = %q; want %q, %q", removed, added, wantRemoved, wantAdded)
+	}
+}
+
+func TestYAMLStructuralDiffIgnoresKeyOrder(t *testing.T) {
+	diff := yamlDiffForTest(t, "a: 1\nb:\n  c: 2\n  d: 3\n", "b:\n  d: 3\n  c: 2\na: 1\n")
+	if got := countEdits(diff); got != 0 {
+		t.Fatalf("expected reordered keys to produce no changes, got %+v", diff)
+	}
+}
+
+func TestYAMLStructuralDiffMultiDocument(t *testing.T) {
+	oldYAML := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  level: info
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+  - port: 80
+`
+	newYAML := `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+  - port: 80
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  level: debug
+`
+	removed, added := changedYAMLLines(yamlDiffForTest(t, oldYAML, newYAML))
+	if strings.Join(removed, "|") != "[ConfigMap/settings] data.level: info" {
+		t.Errorf("removed = %q", removed)
+	}
+	if strings.Join(added, "|") != "[ConfigMap/settings] data.level: debug" {
+		t.Errorf("added = %q", added)
+	}
+}
+
+func TestParseYAMLDocumentsRejectsPlainText(t *testing.T) {
+	if _, ok := parseYAMLDocuments("#!/bin/bash\necho hello\n"); ok {
+		t.Fatal("expected a shell script not to be treated as YAML")
+	}
+}
+
+func TestYAMLHeredocPairRendersPathDiff(t *testing.T) {
+	r := parser.Resource{Address: "helm_release.chart", Action: parser.ActionUpdate}
+	lines := []string{
+		`      ~ values = [`,
+		`          - <<-EOT`,
+		`              labels:`,
+		`                app.kubernetes.io/name: web`,
+		`              replicaCount: 2`,
+		`            EOT,`,
+		`          + <<-EOT`,
+		`              replicaCount: 3`,
+		`              labels:`,
+		`                app.kubernetes.io/name: web`,
+		`            EOT,`,
+		`        ]`,
+	}
+	got := renderExpandedWithDiffContextForTest(r, lines, 3)
+	for _, want := range []string{`┄┄┄ yaml diff ┄┄┄`, `- replicaCount: 2`, `+ replicaCount: 3`, `labels["app.kubernetes.io/name"]: web`} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("rendered YAML diff missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+// Package watch polls a Terraform working directory for changes to its
+// configuration, variable files and local module sources.
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultInterval is how often the watched files are polled
+	DefaultInterval = 500 * time.Millisecond
+	// DefaultDebounce is how long the files must stay unchanged before a
+	// burst of changes is reported
+	DefaultDebounce = time.Second
+)
+
+// watchedSuffixes are the file types that affect a plan.
+var watchedSuffixes = []string{".tf", ".tf.json", ".tfvars", ".tfvars.json", ".tftpl"}
+
+// localSourceRegex matches local module sources, e.g. source = "./modules/vpc".
+var localSourceRegex = regexp.MustCompile(`(?m)^\s*source\s*=\s*"(\.\.?/[^"]*)"`)
+
+// Watcher reports changes to the Terraform files of a working directory and
+// of the local modules it calls, directly or through other local modules.
+type Watcher struct {
+	Dir      string
+	Interval time.Duration
+	Debounce time.Duration
+}
+
+// New returns a Watcher for dir with the default interval and debounce.
+func New(dir string) *Watcher {
+	return &Watcher{Dir: dir, Interval: DefaultInterval, Debounce: DefaultDebounce}
+}
+
+// fileState identifies a version of a file.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Run polls until done is closed. After files change and then stay
+// unchanged for the debounce period, it sends the changed paths (relative to
+// Dir where possible). The channel is closed when Run returns.
+func (w *Watcher) Run(done <-chan struct{}) <-chan []string {
+	changes := make(chan []string)
+	go func() {
+		defer close(changes)
+		ticker := time.NewTicker(w.Interval)
+		defer ticker.Stop()
+
+		prev := w.snapshot()
+		pending := make(map[string]bool)
+		var lastChange time.Time
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				next := w.snapshot()
+				if changed := diffSnapshots(prev, next); len(changed) > 0 {
+					for _, path := range changed {
+						pending[path] = true
+					}
+					lastChange = now
+				}
+				prev = next
+				if len(pending) == 0 || now.Sub(lastChange) < w.Debounce {
+					continue
+				}
+				paths := make([]string, 0, len(pending))
+				for path := range pending {
+					paths = append(paths, w.relative(path))
+				}
+				sort.Strings(paths)
+				pending = make(map[string]bool)
+				select {
+				case changes <- paths:
+				case <-done:
+					return
+				}
+			}
+		}
+	}()
+	return changes
+}
+
+// snapshot returns the state of every watched file.
+func (w *Watcher) snapshot() map[string]fileState {
+	files := make(map[string]fileState)
+	seen := make(map[string]bool)
+	queue := []string{filepath.Clean(w.Dir)}
+	for len(queue) > 0 {
+		dir := queue[0]
+		queue = queue[1:]
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !isWatchedFile(entry.Name()) {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			files[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+			if strings.HasSuffix(path, ".tf") {
+				queue = append(queue, localModuleDirs(path)...)
+			}
+		}
+	}
+	return files
+}
+
+// localModuleDirs returns the directories of the local module sources
+// referenced by a .tf file.
+func localModuleDirs(path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var dirs []string
+	for _, match := range localSourceRegex.FindAllStringSubmatch(string(data), -1) {
+		dirs = append(dirs, filepath.Clean(filepath.Join(filepath.Dir(path), match[1])))
+	}
+	return dirs
+}
+
+func isWatchedFile(name string) bool {
+	for _, suffix := range watchedSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// diffSnapshots returns the files added, removed or modified between two
+// snapshots.
+func diffSnapshots(prev, next map[string]fileState) []string {
+	var changed []string
+	for path, state := range next {
+		if old, ok := prev[path]; !ok || !old.modTime.Equal(state.modTime) || old.size != state.size {
+			changed = append(changed, path)
+		}
+	}
+	for path := range prev {
+		if _, ok := next[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	return changed
+}
+
+func (w *Watcher) relative(path string) string {
+	if rel, err := filepath.Rel(w.Dir, path); err == nil {
+		return rel
+	}
+	return path
+}
@@ -0,0 +1,76 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSnapshotFollowsLocalModules(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "env")
+	writeFile(t, filepath.Join(dir, "main.tf"), "module \"vpc\" {\n  source = \"../modules/vpc\"\n}\nmodule \"remote\" {\n  source = \"hashicorp/consul/aws\"\n}\n")
+	writeFile(t, filepath.Join(dir, "prod.tfvars"), "env = \"prod\"\n")
+	writeFile(t, filepath.Join(dir, "README.md"), "ignored\n")
+	writeFile(t, filepath.Join(dir, ".terraform", "modules", "x.tf"), "ignored\n")
+	writeFile(t, filepath.Join(root, "modules", "vpc", "main.tf"), "module \"subnets\" {\n  source = \"./subnets\"\n}\n")
+	writeFile(t, filepath.Join(root, "modules", "vpc", "subnets", "main.tf"), "")
+	writeFile(t, filepath.Join(root, "modules", "vpc", "user_data.tftpl"), "")
+	writeFile(t, filepath.Join(root, "modules", "unused", "main.tf"), "")
+
+	w := New(dir)
+	var got []string
+	for path := range w.snapshot() {
+		got = append(got, w.relative(path))
+	}
+	want := map[string]bool{
+		"main.tf":                        true,
+		"prod.tfvars":                    true,
+		"../modules/vpc/main.tf":         true,
+		"../modules/vpc/user_data.tftpl": true,
+		"../modules/vpc/subnets/main.tf": true,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("watched %v, want %v", got, want)
+	}
+	for _, path := range got {
+		if !want[filepath.ToSlash(path)] {
+			t.Errorf("unexpected watched file %s", path)
+		}
+	}
+}
+
+func TestRunDebouncesChanges(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "main.tf"), "")
+	w := &Watcher{Dir: dir, Interval: 10 * time.Millisecond, Debounce: 50 * time.Millisecond}
+	done := make(chan struct{})
+	defer close(done)
+	changes := w.Run(done)
+
+	time.Sleep(30 * time.Millisecond)
+	writeFile(t, filepath.Join(dir, "main.tf"), "resource \"null_resource\" \"a\" {}\n")
+	time.Sleep(20 * time.Millisecond)
+	writeFile(t, filepath.Join(dir, "vars.tfvars"), "a = 1\n")
+
+	select {
+	case paths := <-changes:
+		if want := []string{"main.tf", "vars.tfvars"}; !reflect.DeepEqual(paths, want) {
+			t.Errorf("changes = %v, want %v in one batch", paths, want)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no change reported")
+	}
+}